@@ -1,11 +1,31 @@
 package commands
 
 import (
+	"errors"
 	"fmt"
+	"io"
+	"io/fs"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/barisercan/arcsii/internal/parser"
 	"github.com/barisercan/arcsii/internal/renderer"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// exportViews are the views /export can save
+var exportViews = []string{"stats", "tree", "uml", "deps", "funcs", "changes"}
+
+// Sequence diagrams follow calls one level by default; the cap keeps
+// heavily connected graphs readable
+const (
+	defaultSequenceDepth = 1
+	maxSequenceDepth     = 5
 )
 
 type Command struct {
@@ -13,30 +33,374 @@ type Command struct {
 	Aliases     []string
 	Description string
 	Handler     func(args []string) (string, string)
+	WidthAware  bool // Output depends on SetWidth, so it's rerun on resize
+	JSON        bool // Handler renders JSON when an argument is json or --json
+
+	// Stream, when set, writes the output to w as it renders and returns
+	// the status; Handler then wraps it
+	Stream func(w io.Writer, args []string) string
 }
 
 type Registry struct {
 	targetDir string
+	focus     string // Subdirectory views are scoped to, relative to targetDir
 	commands  map[string]*Command
+	order     []*Command        // Registered and described commands, for help
+	shortcuts map[string]string // Command name to the key that runs it, for help
+	width     int               // Output width for width-aware views
+
+	// Levels /tree draws by default, from ARCSII_TREE_DEPTH; 0 draws all
+	treeDepth    int
+	treeDepthErr error
 }
 
 func NewRegistry(targetDir string) *Registry {
 	r := &Registry{
 		targetDir: targetDir,
 		commands:  make(map[string]*Command),
+		shortcuts: make(map[string]string),
 	}
+	r.treeDepth, r.treeDepthErr = treeDepthFromEnv()
 	r.registerCommands()
 	return r
 }
 
+// SetTargetDir points every command at a new project root, clearing
+// any focus
+func (r *Registry) SetTargetDir(dir string) {
+	r.targetDir = dir
+	r.focus = ""
+}
+
+// Focus returns the subdirectory views are scoped to, or ""
+func (r *Registry) Focus() string {
+	return r.focus
+}
+
+// root is the directory views scan: the focused subdirectory if one is
+// set, otherwise the project root. Commands that need go.mod keep using
+// targetDir.
+func (r *Registry) root() string {
+	if r.focus == "" {
+		return r.targetDir
+	}
+	return filepath.Join(r.targetDir, r.focus)
+}
+
+// workspace returns the member directories when the project is a
+// go.work or JS workspace and no focus is set
+func (r *Registry) workspace() []string {
+	if r.focus != "" {
+		return nil
+	}
+	members, err := parser.ParseWorkspace(r.targetDir)
+	if err != nil {
+		return nil
+	}
+	return members
+}
+
+// resolveFocus maps a /focus argument to a directory relative to
+// targetDir. An exact relative path wins; otherwise a unique directory
+// whose path ends in the argument is used, so "/focus parser" finds
+// internal/parser.
+func (r *Registry) resolveFocus(arg string) (string, error) {
+	arg = filepath.Clean(strings.Trim(arg, "/"))
+	if info, err := os.Stat(filepath.Join(r.targetDir, arg)); err == nil && info.IsDir() {
+		return arg, nil
+	}
+
+	var matches []string
+	suffix := string(filepath.Separator) + arg
+	filepath.Walk(r.targetDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || !info.IsDir() {
+			return nil
+		}
+		name := info.Name()
+		if path != r.targetDir && (strings.HasPrefix(name, ".") || name == "node_modules" || name == "vendor") {
+			return filepath.SkipDir
+		}
+		if strings.HasSuffix(path, suffix) {
+			rel, _ := filepath.Rel(r.targetDir, path)
+			matches = append(matches, rel)
+		}
+		return nil
+	})
+
+	switch len(matches) {
+	case 0:
+		return "", fmt.Errorf("no directory %q in %s", arg, r.targetDir)
+	case 1:
+		return matches[0], nil
+	}
+	return "", fmt.Errorf("%q is ambiguous: %s", arg, strings.Join(matches, ", "))
+}
+
+// callList answers /calls, /callers and /callees. A name that matches
+// functions in several packages lists them so it can be qualified.
+func (r *Registry) callList(command string, args []string) (string, string) {
+	if len(args) == 0 {
+		return fmt.Sprintf("Usage: /%s <function>\n\nExamples: /%[1]s ParseStats, /%[1]s Registry.Execute", command), "Missing function name"
+	}
+
+	name := args[0]
+	graph := parser.ParseCallGraph(r.targetDir)
+	if targets := parser.CallTargets(graph, name); len(targets) > 1 {
+		return fmt.Sprintf("Error: %q is ambiguous; it matches:\n\n  %s\n\nQualify it, e.g. /%s %s", name, strings.Join(targets, "\n  "), command, targets[0]), "Ambiguous function name"
+	}
+
+	switch command {
+	case "callers":
+		callers := parser.CallersOf(graph, name)
+		return renderer.RenderCallers(name, callers), fmt.Sprintf("%d call sites of %s", len(callers), name)
+	case "callees":
+		callees := parser.CalleesOf(graph, name)
+		return renderer.RenderCallees(name, callees), fmt.Sprintf("%d calls from %s", len(callees), name)
+	}
+	callers := parser.CallersOf(graph, name)
+	callees := parser.CalleesOf(graph, name)
+	return renderer.RenderCalls(name, callers, callees), "Calls: " + name
+}
+
+// fileTree parses the tree /tree draws: the directory named by the first
+// argument unless it's "heat", else the project, split by workspace
+// module. The arguments left over are returned.
+func (r *Registry) fileTree(args []string) (*parser.FileNode, []string, error) {
+	if len(args) > 0 && args[0] != "heat" {
+		dir, err := r.resolveSubdir(args[0])
+		if err != nil {
+			return nil, nil, err
+		}
+		return parser.ParseFileTree(dir), args[1:], nil
+	}
+
+	tree := parser.ParseFileTree(r.root())
+	if members := r.workspace(); len(members) > 0 {
+		// One subtree per workspace module
+		tree.Children = nil
+		for _, member := range members {
+			sub := parser.ParseFileTree(filepath.Join(r.targetDir, member))
+			sub.Name = member
+			tree.Children = append(tree.Children, sub)
+		}
+	}
+	return tree, args, nil
+}
+
+// FileTree returns the tree for input if it asks for the plain /tree
+// view, optionally of a directory, for the UI to draw interactively,
+// with the depth below which it starts folded. It returns nil for
+// anything else, including heat, langs and json.
+func (r *Registry) FileTree(input string) (*parser.FileNode, int) {
+	parts := strings.Fields(strings.TrimPrefix(input, "/"))
+	if len(parts) == 0 {
+		return nil, 0
+	}
+	if cmd, ok := r.commands[strings.ToLower(parts[0])]; !ok || cmd.Name != "tree" {
+		return nil, 0
+	}
+	depth, args, err := r.treeDepthArg(parts[1:])
+	if err != nil || len(args) > 1 {
+		return nil, 0
+	}
+	if len(args) == 1 && slices.Contains([]string{"heat", "langs", "--langs", "json", "--json"}, args[0]) {
+		return nil, 0
+	}
+
+	tree, _, err := r.fileTree(args)
+	if err != nil {
+		return nil, 0
+	}
+	return tree, depth
+}
+
+// treeDepthFromEnv reads ARCSII_TREE_DEPTH, the levels /tree draws when
+// not given --depth
+func treeDepthFromEnv() (int, error) {
+	v := os.Getenv("ARCSII_TREE_DEPTH")
+	if v == "" {
+		return 0, nil
+	}
+	depth, err := strconv.Atoi(v)
+	if err != nil || depth < 0 {
+		return 0, fmt.Errorf("ignoring ARCSII_TREE_DEPTH=%q, want a number of levels", v)
+	}
+	return depth, nil
+}
+
+// treeDepthArg takes "--depth N" or "--depth=N" out of /tree's
+// arguments, returning the depth to draw and the other arguments.
+// Without the flag the depth is the configured default; 0 draws all.
+func (r *Registry) treeDepthArg(args []string) (int, []string, error) {
+	depth := r.treeDepth
+	var rest []string
+	for i := 0; i < len(args); i++ {
+		value, ok := strings.CutPrefix(args[i], "--depth=")
+		if args[i] == "--depth" {
+			if i+1 == len(args) {
+				return 0, nil, fmt.Errorf("--depth needs a number of levels")
+			}
+			value, ok = args[i+1], true
+			i++
+		}
+		if !ok {
+			rest = append(rest, args[i])
+			continue
+		}
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			return 0, nil, fmt.Errorf("invalid depth %q", value)
+		}
+		depth = n
+	}
+	return depth, rest, nil
+}
+
+// wantsJSON reports whether a view was asked for JSON output, as in
+// "/stats json" or "/file main.go --json"
+func wantsJSON(args []string) bool {
+	return slices.ContainsFunc(args, isJSONArg)
+}
+
+// isJSONArg reports whether arg asks for JSON output
+func isJSONArg(arg string) bool {
+	return arg == "json" || arg == "--json"
+}
+
+// plainText strips ANSI styling and the padding it leaves at line ends
+func plainText(content string) string {
+	lines := strings.Split(ansi.Strip(content), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " ")
+	}
+	return strings.TrimRight(strings.Join(lines, "\n"), "\n") + "\n"
+}
+
+// insideTarget reports whether path is the target directory or below it
+func (r *Registry) insideTarget(path string) bool {
+	base, err := filepath.Abs(r.targetDir)
+	if err != nil {
+		base = r.targetDir
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		abs = path
+	}
+	rel, err := filepath.Rel(base, abs)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// resolveFile resolves a file argument against the focus first, then the
+// project root, and makes sure it stays inside the target
+func (r *Registry) resolveFile(arg string) (string, error) {
+	path := arg
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(r.root(), arg)
+		if _, err := os.Stat(path); err != nil {
+			path = filepath.Join(r.targetDir, arg)
+		}
+	}
+	if !r.insideTarget(path) {
+		return "", fmt.Errorf("%s is outside %s", arg, r.targetDir)
+	}
+
+	info, err := os.Stat(path)
+	switch {
+	case err != nil:
+		return "", fmt.Errorf("no file %q in %s", arg, r.targetDir)
+	case info.IsDir():
+		return "", fmt.Errorf("%s is a directory; try /tree %[1]s", arg)
+	}
+	return path, nil
+}
+
+// resolveSubdir resolves a directory argument against the current view
+// root (the focus, if set) and makes sure it stays inside the target
+func (r *Registry) resolveSubdir(arg string) (string, error) {
+	dir := arg
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(r.root(), dir)
+	}
+	if !r.insideTarget(dir) {
+		return "", fmt.Errorf("%s is outside %s", arg, r.targetDir)
+	}
+
+	if err := parser.ValidateRoot(dir); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return "", fmt.Errorf("no directory %q in %s", arg, r.root())
+		}
+		return "", err
+	}
+	return dir, nil
+}
+
+// SetWidth sets the output width used by width-aware views
+func (r *Registry) SetWidth(width int) {
+	r.width = width
+}
+
+// Describe lists a command that's handled outside the registry (such as
+// the UI's /watch) on the welcome and help screens
+func (r *Registry) Describe(name, description string) {
+	r.order = append(r.order, &Command{Name: name, Description: description})
+}
+
+// Shortcut shows key beside the command name on the welcome and help
+// screens, for a key the UI binds to it
+func (r *Registry) Shortcut(name, key string) {
+	r.shortcuts[name] = key
+}
+
+// CompletionCandidates lists the commands whose name starts with prefix,
+// ignoring a leading slash and case, then aliases of other commands that
+// do. Each group is sorted. Commands handled outside the registry are
+// included.
+func (r *Registry) CompletionCandidates(prefix string) []string {
+	prefix = strings.ToLower(strings.TrimPrefix(prefix, "/"))
+
+	var names, aliases []string
+	for _, cmd := range r.order {
+		if strings.HasPrefix(cmd.Name, prefix) {
+			names = append(names, cmd.Name)
+			continue
+		}
+		for _, alias := range cmd.Aliases {
+			if strings.HasPrefix(alias, prefix) {
+				aliases = append(aliases, alias)
+			}
+		}
+	}
+	slices.Sort(names)
+	slices.Sort(aliases)
+	return append(names, aliases...)
+}
+
+// helpEntries returns every listed command, with /help last
+func (r *Registry) helpEntries() []renderer.CommandInfo {
+	var entries []renderer.CommandInfo
+	var help *Command
+	for _, cmd := range r.order {
+		if cmd.Name == "help" {
+			help = cmd
+			continue
+		}
+		entries = append(entries, renderer.CommandInfo{Name: cmd.Name, Description: cmd.Description, Shortcut: r.shortcuts[cmd.Name]})
+	}
+	if help != nil {
+		entries = append(entries, renderer.CommandInfo{Name: help.Name, Description: help.Description, Shortcut: r.shortcuts[help.Name]})
+	}
+	return entries
+}
+
 func (r *Registry) registerCommands() {
 	// Help command
 	r.register(&Command{
 		Name:        "help",
 		Aliases:     []string{"h", "?"},
 		Description: "Show available commands",
+		WidthAware:  true,
 		Handler: func(args []string) (string, string) {
-			return renderer.RenderHelp(), "Showing help"
+			return renderer.RenderHelp(r.helpEntries(), r.width), "Showing help"
 		},
 	})
 
@@ -45,9 +409,61 @@ func (r *Registry) registerCommands() {
 		Name:        "tree",
 		Aliases:     []string{"t", "files"},
 		Description: "Show file tree structure",
-		Handler: func(args []string) (string, string) {
-			tree := parser.ParseFileTree(r.targetDir)
-			return renderer.RenderTree(tree), "File tree"
+		JSON:        true,
+		Stream: func(w io.Writer, args []string) string {
+			usage := "Usage: /tree [dir] [--depth N] [langs] [heat [1h 1d 1w 30d]]"
+
+			depth, args, err := r.treeDepthArg(args)
+			if err != nil {
+				fmt.Fprintf(w, "Error: %v\n\n%s", err, usage)
+				return "Invalid depth"
+			}
+			status := "File tree"
+			if r.treeDepthErr != nil {
+				status += " · " + r.treeDepthErr.Error()
+			}
+
+			// "langs" may be combined with the other modes
+			langs, asJSON := false, false
+			var rest []string
+			for _, arg := range args {
+				if arg == "langs" || arg == "--langs" {
+					langs = true
+				} else if arg == "json" || arg == "--json" {
+					asJSON = true
+				} else {
+					rest = append(rest, arg)
+				}
+			}
+			args = rest
+
+			tree, args, err := r.fileTree(args)
+			if err != nil {
+				fmt.Fprintf(w, "Error: %v\n\n%s", err, usage)
+				return "invalid path"
+			}
+			if langs {
+				parser.AnnotateLanguages(tree)
+			}
+			if asJSON {
+				io.WriteString(w, renderer.RenderJSON(tree))
+				return "File tree (JSON)"
+			}
+
+			if len(args) > 0 && args[0] == "heat" {
+				thresholds, err := parseAges(args[1:])
+				if err != nil {
+					fmt.Fprintf(w, "Error: %v\n\n%s", err, usage)
+					return "Invalid thresholds"
+				}
+				renderer.RenderTreeHeatTo(w, tree, thresholds, depth)
+				return "File tree heatmap"
+			} else if len(args) > 0 {
+				fmt.Fprintf(w, "Error: unexpected argument %q\n\n%s", args[0], usage)
+				return "Invalid arguments"
+			}
+			renderer.RenderTreeTo(w, tree, depth)
+			return status
 		},
 	})
 
@@ -56,13 +472,20 @@ func (r *Registry) registerCommands() {
 		Name:        "uml",
 		Aliases:     []string{"class", "classes"},
 		Description: "Show UML class diagram",
-		Handler: func(args []string) (string, string) {
-			// Try multi-language parser first, fall back to Go parser
-			classes := parser.ParseClassesMultiLang(r.targetDir)
-			if len(classes) == 0 {
-				classes = parser.ParseClasses(r.targetDir)
+		JSON:        true,
+		Stream: func(w io.Writer, args []string) string {
+			classes, ifaces := r.umlTypes()
+			if wantsJSON(args) {
+				// Interfaces have their own JSON under /interfaces
+				io.WriteString(w, renderer.RenderJSON(classes))
+				return "UML diagram (JSON)"
 			}
-			return renderer.RenderUML(classes), "UML diagram"
+			if len(args) > 0 && args[0] == "mermaid" {
+				io.WriteString(w, renderer.RenderUMLMermaid(classes, ifaces))
+				return "UML diagram (Mermaid)"
+			}
+			renderer.RenderUMLTo(w, classes, ifaces)
+			return "UML diagram"
 		},
 	})
 
@@ -71,13 +494,14 @@ func (r *Registry) registerCommands() {
 		Name:        "ascii",
 		Aliases:     []string{"art", "a"},
 		Description: "ASCII art architecture view",
+		WidthAware:  true,
 		Handler: func(args []string) (string, string) {
 			// Try multi-language parser first
-			structure := parser.ParseStructureMultiLang(r.targetDir)
+			structure := parser.ParseStructureMultiLang(r.root())
 			if len(structure.Modules) == 0 {
-				structure = parser.ParseStructure(r.targetDir)
+				structure = parser.ParseStructure(r.root())
 			}
-			return renderer.RenderASCIIArt(structure), "ASCII art view"
+			return renderer.RenderASCIIArt(structure, r.width), "ASCII art view"
 		},
 	})
 
@@ -86,13 +510,81 @@ func (r *Registry) registerCommands() {
 		Name:        "deps",
 		Aliases:     []string{"dependencies", "d"},
 		Description: "Show dependency graph",
+		JSON:        true,
+		Stream: func(w io.Writer, args []string) string {
+			if len(args) > 0 && (args[0] == "circular-files" || args[0] == "cycles") {
+				resolved := parser.ResolveImports(r.root(), r.dependencies())
+				cycles := parser.FindImportCycles(resolved)
+				io.WriteString(w, renderer.RenderImportCycles(cycles))
+				return fmt.Sprintf("%d file import cycle(s)", len(cycles))
+			}
+			if len(args) > 0 && args[0] == "dot" {
+				io.WriteString(w, renderer.RenderDepsDOT(r.dependencies()))
+				return "Dependency graph (DOT)"
+			}
+			if len(args) > 0 && (args[0] == "reverse" || args[0] == "rev") {
+				if len(args) < 2 {
+					io.WriteString(w, "Usage: /deps reverse <import path> [--json]\n\nExample: /deps reverse github.com/barisercan/arcsii/internal/parser")
+					return "Missing import path"
+				}
+				target, dependents := parser.IndexDependents(r.dependencies()).Of(args[1])
+				if wantsJSON(args[2:]) {
+					io.WriteString(w, renderer.RenderJSON(dependents))
+					return "Dependents (JSON)"
+				}
+				io.WriteString(w, renderer.RenderDependents(target, dependents))
+				return fmt.Sprintf("%d file(s) import %s", len(dependents), target)
+			}
+			if len(args) > 0 && args[0] == "unused" {
+				unused, err := parser.UnusedModules(r.targetDir)
+				if err != nil {
+					fmt.Fprintf(w, "Error: %v\n\n/deps unused needs a go.mod at the project root", err)
+					return "No go.mod"
+				}
+				if wantsJSON(args[1:]) {
+					io.WriteString(w, renderer.RenderJSON(unused))
+					return "Unused modules (JSON)"
+				}
+				mod, _ := parser.ParseGoMod(r.targetDir)
+				io.WriteString(w, renderer.RenderUnusedModules(unused, mod))
+				return fmt.Sprintf("%d unused module(s)", len(unused))
+			}
+			if wantsJSON(args) {
+				io.WriteString(w, renderer.RenderJSON(r.dependencies()))
+				return "Dependencies (JSON)"
+			}
+			renderer.RenderDepsTo(w, r.dependencies())
+			return "Dependencies"
+		},
+	})
+
+	// Line accounting
+	r.register(&Command{
+		Name:        "loc",
+		Aliases:     []string{"cloc", "lines"},
+		Description: "Count code, comment and blank lines per language",
+		JSON:        true,
 		Handler: func(args []string) (string, string) {
-			// Try multi-language parser first
-			deps := parser.ParseDependenciesMultiLang(r.targetDir)
-			if len(deps) == 0 {
-				deps = parser.ParseDependencies(r.targetDir)
+			report := parser.CountLOC(r.root())
+			if wantsJSON(args) {
+				return renderer.RenderJSON(report), "Lines of code (JSON)"
+			}
+			return renderer.RenderLOC(report), fmt.Sprintf("%d lines of code", report.Total.Code)
+		},
+	})
+
+	// Interface catalog
+	r.register(&Command{
+		Name:        "interfaces",
+		Aliases:     []string{"iface", "ifaces"},
+		Description: "List Go interfaces with their method signatures",
+		JSON:        true,
+		Handler: func(args []string) (string, string) {
+			_, ifaces := r.umlTypes()
+			if wantsJSON(args) {
+				return renderer.RenderJSON(ifaces), "Interfaces (JSON)"
 			}
-			return renderer.RenderDeps(deps), "Dependencies"
+			return renderer.RenderInterfaces(ifaces), fmt.Sprintf("%d interface(s)", len(ifaces))
 		},
 	})
 
@@ -101,9 +593,150 @@ func (r *Registry) registerCommands() {
 		Name:        "changes",
 		Aliases:     []string{"recent", "modified"},
 		Description: "Show recently modified files",
+		JSON:        true,
+		Stream: func(w io.Writer, args []string) string {
+			if len(args) > 0 && (args[0] == "diff-stat" || args[0] == "diffstat") {
+				stats, err := parser.WorkingTreeStat(r.root())
+				if errors.Is(err, parser.ErrNotARepo) {
+					fmt.Fprintf(w, "Error: %v\n\n/changes diff-stat needs a git repository; /changes lists files by modification time.", err)
+					return "Not a git repository"
+				} else if err != nil {
+					fmt.Fprintf(w, "Error: %v", err)
+					return "git diff failed"
+				}
+				if len(stats) == 0 {
+					io.WriteString(w, renderer.RenderDiffStat(stats))
+					return "Working tree clean"
+				}
+				io.WriteString(w, renderer.RenderDiffStat(stats))
+				return fmt.Sprintf("%d uncommitted changes", len(stats))
+			}
+
+			limit := parser.DefaultRecentChanges
+			asJSON := wantsJSON(args)
+			args = slices.DeleteFunc(slices.Clone(args), isJSONArg)
+			if len(args) > 0 {
+				n, err := strconv.Atoi(args[0])
+				if err != nil || n <= 0 {
+					fmt.Fprintf(w, "Error: invalid count %q\n\nUsage: /changes [count] | /changes diff-stat", args[0])
+					return "Invalid count"
+				}
+				limit = n
+			}
+
+			// The activity sparklines count every file, not just those listed
+			all := parser.ParseRecentChanges(r.root(), math.MaxInt)
+			changes := all[:min(limit, len(all))]
+			if asJSON {
+				io.WriteString(w, renderer.RenderJSON(changes))
+				return "Recent changes (JSON)"
+			}
+			renderer.RenderChangesTo(w, changes, all)
+			return "Recent changes"
+		},
+	})
+
+	// Uncommitted git changes
+	r.register(&Command{
+		Name:        "diff",
+		Aliases:     []string{"status", "st"},
+		Description: "Show uncommitted git changes (--name-only for paths)",
+		Handler: func(args []string) (string, string) {
+			nameOnly := false
+			for _, arg := range args {
+				switch arg {
+				case "--name-only", "name-only", "names":
+					nameOnly = true
+				default:
+					return fmt.Sprintf("Error: unknown option %q\n\nUsage: /diff [--name-only]", arg), "Invalid option"
+				}
+			}
+
+			stats, err := parser.GitStatus(r.root())
+			if errors.Is(err, parser.ErrNotARepo) {
+				return fmt.Sprintf("Error: %v\n\n/diff shows uncommitted changes and needs a git repository.", err), "Not a git repository"
+			} else if err != nil {
+				return fmt.Sprintf("Error: %v", err), "git diff failed"
+			}
+			if len(stats) == 0 {
+				return renderer.RenderGitStatus(stats, nameOnly), "Working tree clean"
+			}
+			return renderer.RenderGitStatus(stats, nameOnly), fmt.Sprintf("%d uncommitted changes", len(stats))
+		},
+	})
+
+	// Recent commits
+	r.register(&Command{
+		Name:        "log",
+		Aliases:     []string{"commits"},
+		Description: "Show recent git commits",
+		Handler: func(args []string) (string, string) {
+			count := parser.DefaultLogCount
+			if len(args) > 0 {
+				n, err := strconv.Atoi(args[0])
+				if err != nil || n <= 0 {
+					return fmt.Sprintf("Error: invalid count %q\n\nUsage: /log [count]", args[0]), "Invalid count"
+				}
+				count = n
+			}
+
+			commits, err := parser.GitLog(r.root(), count)
+			if errors.Is(err, parser.ErrNotARepo) {
+				return fmt.Sprintf("Error: %v\n\n/log shows commit history and needs a git repository.", err), "Not a git repository"
+			} else if err != nil {
+				return fmt.Sprintf("Error: %v", err), "git log failed"
+			}
+			if len(commits) == 0 {
+				return renderer.RenderGitLog(commits), "No commits yet"
+			}
+			return renderer.RenderGitLog(commits), fmt.Sprintf("Last %d commits", len(commits))
+		},
+	})
+
+	// Commit counts by author
+	r.register(&Command{
+		Name:        "history",
+		Aliases:     []string{"authors", "who"},
+		Description: "Show commit counts per author",
+		JSON:        true,
 		Handler: func(args []string) (string, string) {
-			changes := parser.ParseRecentChanges(r.targetDir)
-			return renderer.RenderChanges(changes), "Recent changes"
+			usage := "Usage: /history [--since 30d|2w|2024-01-02]\n\nExample: /history --since 30d"
+
+			var since, window string
+			for i := 0; i < len(args); i++ {
+				if args[i] == "json" || args[i] == "--json" {
+					continue
+				}
+				value, ok := strings.CutPrefix(args[i], "--since=")
+				if args[i] == "--since" && i+1 < len(args) {
+					value, ok = args[i+1], true
+					i++
+				}
+				if !ok {
+					return fmt.Sprintf("Error: unexpected argument %q\n\n%s", args[i], usage), "Invalid arguments"
+				}
+
+				// Ages are relative to now; dates go to git as given
+				if ages, err := parseAges([]string{value}); err == nil && len(ages) == 1 {
+					since = time.Now().Add(-ages[0]).Format(time.RFC3339)
+					window = "last " + value
+				} else if _, err := time.Parse(time.DateOnly, value); err == nil {
+					since, window = value, "since "+value
+				} else {
+					return fmt.Sprintf("Error: invalid --since %q, want an age such as 30d or a date such as 2024-01-02\n\n%s", value, usage), "Invalid window"
+				}
+			}
+
+			authors, err := parser.GitAuthors(r.root(), since)
+			if errors.Is(err, parser.ErrNotARepo) {
+				return fmt.Sprintf("Error: %v\n\n/history counts commits per author and needs a git repository.", err), "Not a git repository"
+			} else if err != nil {
+				return fmt.Sprintf("Error: %v", err), "git shortlog failed"
+			}
+			if wantsJSON(args) {
+				return renderer.RenderJSON(authors), "History (JSON)"
+			}
+			return renderer.RenderAuthors(authors, window), fmt.Sprintf("%d author(s)", len(authors))
 		},
 	})
 
@@ -112,41 +745,531 @@ func (r *Registry) registerCommands() {
 		Name:        "stats",
 		Aliases:     []string{"info", "summary"},
 		Description: "Show project statistics",
+		JSON:        true,
 		Handler: func(args []string) (string, string) {
-			stats := parser.ParseStats(r.targetDir)
+			stats := parser.ParseStats(r.root())
+			if wantsJSON(args) {
+				return renderer.RenderJSON(stats), "Project stats (JSON)"
+			}
+			if members := r.workspace(); len(members) > 0 {
+				var perModule []parser.ProjectStats
+				for _, member := range members {
+					perModule = append(perModule, parser.ParseStats(filepath.Join(r.targetDir, member)))
+				}
+				file := parser.WorkspaceFile(r.targetDir)
+				return renderer.RenderStats(stats) + renderer.RenderWorkspaceStats(file, members, perModule), "Workspace stats"
+			}
 			return renderer.RenderStats(stats), "Project stats"
 		},
 	})
 
+	// Metrics command - per-package aggregates
+	r.register(&Command{
+		Name:        "metrics",
+		Aliases:     []string{"pkgs"},
+		Description: "Show files, functions and lines per package",
+		JSON:        true,
+		Handler: func(args []string) (string, string) {
+			// Kept from when /metrics only exported Prometheus metrics
+			if len(args) > 0 && (args[0] == "prometheus" || args[0] == "prom") {
+				stats := parser.ParseStats(r.root())
+				return renderer.RenderStatsPrometheus(stats), "Prometheus metrics"
+			}
+			metrics := parser.PackageMetrics(r.root())
+			if wantsJSON(args) {
+				return renderer.RenderJSON(metrics), "Package metrics (JSON)"
+			}
+			return renderer.RenderMetrics(metrics), fmt.Sprintf("%d packages", len(metrics))
+		},
+	})
+
+	// Prometheus command - stats in machine-readable form
+	r.register(&Command{
+		Name:        "prom",
+		Aliases:     []string{"prometheus"},
+		Description: "Export project stats as Prometheus metrics",
+		Handler: func(args []string) (string, string) {
+			stats := parser.ParseStats(r.root())
+			return renderer.RenderStatsPrometheus(stats), "Prometheus metrics"
+		},
+	})
+
 	// Functions command
 	r.register(&Command{
 		Name:        "funcs",
 		Aliases:     []string{"functions", "fn"},
 		Description: "List all functions/methods",
-		Handler: func(args []string) (string, string) {
+		JSON:        true,
+		Stream: func(w io.Writer, args []string) string {
 			// Try multi-language parser first
-			funcs := parser.ParseFunctionsMultiLang(r.targetDir)
+			funcs := parser.ParseFunctionsMultiLang(r.root())
 			if len(funcs) == 0 {
-				funcs = parser.ParseFunctions(r.targetDir)
+				funcs = parser.ParseFunctions(r.root())
+			}
+			if wantsJSON(args) {
+				io.WriteString(w, renderer.RenderJSON(funcs))
+				return "Functions (JSON)"
+			}
+			renderer.RenderFunctionsTo(w, funcs)
+			return "Functions"
+		},
+	})
+
+	// Sizeof command - Go struct memory layout
+	r.register(&Command{
+		Name:        "sizeof",
+		Aliases:     []string{"layout", "size"},
+		Description: "Show Go struct sizes and padding",
+		Handler: func(args []string) (string, string) {
+			arch := parser.DefaultArch()
+			for i := 0; i < len(args); i++ {
+				if args[i] == "--arch" && i+1 < len(args) {
+					arch = args[i+1]
+					i++
+				} else if strings.HasPrefix(args[i], "--arch=") {
+					arch = strings.TrimPrefix(args[i], "--arch=")
+				}
+			}
+
+			layouts, err := parser.ParseStructLayouts(r.targetDir, arch)
+			if err != nil {
+				return fmt.Sprintf("Error: %v\n\nUsage: /sizeof [--arch amd64|arm64|386|...]", err), "Struct layout failed"
+			}
+			return renderer.RenderSizeof(layouts, arch), "Struct layout"
+		},
+	})
+
+	// Calls command - callers and callees of one function
+	r.register(&Command{
+		Name:        "calls",
+		Aliases:     []string{"call"},
+		Description: "Show callers and callees of a function",
+		Handler: func(args []string) (string, string) {
+			return r.callList("calls", args)
+		},
+	})
+
+	// Callers and callees commands - one direction of /calls each
+	r.register(&Command{
+		Name:        "callers",
+		Description: "Show the functions that call a function",
+		Handler: func(args []string) (string, string) {
+			return r.callList("callers", args)
+		},
+	})
+	r.register(&Command{
+		Name:        "callees",
+		Description: "Show the functions a function calls",
+		Handler: func(args []string) (string, string) {
+			return r.callList("callees", args)
+		},
+	})
+
+	// Search command - find symbols by name
+	r.register(&Command{
+		Name:        "search",
+		Aliases:     []string{"grep", "find"},
+		Description: "Find functions, methods, structs and interfaces by name",
+		Handler: func(args []string) (string, string) {
+			if len(args) == 0 {
+				return "Usage: /search <name>\n\nExample: /search Handler", "Missing query"
+			}
+
+			query := strings.Join(args, " ")
+			hits := parser.SearchSymbols(r.root(), query)
+			return renderer.RenderSearchResults(query, hits), fmt.Sprintf("%d symbol(s) matching %q", len(hits), query)
+		},
+	})
+
+	// Todo command - TODO, FIXME, HACK and XXX comments
+	r.register(&Command{
+		Name:        "todo",
+		Aliases:     []string{"fixme"},
+		Description: "List TODO, FIXME, HACK and XXX comments",
+		JSON:        true,
+		Handler: func(args []string) (string, string) {
+			todos := parser.ParseTodos(r.root())
+			if wantsJSON(args) {
+				return renderer.RenderJSON(todos), "TODOs (JSON)"
+			}
+			return renderer.RenderTodos(todos), fmt.Sprintf("%d TODO comment(s)", len(todos))
+		},
+	})
+
+	// Complexity command - cyclomatic complexity per function
+	r.register(&Command{
+		Name:        "complexity",
+		Aliases:     []string{"cc"},
+		Description: "Rank Go functions by cyclomatic complexity",
+		JSON:        true,
+		Handler: func(args []string) (string, string) {
+			funcs := parser.ParseComplexity(r.root())
+			if wantsJSON(args) {
+				return renderer.RenderJSON(funcs), "Complexity (JSON)"
+			}
+			return renderer.RenderComplexity(funcs), fmt.Sprintf("Complexity of %d functions", len(funcs))
+		},
+	})
+
+	// Export command - save a view as plain text
+	r.register(&Command{
+		Name:        "export",
+		Aliases:     []string{"save"},
+		Description: "Save a view to a plain-text file",
+		Handler: func(args []string) (string, string) {
+			usage := fmt.Sprintf("Usage: /export <view> <file> [view args]\n\nViews: %s\nExamples: /export tree tree.txt, /export uml uml.svg", strings.Join(exportViews, ", "))
+			if len(args) < 2 {
+				return usage, "Missing view or file"
+			}
+
+			cmd, ok := r.commands[strings.ToLower(args[0])]
+			if !ok || !slices.Contains(exportViews, cmd.Name) {
+				return fmt.Sprintf("Error: can't export %q\n\n%s", args[0], usage), "Unknown view"
+			}
+
+			path := args[1]
+			if path == "~" || strings.HasPrefix(path, "~/") {
+				if home, err := os.UserHomeDir(); err == nil {
+					path = filepath.Join(home, path[1:])
+				}
+			}
+			if !filepath.IsAbs(path) {
+				path = filepath.Join(r.targetDir, path)
+			}
+
+			// The class diagram also exports as an image for docs
+			if strings.EqualFold(filepath.Ext(path), ".svg") {
+				if cmd.Name != "uml" {
+					return fmt.Sprintf("Error: only uml exports as SVG, not %q\n\n%s", cmd.Name, usage), "Unsupported format"
+				}
+				classes, ifaces := r.umlTypes()
+				if err := os.WriteFile(path, []byte(renderer.RenderUMLSVG(classes, ifaces)), 0644); err != nil {
+					return fmt.Sprintf("Error: %v", err), "Export failed"
+				}
+				return renderer.RenderUML(classes, ifaces), "exported SVG to " + args[1]
+			}
+
+			content, _ := cmd.Handler(args[2:])
+			if err := os.WriteFile(path, []byte(plainText(content)), 0644); err != nil {
+				return fmt.Sprintf("Error: %v", err), "Export failed"
+			}
+			return content, "exported to " + args[1]
+		},
+	})
+
+	// Diagram command - exportable diagrams built from the call graph
+	r.register(&Command{
+		Name:        "diagram",
+		Aliases:     []string{"diag", "seq"},
+		Description: "Export a Mermaid sequence diagram of a function's calls",
+		Handler: func(args []string) (string, string) {
+			usage := "Usage: /diagram sequence <function> [--depth N]\n\nExamples: /diagram sequence main, /diagram sequence Registry.Execute --depth 2"
+			if len(args) == 0 || args[0] != "sequence" {
+				return usage, "Missing diagram type"
+			}
+
+			depth := defaultSequenceDepth
+			var name string
+			for i := 1; i < len(args); i++ {
+				switch arg := args[i]; {
+				case arg == "--depth" || arg == "-d":
+					if i+1 == len(args) {
+						return "Error: --depth needs a value\n\n" + usage, "Invalid depth"
+					}
+					i++
+					n, err := strconv.Atoi(args[i])
+					if err != nil || n < 1 || n > maxSequenceDepth {
+						return fmt.Sprintf("Error: depth must be 1-%d, got %q\n\n%s", maxSequenceDepth, args[i], usage), "Invalid depth"
+					}
+					depth = n
+				case name == "":
+					name = arg
+				default:
+					return fmt.Sprintf("Error: unexpected argument %q\n\n%s", arg, usage), "Invalid arguments"
+				}
+			}
+			if name == "" {
+				return usage, "Missing function name"
+			}
+
+			graph := parser.ParseCallGraph(r.targetDir)
+			return renderer.RenderSequenceMermaid(graph, name, depth), "Sequence diagram: " + name
+		},
+	})
+
+	// Routes command - HTTP route registrations
+	r.register(&Command{
+		Name:        "routes",
+		Aliases:     []string{"endpoints", "http"},
+		Description: "Show HTTP routes for web frameworks",
+		JSON:        true,
+		Handler: func(args []string) (string, string) {
+			routes := parser.ParseRoutes(r.root())
+			if wantsJSON(args) {
+				return renderer.RenderJSON(routes), "HTTP routes (JSON)"
+			}
+			return renderer.RenderRoutes(routes), "HTTP routes"
+		},
+	})
+
+	// Architecture command - layered view with direction checks
+	r.register(&Command{
+		Name:        "architecture",
+		Aliases:     []string{"arch", "layers"},
+		Description: "Show packages as architecture layers",
+		Handler: func(args []string) (string, string) {
+			rules := parser.LoadLayerRules(r.targetDir)
+			view := parser.ClassifyLayers(r.dependencies(), rules)
+			return renderer.RenderLayers(view), "Architecture layers"
+		},
+	})
+
+	// Imports command - Go import grouping check
+	r.register(&Command{
+		Name:        "imports",
+		Aliases:     []string{"imp"},
+		Description: "Check Go import grouping (std, external, internal)",
+		Handler: func(args []string) (string, string) {
+			if len(args) > 0 && args[0] != "check" {
+				return fmt.Sprintf("Error: unknown subcommand %q\n\nUsage: /imports check", args[0]), "Unknown subcommand"
+			}
+			// Needs go.mod at the root, so scan everything and filter
+			var issues []parser.ImportIssue
+			for _, issue := range parser.CheckImportGrouping(r.targetDir) {
+				if r.focus == "" || strings.HasPrefix(issue.File, r.focus+string(filepath.Separator)) {
+					issues = append(issues, issue)
+				}
 			}
-			return renderer.RenderFunctions(funcs), "Functions"
+			return renderer.RenderImportIssues(issues), fmt.Sprintf("%d file(s) with import grouping issues", len(issues))
 		},
 	})
+
+	// Bloat command - which functions make a file large
+	r.register(&Command{
+		Name:        "bloat",
+		Aliases:     []string{"fsize"},
+		Description: "Show a file's functions by size",
+		Handler: func(args []string) (string, string) {
+			if len(args) == 0 {
+				return "Usage: /bloat <file>\n\nExample: /bloat internal/ui/model.go", "Missing file"
+			}
+
+			path, err := r.resolveFile(args[0])
+			if err != nil {
+				return fmt.Sprintf("Error: %v", err), "File not found"
+			}
+
+			fi := parser.QuickFileStats(path)
+			if fi.Path == "" {
+				return fmt.Sprintf("Error: can't read %s", args[0]), "File not found"
+			}
+
+			funcs := parser.FunctionSizes(path)
+			return renderer.RenderBloat(args[0], funcs, fi.Lines), fmt.Sprintf("%d functions in %s", len(funcs), filepath.Base(path))
+		},
+	})
+
+	// File command - one file in depth
+	r.register(&Command{
+		Name:        "file",
+		Aliases:     []string{"inspect"},
+		Description: "Show a file's functions, types, imports and size",
+		JSON:        true,
+		Handler: func(args []string) (string, string) {
+			if len(args) == 0 {
+				return "Usage: /file <path>\n\nExample: /file internal/parser/parser.go", "Missing file"
+			}
+
+			path, err := r.resolveFile(args[0])
+			if err != nil {
+				return fmt.Sprintf("Error: %v", err), "File not found"
+			}
+
+			analysis := parser.AnalyzeFile(path)
+			if wantsJSON(args[1:]) {
+				return renderer.RenderJSON(analysis), "File analysis (JSON)"
+			}
+			return renderer.RenderFileAnalysis(args[0], analysis), filepath.Base(path)
+		},
+	})
+
+	// Focus command - scope views to a subdirectory
+	r.register(&Command{
+		Name:        "focus",
+		Aliases:     []string{"scope"},
+		Description: "Scope views to a package directory (/focus off to clear)",
+		Handler: func(args []string) (string, string) {
+			if len(args) == 0 {
+				if r.focus == "" {
+					return "No focus set\n\nUsage: /focus <dir> (e.g. /focus internal/parser), /focus off", "No focus"
+				}
+				return fmt.Sprintf("Focused on %s\n\nUse /focus off to clear", r.focus), "Focus: " + r.focus
+			}
+			if args[0] == "off" || args[0] == "clear" || args[0] == "none" {
+				r.focus = ""
+				return "Focus cleared - views show the whole project", "Focus cleared"
+			}
+
+			focus, err := r.resolveFocus(args[0])
+			if err != nil {
+				return fmt.Sprintf("Error: %v", err), "Focus failed"
+			}
+			if focus == "." {
+				focus = ""
+			}
+			r.focus = focus
+			return fmt.Sprintf("Focused on %s\n\n/tree, /uml, /funcs, /deps, /stats and other views now cover only this directory.\nUse /focus off to clear.", focus), "Focus: " + focus
+		},
+	})
+
+	// Smells command - god structs and oversized packages
+	r.register(&Command{
+		Name:        "smells",
+		Aliases:     []string{"god", "smell"},
+		Description: "Flag unusually large structs and packages",
+		JSON:        true,
+		Handler: func(args []string) (string, string) {
+			// The regex Go scanner would take every free function as a
+			// method of the struct above it
+			classes, _ := r.umlTypes()
+			objects := parser.DetectGodObjects(classes)
+			if wantsJSON(args) {
+				return renderer.RenderJSON(objects), "Smells (JSON)"
+			}
+			return renderer.RenderSmells(objects), fmt.Sprintf("%d god object(s)", len(objects))
+		},
+	})
+
+	// Orphans command - Go files nothing else uses
+	r.register(&Command{
+		Name:        "orphans",
+		Aliases:     []string{"dead", "unreferenced"},
+		Description: "List Go files no other file seems to use",
+		JSON:        true,
+		Handler: func(args []string) (string, string) {
+			// Imports resolve against the module, so the whole project is
+			// scanned even under /focus
+			orphans := parser.FindOrphans(r.targetDir)
+			if wantsJSON(args) {
+				return renderer.RenderJSON(orphans), "Orphans (JSON)"
+			}
+			return renderer.RenderOrphans(orphans), fmt.Sprintf("%d orphan file(s)", len(orphans))
+		},
+	})
+}
+
+// umlTypes collects the classes and interfaces for the UML views. Go
+// types come from the AST parser, which keeps fields and their tags and
+// interface method signatures; the multi-language parser covers
+// everything else.
+func (r *Registry) umlTypes() ([]parser.ClassInfo, []parser.InterfaceInfo) {
+	classes := parser.ParseClasses(r.root())
+	ifaces := parser.ParseInterfaces(r.root())
+	otherClasses, otherIfaces := parser.ParseTypesMultiLang(r.root())
+	for _, class := range otherClasses {
+		if filepath.Ext(class.File) != ".go" {
+			classes = append(classes, class)
+		}
+	}
+	for _, iface := range otherIfaces {
+		if filepath.Ext(iface.File) != ".go" {
+			ifaces = append(ifaces, iface)
+		}
+	}
+	return classes, ifaces
+}
+
+// dependencies parses Go imports with the AST parser, which sees
+// grouped import blocks, and other languages' with the multi-language
+// parser
+func (r *Registry) dependencies() []parser.Dependency {
+	deps := parser.ParseDependencies(r.root())
+	for _, dep := range parser.ParseDependenciesMultiLang(r.root()) {
+		if !strings.HasSuffix(dep.From, ".go") {
+			deps = append(deps, dep)
+		}
+	}
+	return deps
 }
 
 func (r *Registry) register(cmd *Command) {
+	if cmd.Stream != nil && cmd.Handler == nil {
+		stream := cmd.Stream
+		cmd.Handler = func(args []string) (string, string) {
+			var sb strings.Builder
+			status := stream(&sb, args)
+			return sb.String(), status
+		}
+	}
+	r.order = append(r.order, cmd)
 	r.commands[cmd.Name] = cmd
 	for _, alias := range cmd.Aliases {
 		r.commands[alias] = cmd
 	}
 }
 
+// parseAges parses ages like "30m", "1h", "2d" or "1w", accepting commas
+// as separators. Ages must increase.
+func parseAges(args []string) ([]time.Duration, error) {
+	var ages []time.Duration
+	prev := ""
+	for _, arg := range args {
+		for _, field := range strings.Split(arg, ",") {
+			if field == "" {
+				continue
+			}
+
+			var age time.Duration
+			var err error
+			if n, convErr := strconv.Atoi(strings.TrimRight(field, "dw")); convErr == nil && strings.HasSuffix(field, "d") {
+				age = time.Duration(n) * 24 * time.Hour
+			} else if convErr == nil && strings.HasSuffix(field, "w") {
+				age = time.Duration(n) * 7 * 24 * time.Hour
+			} else {
+				age, err = time.ParseDuration(field)
+			}
+			if err != nil || age <= 0 {
+				return nil, fmt.Errorf("invalid age %q", field)
+			}
+			if len(ages) > 0 && age <= ages[len(ages)-1] {
+				return nil, fmt.Errorf("ages must increase: %q after %q", field, prev)
+			}
+			ages = append(ages, age)
+			prev = field
+		}
+	}
+	return ages, nil
+}
+
+// Has reports whether name is a registered command or alias
+func (r *Registry) Has(name string) bool {
+	_, ok := r.commands[strings.ToLower(strings.TrimPrefix(name, "/"))]
+	return ok
+}
+
+// WidthAware reports whether the command in input renders to the width
+// given by SetWidth. An empty input shows the welcome screen, which does.
+func (r *Registry) WidthAware(input string) bool {
+	parts := strings.Fields(strings.TrimPrefix(input, "/"))
+	if len(parts) == 0 {
+		return true
+	}
+	cmd, ok := r.commands[strings.ToLower(parts[0])]
+	return ok && cmd.WidthAware
+}
+
+// JSON reports whether the named command can render its data as JSON
+func (r *Registry) JSON(name string) bool {
+	cmd, ok := r.commands[strings.ToLower(strings.TrimPrefix(name, "/"))]
+	return ok && cmd.JSON
+}
+
 func (r *Registry) Execute(input string) (string, string) {
 	input = strings.TrimPrefix(input, "/")
 	parts := strings.Fields(input)
 
 	if len(parts) == 0 {
-		return renderer.RenderWelcome(), "Ready"
+		return renderer.RenderWelcome(r.helpEntries(), r.width), "Ready"
 	}
 
 	cmdName := strings.ToLower(parts[0])
@@ -156,5 +1279,26 @@ func (r *Registry) Execute(input string) (string, string) {
 		return cmd.Handler(args)
 	}
 
-	return fmt.Sprintf("Unknown command: %s\n\nType /help for available commands", cmdName), "Unknown command"
+	return fmt.Sprintf("Error: unknown command %q\n\nType /help for available commands", cmdName), "Unknown command"
+}
+
+// ExecuteTo runs input like Execute but writes the output to w, streaming
+// it for commands that render as they go, and returns the status
+func (r *Registry) ExecuteTo(w io.Writer, input string) string {
+	parts := strings.Fields(strings.TrimPrefix(input, "/"))
+	if len(parts) > 0 {
+		if cmd, ok := r.commands[strings.ToLower(parts[0])]; ok && cmd.Stream != nil {
+			return cmd.Stream(w, parts[1:])
+		}
+	}
+	content, status := r.Execute(input)
+	io.WriteString(w, content)
+	return status
+}
+
+// Failed reports whether a command's output is an error or a usage
+// message rather than a result. Commands start these with "Error:" and
+// "Usage:".
+func Failed(content string) bool {
+	return strings.HasPrefix(content, "Error:") || strings.HasPrefix(content, "Usage:")
 }
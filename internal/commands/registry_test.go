@@ -0,0 +1,128 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeFiles creates files, keyed by slash-separated path, under dir
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestSmellsIgnoresGoFreeFunctions(t *testing.T) {
+	var src strings.Builder
+	src.WriteString("package p\n")
+	for i := range 10 {
+		fmt.Fprintf(&src, "\ntype s%d struct{ n int }\n\nfunc (s s%d) N() int { return s.n }\n", i, i)
+	}
+	// Free functions after the last struct would become its methods if
+	// the regex Go scanner were used
+	for i := range 30 {
+		fmt.Fprintf(&src, "\nfunc helper%d() int { return %d }\n", i, i)
+	}
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{"p/p.go": src.String()})
+
+	out, status := NewRegistry(dir).Execute("smells json")
+	if strings.Contains(out, `"Name": "s9"`) {
+		t.Errorf("s9 was flagged as a god struct (%s):\n%s", status, out)
+	}
+}
+
+func TestCallsRejectsAmbiguousNames(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"go.mod":  "module example.com/m\n\ngo 1.22\n",
+		"a/a.go":  "package a\n\nfunc Execute() { helper() }\n\nfunc helper() {}\n",
+		"b/b.go":  "package b\n\nfunc Execute() { run() }\n\nfunc run() {}\n",
+		"main.go": "package main\n\nimport (\n\t\"example.com/m/a\"\n\t\"example.com/m/b\"\n)\n\nfunc main() {\n\ta.Execute()\n\tb.Execute()\n}\n",
+	})
+	r := NewRegistry(dir)
+
+	for _, command := range []string{"calls", "callers", "callees"} {
+		out, status := r.Execute(command + " Execute")
+		if !Failed(out) || status != "Ambiguous function name" {
+			t.Errorf("/%s Execute: got %q, want the ambiguous names listed:\n%s", command, status, out)
+		}
+	}
+	if out, status := r.Execute("calls a.Execute"); Failed(out) {
+		t.Errorf("/calls a.Execute failed (%s):\n%s", status, out)
+	}
+}
+
+func TestBloatStaysInsideProject(t *testing.T) {
+	parent := t.TempDir()
+	writeFiles(t, parent, map[string]string{
+		"project/main.go": "package main\n\nfunc main() {}\n",
+		"secret.go":       "package secret\n\nfunc Key() string { return \"\" }\n",
+	})
+	r := NewRegistry(filepath.Join(parent, "project"))
+
+	for _, arg := range []string{"../secret.go", filepath.Join(parent, "secret.go")} {
+		if out, _ := r.Execute("bloat " + arg); !Failed(out) || !strings.Contains(out, "outside") {
+			t.Errorf("/bloat %s read a file outside the project:\n%s", arg, out)
+		}
+	}
+	if out, status := r.Execute("bloat main.go"); Failed(out) {
+		t.Errorf("/bloat main.go failed (%s):\n%s", status, out)
+	}
+}
+
+func TestJSONFollowsCommandArguments(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"go.mod":  "module example.com/m\n\ngo 1.22\n",
+		"main.go": "package main\n\nimport \"fmt\"\n\nfunc main() { fmt.Println() }\n",
+		"util.go": "package main\n\nfunc helper() {}\n",
+	})
+
+	r := NewRegistry(dir)
+	for _, command := range []string{"changes 1 json", "file main.go json", "deps reverse fmt json", "deps unused json", "stats --json"} {
+		out, status := r.Execute(command)
+		if !json.Valid([]byte(out)) {
+			t.Errorf("%s = %q (%s), want JSON", command, out, status)
+		}
+	}
+
+	var changes []any
+	out, _ := r.Execute("changes 1 json")
+	if err := json.Unmarshal([]byte(out), &changes); err != nil || len(changes) != 1 {
+		t.Errorf("changes 1 json = %d entries (%v), want 1", len(changes), err)
+	}
+
+	if !r.JSON("stats") || !r.JSON("/inspect") || r.JSON("sizeof") || r.JSON("diff") {
+		t.Error("JSON reports the wrong commands as having JSON output")
+	}
+}
+
+func TestExecuteToMatchesExecute(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"go.mod":        "module example.com/m\n\ngo 1.22\n",
+		"main.go":       "package main\n\nimport \"example.com/m/store\"\n\nfunc main() { store.New() }\n",
+		"store/user.go": "package store\n\ntype User struct{ Name string }\n\nfunc New() *User { return &User{} }\n",
+	})
+
+	r := NewRegistry(dir)
+	for _, command := range []string{"tree", "tree --depth 1", "tree nope", "uml", "deps", "changes 2", "funcs", "stats", "nope"} {
+		want, wantStatus := r.Execute(command)
+		var sb strings.Builder
+		if status := r.ExecuteTo(&sb, command); sb.String() != want || status != wantStatus {
+			t.Errorf("ExecuteTo(%q) = %q (%s), want %q (%s)", command, sb.String(), status, want, wantStatus)
+		}
+	}
+}
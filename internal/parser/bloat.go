@@ -0,0 +1,83 @@
+package parser
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Lines returns how many lines the function spans, or 0 if its end
+// isn't known
+func (f FunctionInfo) Lines() int {
+	if f.EndLine < f.Line {
+		return 0
+	}
+	return f.EndLine - f.Line + 1
+}
+
+// FunctionSizes lists the functions and methods of a single file with
+// their line spans, largest first. Go files use AST positions; other
+// languages are estimated as running until the next function starts.
+func FunctionSizes(path string) []FunctionInfo {
+	var funcs []FunctionInfo
+
+	if strings.HasSuffix(path, ".go") {
+		fset := token.NewFileSet()
+		node, err := parser.ParseFile(fset, path, nil, 0)
+		if err == nil {
+			for _, decl := range node.Decls {
+				funcDecl, ok := decl.(*ast.FuncDecl)
+				if !ok {
+					continue
+				}
+
+				name := funcDecl.Name.Name
+				if funcDecl.Recv != nil && len(funcDecl.Recv.List) > 0 {
+					recv := strings.TrimPrefix(exprToString(funcDecl.Recv.List[0].Type), "*")
+					name = recv + "." + name
+				}
+
+				funcs = append(funcs, FunctionInfo{
+					Name:    name,
+					Package: node.Name.Name,
+					File:    path,
+					Line:    fset.Position(funcDecl.Pos()).Line,
+					EndLine: fset.Position(funcDecl.End()).Line,
+				})
+			}
+			sortBySize(funcs)
+			return funcs
+		}
+	}
+
+	lang := getLanguageForFile(filepath.Base(path))
+	if lang == nil || lang.FuncRegex == nil {
+		return nil
+	}
+	src, ok := readSource(path)
+	if !ok {
+		return nil
+	}
+
+	funcs = scanFunctions(src, path, "", lang)
+	lastLine := lineCount(src)
+	for i := range funcs {
+		if i+1 < len(funcs) {
+			funcs[i].EndLine = funcs[i+1].Line - 1
+		} else {
+			funcs[i].EndLine = lastLine
+		}
+	}
+
+	sortBySize(funcs)
+	return funcs
+}
+
+func sortBySize(funcs []FunctionInfo) {
+	sort.SliceStable(funcs, func(i, j int) bool {
+		return funcs[i].Lines() > funcs[j].Lines()
+	})
+}
@@ -0,0 +1,154 @@
+package parser
+
+import (
+	"go/ast"
+	"go/types"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// CallEdge represents a single call site from one function to another.
+// Names are package-qualified: "pkg.Func" or "pkg.Type.Method".
+type CallEdge struct {
+	Caller string
+	Callee string
+	File   string
+	Line   int
+}
+
+// ParseCallGraph type-checks Go packages and records every resolved call
+// made from inside a function or method body
+func ParseCallGraph(root string) []CallEdge {
+	var edges []CallEdge
+
+	fset, packages := loadGoPackages(root, nil)
+	for _, cp := range packages {
+		for _, file := range cp.Files {
+			for _, decl := range file.Decls {
+				funcDecl, ok := decl.(*ast.FuncDecl)
+				if !ok || funcDecl.Body == nil {
+					continue
+				}
+
+				caller, ok := cp.Info.Defs[funcDecl.Name].(*types.Func)
+				if !ok {
+					continue
+				}
+				callerName := qualifiedFuncName(caller)
+
+				ast.Inspect(funcDecl.Body, func(n ast.Node) bool {
+					call, ok := n.(*ast.CallExpr)
+					if !ok {
+						return true
+					}
+
+					var ident *ast.Ident
+					switch fn := ast.Unparen(call.Fun).(type) {
+					case *ast.Ident:
+						ident = fn
+					case *ast.SelectorExpr:
+						ident = fn.Sel
+					case *ast.IndexExpr: // Explicit generic instantiation
+						if id, ok := fn.X.(*ast.Ident); ok {
+							ident = id
+						}
+					}
+					if ident == nil {
+						return true
+					}
+
+					callee, ok := cp.Info.Uses[ident].(*types.Func)
+					if !ok {
+						return true
+					}
+
+					pos := fset.Position(call.Pos())
+					rel, err := filepath.Rel(root, pos.Filename)
+					if err != nil {
+						rel = pos.Filename
+					}
+
+					edges = append(edges, CallEdge{
+						Caller: callerName,
+						Callee: qualifiedFuncName(callee),
+						File:   rel,
+						Line:   pos.Line,
+					})
+					return true
+				})
+			}
+		}
+	}
+
+	return edges
+}
+
+// CallersOf returns the edges whose callee matches name
+func CallersOf(edges []CallEdge, name string) []CallEdge {
+	var result []CallEdge
+	for _, edge := range edges {
+		if matchesSymbol(edge.Callee, name) {
+			result = append(result, edge)
+		}
+	}
+	return result
+}
+
+// CalleesOf returns the edges whose caller matches name
+func CalleesOf(edges []CallEdge, name string) []CallEdge {
+	var result []CallEdge
+	for _, edge := range edges {
+		if matchesSymbol(edge.Caller, name) {
+			result = append(result, edge)
+		}
+	}
+	return result
+}
+
+// CallTargets lists, sorted, the distinct functions in edges that name
+// matches as a caller or callee. More than one means name is ambiguous,
+// such as "New" declared in several packages.
+func CallTargets(edges []CallEdge, name string) []string {
+	seen := make(map[string]bool)
+	for _, edge := range edges {
+		for _, fn := range []string{edge.Caller, edge.Callee} {
+			if matchesSymbol(fn, name) {
+				seen[fn] = true
+			}
+		}
+	}
+
+	targets := make([]string, 0, len(seen))
+	for fn := range seen {
+		targets = append(targets, fn)
+	}
+	sort.Strings(targets)
+	return targets
+}
+
+// matchesSymbol reports whether a qualified name matches a query such as
+// "Execute", "Registry.Execute" or "commands.Registry.Execute"
+func matchesSymbol(qualified, query string) bool {
+	return qualified == query || strings.HasSuffix(qualified, "."+query)
+}
+
+// qualifiedFuncName formats a function as "pkg.Func" or "pkg.Type.Method"
+func qualifiedFuncName(fn *types.Func) string {
+	name := fn.Name()
+
+	if sig, ok := fn.Type().(*types.Signature); ok && sig.Recv() != nil {
+		recv := sig.Recv().Type()
+		if ptr, ok := recv.(*types.Pointer); ok {
+			recv = ptr.Elem()
+		}
+		if named, ok := recv.(*types.Named); ok {
+			name = named.Obj().Name() + "." + name
+		}
+	}
+
+	if fn.Pkg() != nil {
+		name = fn.Pkg().Name() + "." + name
+	}
+	return name
+}
@@ -0,0 +1,111 @@
+package parser
+
+import "strings"
+
+// Comment markers shared by several languages
+var (
+	slashComments = []string{"//"}
+	hashComments  = []string{"#"}
+	starComments  = [][2]string{{"/*", "*/"}}
+)
+
+// commentFilter strips comments from source lines before the regex
+// parsers see them, so "// class Foo" or a "def bar" string literal
+// doesn't produce a phantom symbol. Block comments span lines, so one
+// filter is used per file, line by line in order.
+type commentFilter struct {
+	lang     *LanguagePattern
+	blockEnd string // Closing marker of the block comment we're in, or ""
+}
+
+func newCommentFilter(lang *LanguagePattern) *commentFilter {
+	return &commentFilter{lang: lang}
+}
+
+// strip returns line without comments, and the same with the contents of
+// string literals blanked out. Imports are matched on the first, since
+// their paths are strings; everything else on the second. Both are empty
+// for a line that is entirely comment.
+func (f *commentFilter) strip(line string) (code, blanked string) {
+	var cb, bb strings.Builder
+	i := 0
+
+scan:
+	for i < len(line) {
+		if f.blockEnd != "" {
+			end := strings.Index(line[i:], f.blockEnd)
+			if end < 0 {
+				break
+			}
+			i += end + len(f.blockEnd)
+			f.blockEnd = ""
+			cb.WriteByte(' ')
+			bb.WriteByte(' ')
+			continue
+		}
+
+		rest := line[i:]
+		for _, block := range f.lang.BlockComments {
+			if strings.HasPrefix(rest, block[0]) {
+				f.blockEnd = block[1]
+				i += len(block[0])
+				continue scan
+			}
+		}
+		for _, marker := range f.lang.LineComments {
+			if strings.HasPrefix(rest, marker) {
+				break scan
+			}
+		}
+
+		c := line[i]
+		if strings.IndexByte(f.lang.Quotes, c) < 0 {
+			cb.WriteByte(c)
+			bb.WriteByte(c)
+			i++
+			continue
+		}
+
+		// A string literal runs to the matching quote, skipping escapes,
+		// or to the end of the line
+		end := i + 1
+		for end < len(line) && line[end] != c {
+			if line[end] == '\\' {
+				end++
+			}
+			end++
+		}
+		end = min(end+1, len(line))
+		cb.WriteString(line[i:end])
+		bb.WriteByte(c)
+		bb.WriteString(strings.Repeat(" ", max(end-i-2, 0)))
+		if end-i > 1 && line[end-1] == c {
+			bb.WriteByte(c)
+		}
+		i = end
+	}
+
+	return cb.String(), bb.String()
+}
+
+// docText returns the text of a line that strip found to be entirely
+// comment, without its markers, so comment lines above a declaration can
+// be kept as its doc. "// x", "/// x", "# x", "/** x", " * x" and "x */"
+// all give "x".
+func (f *commentFilter) docText(raw string) string {
+	text := strings.TrimSpace(raw)
+	for _, marker := range f.lang.LineComments {
+		if rest, ok := strings.CutPrefix(text, marker); ok {
+			// Rust's //!, Haddock's -- | and -- ^, and Erlang's %% mark
+			// doc comments
+			return strings.TrimSpace(strings.TrimLeft(rest, marker+"/!|^ "))
+		}
+	}
+	for _, block := range f.lang.BlockComments {
+		text = strings.TrimSuffix(text, block[1])
+		if rest, ok := strings.CutPrefix(text, block[0]); ok {
+			text = strings.TrimLeft(rest, "*!")
+		}
+	}
+	return strings.TrimSpace(strings.TrimPrefix(text, "*"))
+}
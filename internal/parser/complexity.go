@@ -0,0 +1,111 @@
+package parser
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// FunctionComplexity is the cyclomatic complexity of one Go function
+type FunctionComplexity struct {
+	Name    string // "Func" or "Type.Method"
+	Package string
+	File    string // Relative to the project root
+	Line    int
+	Score   int
+}
+
+// ParseComplexity scores every Go function and method under root as one
+// plus its decision points: if, for, range, non-default case and select
+// clauses, && and ||. Closures count towards the enclosing function.
+// Results are sorted by score, highest first.
+func ParseComplexity(root string) []FunctionComplexity {
+	var results []FunctionComplexity
+	fset := token.NewFileSet()
+
+	ignore := LoadIgnore(root)
+	filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if skip, result := ignore.Skip(path, info); skip {
+			return result
+		}
+		if err != nil {
+			return nil
+		}
+		name := info.Name()
+		if info.IsDir() {
+			if path != root && (strings.HasPrefix(name, ".") || name == "vendor" || name == "node_modules") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") {
+			return nil
+		}
+
+		node, err := parser.ParseFile(fset, path, nil, 0)
+		if err != nil {
+			return nil
+		}
+
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			rel = path
+		}
+
+		for _, decl := range node.Decls {
+			funcDecl, ok := decl.(*ast.FuncDecl)
+			if !ok || funcDecl.Body == nil {
+				continue
+			}
+
+			name := funcDecl.Name.Name
+			if funcDecl.Recv != nil && len(funcDecl.Recv.List) > 0 {
+				recv := strings.TrimPrefix(exprToString(funcDecl.Recv.List[0].Type), "*")
+				name = recv + "." + name
+			}
+
+			results = append(results, FunctionComplexity{
+				Name:    name,
+				Package: node.Name.Name,
+				File:    rel,
+				Line:    fset.Position(funcDecl.Pos()).Line,
+				Score:   cyclomatic(funcDecl.Body),
+			})
+		}
+		return nil
+	})
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Score > results[j].Score
+	})
+	return results
+}
+
+// cyclomatic counts the decision points in body, plus one
+func cyclomatic(body *ast.BlockStmt) int {
+	score := 1
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.IfStmt, *ast.ForStmt, *ast.RangeStmt:
+			score++
+		case *ast.CaseClause:
+			if node.List != nil { // default has no list
+				score++
+			}
+		case *ast.CommClause:
+			if node.Comm != nil {
+				score++
+			}
+		case *ast.BinaryExpr:
+			if node.Op == token.LAND || node.Op == token.LOR {
+				score++
+			}
+		}
+		return true
+	})
+	return score
+}
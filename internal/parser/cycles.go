@@ -0,0 +1,210 @@
+package parser
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// jsResolveExtensions are tried, in order, for extensionless relative
+// imports, mirroring the TypeScript and Node resolution order
+var jsResolveExtensions = []string{".ts", ".tsx", ".js", ".jsx", ".mjs"}
+
+// ImportCycle is a set of files that import each other in a loop
+type ImportCycle struct {
+	Files   []string // One loop in import order; the first file closes it
+	Members int      // Files in the strongly connected component
+}
+
+// ResolveImports maps relative JS/TS import specifiers ("./foo",
+// "../bar/index.js") to files within root. The result holds only imports
+// that resolved, with To set to the imported file's path relative to root.
+func ResolveImports(root string, deps []Dependency) []Dependency {
+	exists := make(map[string]bool)
+	isFile := func(rel string) bool {
+		if found, ok := exists[rel]; ok {
+			return found
+		}
+		info, err := os.Stat(filepath.Join(root, filepath.FromSlash(rel)))
+		exists[rel] = err == nil && !info.IsDir()
+		return exists[rel]
+	}
+
+	var resolved []Dependency
+	for _, dep := range deps {
+		if !isJSFile(dep.From) || !strings.HasPrefix(dep.To, ".") {
+			continue
+		}
+
+		from := filepath.ToSlash(dep.From)
+		base := path.Join(path.Dir(from), dep.To)
+		if strings.HasPrefix(base, "../") || base == ".." {
+			continue // Outside the project
+		}
+
+		var candidates []string
+		if ext := path.Ext(base); ext != "" {
+			candidates = append(candidates, base)
+			// TypeScript ESM code imports "./foo.js" for foo.ts
+			if ext == ".js" || ext == ".jsx" || ext == ".mjs" {
+				stem := strings.TrimSuffix(base, ext)
+				candidates = append(candidates, stem+".ts", stem+".tsx")
+			}
+		}
+		for _, ext := range jsResolveExtensions {
+			candidates = append(candidates, base+ext)
+		}
+		for _, ext := range jsResolveExtensions {
+			candidates = append(candidates, base+"/index"+ext)
+		}
+
+		for _, c := range candidates {
+			if isFile(c) {
+				dep.To = filepath.FromSlash(c)
+				resolved = append(resolved, dep)
+				break
+			}
+		}
+	}
+
+	return resolved
+}
+
+func isJSFile(name string) bool {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".ts", ".tsx", ".js", ".jsx", ".mjs":
+		return true
+	}
+	return false
+}
+
+// FindImportCycles finds file-to-file import cycles in resolved
+// dependencies (see ResolveImports). Each strongly connected component
+// is reported once, with one concrete loop through it.
+func FindImportCycles(deps []Dependency) []ImportCycle {
+	graph := make(map[string][]string)
+	for _, dep := range deps {
+		graph[dep.From] = append(graph[dep.From], dep.To)
+		if _, ok := graph[dep.To]; !ok {
+			graph[dep.To] = nil
+		}
+	}
+
+	nodes := make([]string, 0, len(graph))
+	for node, targets := range graph {
+		nodes = append(nodes, node)
+		sort.Strings(targets)
+	}
+	sort.Strings(nodes)
+
+	var cycles []ImportCycle
+	for _, component := range stronglyConnected(nodes, graph) {
+		selfLoop := false
+		for _, t := range graph[component[0]] {
+			if t == component[0] {
+				selfLoop = true
+			}
+		}
+		if len(component) == 1 && !selfLoop {
+			continue
+		}
+
+		sort.Strings(component)
+		cycles = append(cycles, ImportCycle{
+			Files:   loopThrough(component, graph),
+			Members: len(component),
+		})
+	}
+
+	sort.Slice(cycles, func(i, j int) bool {
+		if len(cycles[i].Files) != len(cycles[j].Files) {
+			return len(cycles[i].Files) < len(cycles[j].Files)
+		}
+		return cycles[i].Files[0] < cycles[j].Files[0]
+	})
+	return cycles
+}
+
+// stronglyConnected returns the strongly connected components of graph
+// using Tarjan's algorithm
+func stronglyConnected(nodes []string, graph map[string][]string) [][]string {
+	index := make(map[string]int)
+	low := make(map[string]int)
+	onStack := make(map[string]bool)
+	var stack []string
+	var components [][]string
+	next := 0
+
+	var visit func(node string)
+	visit = func(node string) {
+		index[node] = next
+		low[node] = next
+		next++
+		stack = append(stack, node)
+		onStack[node] = true
+
+		for _, target := range graph[node] {
+			if _, seen := index[target]; !seen {
+				visit(target)
+				low[node] = min(low[node], low[target])
+			} else if onStack[target] {
+				low[node] = min(low[node], index[target])
+			}
+		}
+
+		if low[node] == index[node] {
+			var component []string
+			for {
+				top := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				onStack[top] = false
+				component = append(component, top)
+				if top == node {
+					break
+				}
+			}
+			components = append(components, component)
+		}
+	}
+
+	for _, node := range nodes {
+		if _, seen := index[node]; !seen {
+			visit(node)
+		}
+	}
+	return components
+}
+
+// loopThrough finds the shortest loop from the component's first file
+// back to itself, staying inside the component
+func loopThrough(component []string, graph map[string][]string) []string {
+	inComponent := make(map[string]bool)
+	for _, node := range component {
+		inComponent[node] = true
+	}
+
+	start := component[0]
+	parent := map[string]string{}
+	queue := []string{start}
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+		for _, target := range graph[node] {
+			if target == start {
+				// Walk back to build the loop
+				loop := []string{node}
+				for loop[0] != start {
+					loop = append([]string{parent[loop[0]]}, loop...)
+				}
+				return loop
+			}
+			if _, seen := parent[target]; !seen && inComponent[target] {
+				parent[target] = node
+				queue = append(queue, target)
+			}
+		}
+	}
+	return component
+}
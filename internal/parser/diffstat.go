@@ -0,0 +1,141 @@
+package parser
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// DiffStat is one file's uncommitted line changes, as reported by
+// git diff --numstat
+type DiffStat struct {
+	Path    string
+	Added   int
+	Deleted int
+	Binary  bool   // Git reports no line counts for binary files
+	Staged  bool   // From the index rather than the working tree
+	Status  string // "added", "modified" or "deleted"; set by GitStatus
+}
+
+// Git file statuses reported in DiffStat.Status
+const (
+	StatusAdded    = "added"
+	StatusModified = "modified"
+	StatusDeleted  = "deleted"
+)
+
+// WorkingTreeStat returns staged and unstaged changes to tracked files
+// under root, staged first. Paths are relative to root. The error matches
+// ErrNotARepo when root isn't inside a git work tree.
+func WorkingTreeStat(root string) ([]DiffStat, error) {
+	if err := exec.Command("git", "-C", root, "rev-parse", "--is-inside-work-tree").Run(); err != nil {
+		return nil, fmt.Errorf("%s: %w", root, ErrNotARepo)
+	}
+
+	staged, err := numstat(root, true)
+	if err != nil {
+		return nil, err
+	}
+	unstaged, err := numstat(root, false)
+	if err != nil {
+		return nil, err
+	}
+	return append(staged, unstaged...), nil
+}
+
+// GitStatus returns the same changes as WorkingTreeStat, each with
+// whether git sees the file as added, modified or deleted. Renames are
+// reported as a deletion and an addition.
+func GitStatus(root string) ([]DiffStat, error) {
+	if err := exec.Command("git", "-C", root, "rev-parse", "--is-inside-work-tree").Run(); err != nil {
+		return nil, fmt.Errorf("%s: %w", root, ErrNotARepo)
+	}
+
+	var stats []DiffStat
+	for _, staged := range []bool{true, false} {
+		files, err := numstat(root, staged, "--no-renames")
+		if err != nil {
+			return nil, err
+		}
+		statuses, err := nameStatus(root, staged)
+		if err != nil {
+			return nil, err
+		}
+		for i := range files {
+			files[i].Status = statuses[files[i].Path]
+		}
+		stats = append(stats, files...)
+	}
+	return stats, nil
+}
+
+// nameStatus runs git diff --name-status and maps each path to its status
+func nameStatus(root string, staged bool) (map[string]string, error) {
+	out, err := gitDiff(root, staged, "--name-status", "--no-renames")
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make(map[string]string)
+	for _, line := range strings.Split(out, "\n") {
+		code, path, ok := strings.Cut(line, "\t")
+		if !ok || code == "" {
+			continue
+		}
+		switch code[0] {
+		case 'A':
+			statuses[path] = StatusAdded
+		case 'D':
+			statuses[path] = StatusDeleted
+		default:
+			// Content, type and mode changes, and unmerged paths
+			statuses[path] = StatusModified
+		}
+	}
+	return statuses, nil
+}
+
+// numstat runs git diff --numstat, against the index or HEAD for staged
+func numstat(root string, staged bool, extra ...string) ([]DiffStat, error) {
+	out, err := gitDiff(root, staged, append([]string{"--numstat"}, extra...)...)
+	if err != nil {
+		return nil, err
+	}
+
+	var stats []DiffStat
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.SplitN(line, "\t", 3)
+		if len(fields) < 3 {
+			continue
+		}
+		stat := DiffStat{Path: fields[2], Staged: staged}
+		if fields[0] == "-" && fields[1] == "-" {
+			stat.Binary = true
+		} else {
+			stat.Added, _ = strconv.Atoi(fields[0])
+			stat.Deleted, _ = strconv.Atoi(fields[1])
+		}
+		stats = append(stats, stat)
+	}
+	return stats, nil
+}
+
+// gitDiff runs git diff with args on the working tree, or on the index
+// when staged is set, and returns its output. Paths are relative to root.
+func gitDiff(root string, staged bool, args ...string) (string, error) {
+	args = append([]string{"-C", root, "diff", "--relative"}, args...)
+	if staged {
+		args = append(args, "--cached")
+	}
+
+	var stderr bytes.Buffer
+	cmd := exec.Command("git", args...)
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("git diff: %v: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return string(out), nil
+}
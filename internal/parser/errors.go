@@ -0,0 +1,59 @@
+package parser
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+)
+
+var (
+	// ErrNotADirectory is returned when a project root is a file
+	ErrNotADirectory = errors.New("not a directory")
+
+	// ErrUnknownArch is returned for a GOARCH the gc compiler doesn't know
+	ErrUnknownArch = errors.New("unknown architecture")
+
+	// ErrNoWorkspace is returned by ParseWorkspace when root has no
+	// workspace file
+	ErrNoWorkspace = errors.New("no workspace file")
+
+	// ErrNotARepo is returned by git-backed views outside a work tree
+	ErrNotARepo = errors.New("not a git repository")
+)
+
+// ParseError records a source file the Go parser rejected
+type ParseError struct {
+	File string
+	Err  error
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("parse %s: %v", e.File, e.Err)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// MarshalJSON writes the error message, which the Err interface value
+// would otherwise lose
+func (e *ParseError) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		File  string
+		Error string
+	}{e.File, e.Err.Error()})
+}
+
+// ValidateRoot checks that root exists and is a directory. A missing root
+// is reported with an error matching fs.ErrNotExist.
+func ValidateRoot(root string) error {
+	info, err := os.Stat(root)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("%s: %w", root, ErrNotADirectory)
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+package parser
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// FileAnalysis is everything the single-file view shows about one file
+type FileAnalysis struct {
+	Path       string
+	Language   string // languagePatterns key, or "" for unrecognized files
+	Lines      int
+	Size       int64
+	Functions  []FunctionInfo // In file order, with line spans
+	Structs    []string       // Structs and classes
+	Interfaces []string
+	Imports    []string
+}
+
+// AnalyzeFile reads the functions, types and imports of one file. Go
+// files use the AST; other languages, and Go the AST rejects, use the
+// regex patterns. An unrecognized file gets only its size and lines.
+func AnalyzeFile(path string) FileAnalysis {
+	fi := QuickFileStats(path)
+	fa := FileAnalysis{
+		Path:     path,
+		Language: languageName(filepath.Base(path)),
+		Lines:    fi.Lines,
+		Size:     fi.Size,
+	}
+
+	// FunctionSizes orders by size; this view follows the file
+	fa.Functions = FunctionSizes(path)
+	sort.SliceStable(fa.Functions, func(i, j int) bool {
+		return fa.Functions[i].Line < fa.Functions[j].Line
+	})
+
+	if strings.HasSuffix(path, ".go") {
+		fset := token.NewFileSet()
+		if node, err := parser.ParseFile(fset, path, nil, parser.SkipObjectResolution); err == nil {
+			for _, spec := range node.Imports {
+				if importPath, err := strconv.Unquote(spec.Path.Value); err == nil {
+					fa.Imports = append(fa.Imports, importPath)
+				}
+			}
+			fa.Structs, fa.Interfaces = goTypeNames(node)
+			return fa
+		}
+	}
+
+	lang := getLanguageForFile(filepath.Base(path))
+	if lang == nil {
+		return fa
+	}
+	src, ok := readSource(path)
+	if !ok {
+		return fa
+	}
+
+	if lang.ImportRegex != nil {
+		for _, dep := range scanImports(src, path, "", lang) {
+			fa.Imports = append(fa.Imports, dep.To)
+		}
+	}
+	classes, ifaces := scanClasses(src, path, "", lang)
+	for _, class := range classes {
+		fa.Structs = append(fa.Structs, class.Name)
+	}
+	for _, iface := range ifaces {
+		fa.Interfaces = append(fa.Interfaces, iface.Name)
+	}
+	return fa
+}
+
+// goTypeNames lists the structs and interfaces a Go file declares
+func goTypeNames(node *ast.File) (structs, interfaces []string) {
+	for _, decl := range node.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			ts, ok := spec.(*ast.TypeSpec)
+			if !ok {
+				continue
+			}
+			switch ts.Type.(type) {
+			case *ast.StructType:
+				structs = append(structs, ts.Name.Name+typeParams(ts.TypeParams))
+			case *ast.InterfaceType:
+				interfaces = append(interfaces, ts.Name.Name+typeParams(ts.TypeParams))
+			}
+		}
+	}
+	return structs, interfaces
+}
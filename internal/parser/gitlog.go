@@ -0,0 +1,126 @@
+package parser
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultLogCount is the number of commits GitLog returns when no count
+// is given
+const DefaultLogCount = 20
+
+// Commit is one entry of git log
+type Commit struct {
+	SHA     string // Abbreviated
+	Author  string
+	Time    time.Time // Author date
+	Subject string
+}
+
+// GitLog returns up to n of the most recent commits reachable from HEAD,
+// newest first. A repository without commits yields none. The error
+// matches ErrNotARepo when root isn't inside a git work tree.
+func GitLog(root string, n int) ([]Commit, error) {
+	if n <= 0 {
+		n = DefaultLogCount
+	}
+	if err := exec.Command("git", "-C", root, "rev-parse", "--is-inside-work-tree").Run(); err != nil {
+		return nil, fmt.Errorf("%s: %w", root, ErrNotARepo)
+	}
+	if err := exec.Command("git", "-C", root, "rev-parse", "--verify", "--quiet", "HEAD").Run(); err != nil {
+		return nil, nil
+	}
+
+	// Fields are separated by the unit separator, which can't appear in
+	// names or subjects
+	var stderr bytes.Buffer
+	cmd := exec.Command("git", "-C", root, "log", "-n", strconv.Itoa(n), "--format=%h%x1f%an%x1f%at%x1f%s")
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git log: %v: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	var commits []Commit
+	for _, line := range strings.Split(string(out), "\n") {
+		fields := strings.SplitN(line, "\x1f", 4)
+		if len(fields) < 4 {
+			continue
+		}
+		commit := Commit{SHA: fields[0], Author: fields[1], Subject: fields[3]}
+		if secs, err := strconv.ParseInt(fields[2], 10, 64); err == nil {
+			commit.Time = time.Unix(secs, 0)
+		}
+		commits = append(commits, commit)
+	}
+	return commits, nil
+}
+
+// AuthorActivity is one author's share of the commit history
+type AuthorActivity struct {
+	Name    string
+	Email   string
+	Commits int
+}
+
+// GitAuthors counts the commits reachable from HEAD by author, most
+// active first. since is passed to git log --since, so it takes any date
+// git understands ("2024-01-02", an RFC 3339 time); "" counts the whole
+// history. A repository without commits yields none. The error matches
+// ErrNotARepo when root isn't inside a git work tree.
+func GitAuthors(root, since string) ([]AuthorActivity, error) {
+	if err := exec.Command("git", "-C", root, "rev-parse", "--is-inside-work-tree").Run(); err != nil {
+		return nil, fmt.Errorf("%s: %w", root, ErrNotARepo)
+	}
+	if err := exec.Command("git", "-C", root, "rev-parse", "--verify", "--quiet", "HEAD").Run(); err != nil {
+		return nil, nil
+	}
+
+	// Without a revision shortlog reads a log from stdin, so HEAD is
+	// named explicitly
+	args := []string{"-C", root, "shortlog", "-sne"}
+	if since != "" {
+		args = append(args, "--since="+since)
+	}
+	args = append(args, "HEAD")
+
+	var stderr bytes.Buffer
+	cmd := exec.Command("git", args...)
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git shortlog: %v: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	// Lines are "   12\tName <email>"
+	var authors []AuthorActivity
+	for _, line := range strings.Split(string(out), "\n") {
+		count, author, ok := strings.Cut(strings.TrimSpace(line), "\t")
+		if !ok {
+			continue
+		}
+		n, err := strconv.Atoi(strings.TrimSpace(count))
+		if err != nil {
+			continue
+		}
+		activity := AuthorActivity{Name: author, Commits: n}
+		if open := strings.LastIndexByte(author, '<'); open >= 0 && strings.HasSuffix(author, ">") {
+			activity.Name = strings.TrimSpace(author[:open])
+			activity.Email = author[open+1 : len(author)-1]
+		}
+		authors = append(authors, activity)
+	}
+
+	sort.SliceStable(authors, func(i, j int) bool {
+		if authors[i].Commits != authors[j].Commits {
+			return authors[i].Commits > authors[j].Commits
+		}
+		return authors[i].Name < authors[j].Name
+	})
+	return authors, nil
+}
@@ -0,0 +1,55 @@
+package parser
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// Glob is a compiled path pattern. Like a .gitignore line, a pattern
+// without a slash, such as "*.go" or "parser", matches a name at any
+// depth; one with a slash, such as "internal/**/*.go", matches the path
+// from the project root, with "**" standing for any number of
+// directories. Either way a matching directory takes in everything
+// inside it.
+type Glob struct {
+	pattern  string
+	segments []string
+	anchored bool
+}
+
+// CompileGlob checks a pattern's syntax and compiles it
+func CompileGlob(pattern string) (*Glob, error) {
+	pattern = strings.Trim(filepath.ToSlash(pattern), "/")
+	if pattern == "" {
+		return nil, fmt.Errorf("empty pattern")
+	}
+	segments := strings.Split(pattern, "/")
+	for _, segment := range segments {
+		if _, err := path.Match(segment, ""); err != nil {
+			return nil, fmt.Errorf("bad pattern %q: %w", pattern, err)
+		}
+	}
+	return &Glob{pattern: pattern, segments: segments, anchored: len(segments) > 1}, nil
+}
+
+// Match reports whether a path relative to the project root matches
+func (g *Glob) Match(rel string) bool {
+	segments := strings.Split(filepath.ToSlash(rel), "/")
+	for i, segment := range segments {
+		if g.anchored {
+			if matchGlob(g.segments, segments[:i+1]) {
+				return true
+			}
+		} else if ok, _ := path.Match(g.segments[0], segment); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// String returns the pattern as compiled
+func (g *Glob) String() string {
+	return g.pattern
+}
@@ -0,0 +1,117 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// GoModule is the parsed content of a go.mod file
+type GoModule struct {
+	Path      string
+	GoVersion string
+	Requires  []ModuleRequire
+}
+
+// ModuleRequire is one entry of a require directive
+type ModuleRequire struct {
+	Path     string
+	Version  string
+	Indirect bool // Marked "// indirect": only needed by other dependencies
+}
+
+// ParseGoMod reads the module path, go version and requirements from
+// root/go.mod
+func ParseGoMod(root string) (*GoModule, error) {
+	data, err := os.ReadFile(filepath.Join(root, "go.mod"))
+	if err != nil {
+		return nil, err
+	}
+
+	mod := &GoModule{}
+	inRequire := false
+
+	for _, line := range strings.Split(string(stripBOM(data)), "\n") {
+		code, comment, _ := strings.Cut(line, "//")
+		code = strings.TrimSpace(code)
+		indirect := strings.TrimSpace(comment) == "indirect"
+
+		if inRequire {
+			if code == ")" {
+				inRequire = false
+			} else if req, ok := parseRequire(code, indirect); ok {
+				mod.Requires = append(mod.Requires, req)
+			}
+			continue
+		}
+
+		fields := strings.Fields(code)
+		if len(fields) < 2 {
+			continue
+		}
+
+		switch fields[0] {
+		case "module":
+			mod.Path = strings.Trim(fields[1], `"`)
+		case "go":
+			mod.GoVersion = fields[1]
+		case "require":
+			if fields[1] == "(" {
+				inRequire = true
+			} else if req, ok := parseRequire(strings.TrimPrefix(code, "require"), indirect); ok {
+				mod.Requires = append(mod.Requires, req)
+			}
+		}
+	}
+
+	return mod, nil
+}
+
+// parseRequire parses "path version" from a require line
+func parseRequire(code string, indirect bool) (ModuleRequire, bool) {
+	fields := strings.Fields(code)
+	if len(fields) < 2 {
+		return ModuleRequire{}, false
+	}
+	return ModuleRequire{
+		Path:     strings.Trim(fields[0], `"`),
+		Version:  fields[1],
+		Indirect: indirect,
+	}, true
+}
+
+// UnusedModules returns required modules that no Go file in the project
+// imports, directly or through one of their packages. Test files count
+// as uses; vendored sources don't.
+func UnusedModules(root string) ([]string, error) {
+	mod, err := ParseGoMod(root)
+	if err != nil {
+		return nil, err
+	}
+
+	imports := make(map[string]bool)
+	for _, dep := range ParseDependencies(root) {
+		if strings.HasPrefix(filepath.ToSlash(dep.From), "vendor/") {
+			continue
+		}
+		imports[dep.To] = true
+	}
+
+	var unused []string
+	for _, req := range mod.Requires {
+		used := false
+		for imp := range imports {
+			if imp == req.Path || strings.HasPrefix(imp, req.Path+"/") {
+				used = true
+				break
+			}
+		}
+		if !used {
+			unused = append(unused, req.Path)
+		}
+	}
+
+	sort.Strings(unused)
+	return unused, nil
+}
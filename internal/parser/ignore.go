@@ -0,0 +1,203 @@
+package parser
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// Ignore matches paths against .gitignore rules. The hard-coded skips in
+// each walk (hidden files, node_modules, vendor) still apply on top, so a
+// project without a .gitignore is walked exactly as before.
+type Ignore struct {
+	root    string // As passed to LoadIgnore, to map walked paths
+	absRoot string
+	rules   []ignoreRule
+}
+
+// ignoreRule is one .gitignore line
+type ignoreRule struct {
+	base     string   // Absolute directory the rule is relative to
+	segments []string // Pattern split on "/"
+	anchored bool     // Leading or inner "/": matched from base, not by name
+	dirOnly  bool     // Trailing "/"
+	negate   bool     // Leading "!"
+}
+
+// ArcsiIgnoreFile lists paths, relative to the project root, that arcsii
+// leaves out of every view and the live watch, in .gitignore syntax
+const ArcsiIgnoreFile = ".arcsiignore"
+
+// LoadIgnore reads the .gitignore files that apply to root: the one in
+// root, those in its parents up to the repository top, and the
+// repository's .git/info/exclude. Nested .gitignore files below root are
+// not read. Root's .arcsiignore is read last, so its rules win over all
+// of these. A nil *Ignore (or one with no rules) matches nothing.
+func LoadIgnore(root string) *Ignore {
+	absRoot, err := filepath.Abs(root)
+	if err != nil {
+		absRoot = root
+	}
+	ig := &Ignore{root: root, absRoot: absRoot}
+
+	// Collect directories from the repository top down to root
+	var dirs []string
+	for dir := absRoot; ; dir = filepath.Dir(dir) {
+		dirs = append([]string{dir}, dirs...)
+		if info, err := os.Stat(filepath.Join(dir, ".git")); err == nil && info.IsDir() {
+			ig.load(filepath.Join(dir, ".git", "info", "exclude"), dir)
+			break
+		}
+		if filepath.Dir(dir) == dir {
+			dirs = []string{absRoot} // Not in a repository
+			break
+		}
+	}
+
+	for _, dir := range dirs {
+		ig.load(filepath.Join(dir, ".gitignore"), dir)
+	}
+	ig.load(filepath.Join(absRoot, ArcsiIgnoreFile), absRoot)
+	return ig
+}
+
+// LoadArcsiIgnore reads only root's .arcsiignore, for walks that don't
+// follow .gitignore. It returns nil if the file is missing or empty.
+func LoadArcsiIgnore(root string) *Ignore {
+	absRoot, err := filepath.Abs(root)
+	if err != nil {
+		absRoot = root
+	}
+	ig := &Ignore{root: root, absRoot: absRoot}
+	ig.load(filepath.Join(absRoot, ArcsiIgnoreFile), absRoot)
+	if len(ig.rules) == 0 {
+		return nil
+	}
+	return ig
+}
+
+// load adds the rules of one ignore file, relative to base
+func (ig *Ignore) load(file, base string) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return
+	}
+
+	for _, line := range strings.Split(string(stripBOM(data)), "\n") {
+		line = strings.TrimRight(line, " \r")
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		rule := ignoreRule{base: base}
+		if strings.HasPrefix(line, "!") {
+			rule.negate = true
+			line = line[1:]
+		}
+		line = strings.TrimPrefix(line, `\`) // Escaped leading "#" or "!"
+		if strings.HasSuffix(line, "/") {
+			rule.dirOnly = true
+			line = strings.TrimSuffix(line, "/")
+		}
+		if strings.Contains(line, "/") {
+			rule.anchored = true
+			line = strings.TrimPrefix(line, "/")
+		}
+		if line == "" {
+			continue
+		}
+
+		rule.segments = strings.Split(line, "/")
+		ig.rules = append(ig.rules, rule)
+	}
+}
+
+// Match reports whether path, as produced by walking the root given to
+// LoadIgnore, is ignored. A path inside an ignored directory is ignored
+// too, as in git.
+func (ig *Ignore) Match(path string, isDir bool) bool {
+	if ig == nil || len(ig.rules) == 0 {
+		return false
+	}
+
+	abs := ig.abs(path)
+	if abs == ig.absRoot {
+		return false
+	}
+
+	// Check each parent below the root first: git doesn't look inside
+	// excluded directories
+	rel, err := filepath.Rel(ig.absRoot, abs)
+	if err == nil && !outside(rel) {
+		parts := strings.Split(filepath.ToSlash(rel), "/")
+		for i := 1; i < len(parts); i++ {
+			if ig.matchOne(filepath.Join(ig.absRoot, filepath.Join(parts[:i]...)), true) {
+				return true
+			}
+		}
+	}
+	return ig.matchOne(abs, isDir)
+}
+
+// Skip is Match for a filepath.Walk callback: it reports whether to skip
+// the entry and what the callback should return. It never skips when the
+// walk reported an error (info is nil).
+func (ig *Ignore) Skip(path string, info os.FileInfo) (bool, error) {
+	if info == nil || !ig.Match(path, info.IsDir()) {
+		return false, nil
+	}
+	if info.IsDir() {
+		return true, filepath.SkipDir
+	}
+	return true, nil
+}
+
+// matchOne applies the rules to a single absolute path; the last
+// matching rule wins
+func (ig *Ignore) matchOne(abs string, isDir bool) bool {
+	ignored := false
+	for _, rule := range ig.rules {
+		if rule.negate == ignored && rule.matches(abs, isDir) {
+			ignored = !rule.negate
+		}
+	}
+	return ignored
+}
+
+func (r ignoreRule) matches(abs string, isDir bool) bool {
+	if r.dirOnly && !isDir {
+		return false
+	}
+	rel, err := filepath.Rel(r.base, abs)
+	if err != nil || rel == "." || outside(rel) {
+		return false
+	}
+	segments := strings.Split(filepath.ToSlash(rel), "/")
+
+	if r.anchored {
+		return matchGlob(r.segments, segments)
+	}
+	ok, _ := path.Match(r.segments[0], segments[len(segments)-1])
+	return ok
+}
+
+// abs maps a walked path to an absolute one without asking the OS for
+// the working directory on every call
+func (ig *Ignore) abs(p string) string {
+	if filepath.IsAbs(p) {
+		return filepath.Clean(p)
+	}
+	if rel, err := filepath.Rel(ig.root, p); err == nil && !outside(rel) {
+		return filepath.Join(ig.absRoot, rel)
+	}
+	if abs, err := filepath.Abs(p); err == nil {
+		return abs
+	}
+	return p
+}
+
+// outside reports whether a relative path climbs out of its base
+func outside(rel string) bool {
+	return rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
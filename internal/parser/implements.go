@@ -0,0 +1,161 @@
+package parser
+
+import (
+	"go/ast"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// qualifierRegex matches package qualifiers such as "http." in "*http.Request"
+var qualifierRegex = regexp.MustCompile(`\b\w+\.`)
+
+// interfaceDecl is an interface type found while parsing classes
+type interfaceDecl struct {
+	name     string
+	pkg      string
+	dir      string
+	methods  map[string]string // Method name -> signature
+	embedded []*ast.Field      // Embedded interfaces, resolved later
+}
+
+// implementsIndex collects interfaces and method signatures across files
+// so structs can be matched against every interface in the project once
+// the walk is done, whatever order files were visited in
+type implementsIndex struct {
+	interfaces []*interfaceDecl
+	methods    map[string]map[string]string // dir|type -> method name -> signature
+}
+
+func newImplementsIndex() *implementsIndex {
+	return &implementsIndex{methods: make(map[string]map[string]string)}
+}
+
+// addInterface records an interface declared in file path
+func (idx *implementsIndex) addInterface(name, pkg, path string, iface *ast.InterfaceType) {
+	decl := &interfaceDecl{
+		name:    name,
+		pkg:     pkg,
+		dir:     filepath.Dir(path),
+		methods: make(map[string]string),
+	}
+	for _, field := range iface.Methods.List {
+		if ft, ok := field.Type.(*ast.FuncType); ok {
+			for _, n := range field.Names {
+				decl.methods[n.Name] = funcSignature(ft)
+			}
+		} else {
+			decl.embedded = append(decl.embedded, field)
+		}
+	}
+	idx.interfaces = append(idx.interfaces, decl)
+}
+
+// addMethod records a method of receiver type recv declared in file path
+func (idx *implementsIndex) addMethod(recv, path string, funcDecl *ast.FuncDecl) {
+	key := filepath.Dir(path) + "|" + recv
+	if idx.methods[key] == nil {
+		idx.methods[key] = make(map[string]string)
+	}
+	idx.methods[key][funcDecl.Name.Name] = funcSignature(funcDecl.Type)
+}
+
+// apply fills Implements on every class whose methods cover all methods
+// of a non-empty interface. Interfaces from another package are named
+// "pkg.Interface".
+func (idx *implementsIndex) apply(classes []ClassInfo) {
+	for _, decl := range idx.interfaces {
+		idx.resolve(decl, nil)
+	}
+
+	for i := range classes {
+		class := &classes[i]
+		if !strings.HasSuffix(class.File, ".go") {
+			continue
+		}
+		dir := filepath.Dir(class.File)
+		methods := idx.methods[dir+"|"+class.Name]
+		if len(methods) == 0 {
+			continue
+		}
+
+		for _, decl := range idx.interfaces {
+			if len(decl.methods) == 0 || !satisfies(methods, decl.methods) {
+				continue
+			}
+			name := decl.name
+			if decl.dir != dir {
+				name = decl.pkg + "." + name
+			}
+			class.Implements = append(class.Implements, name)
+		}
+		sort.Strings(class.Implements)
+	}
+}
+
+// resolve merges embedded interfaces into decl's method set. An embedded
+// name is looked up in decl's directory, or by package name when it is
+// qualified; unknown ones (e.g. from the standard library) are skipped.
+func (idx *implementsIndex) resolve(decl *interfaceDecl, seen []*interfaceDecl) {
+	for _, s := range seen {
+		if s == decl {
+			return // Invalid cycle; stop rather than recurse forever
+		}
+	}
+
+	for _, field := range decl.embedded {
+		var pkg, name string
+		switch t := field.Type.(type) {
+		case *ast.Ident:
+			name = t.Name
+		case *ast.SelectorExpr:
+			if x, ok := t.X.(*ast.Ident); ok {
+				pkg, name = x.Name, t.Sel.Name
+			}
+		}
+
+		for _, other := range idx.interfaces {
+			if other == decl || other.name != name {
+				continue
+			}
+			if (pkg == "" && other.dir == decl.dir) || (pkg != "" && other.pkg == pkg) {
+				idx.resolve(other, append(seen, decl))
+				for m, sig := range other.methods {
+					decl.methods[m] = sig
+				}
+				break
+			}
+		}
+	}
+	decl.embedded = nil
+}
+
+func satisfies(methods, required map[string]string) bool {
+	for name, sig := range required {
+		if methods[name] != sig {
+			return false
+		}
+	}
+	return true
+}
+
+// funcSignature formats parameter and result types, one entry per name,
+// without package qualifiers so "Request" in package http matches
+// "http.Request" used elsewhere
+func funcSignature(ft *ast.FuncType) string {
+	list := func(fields *ast.FieldList) string {
+		if fields == nil {
+			return ""
+		}
+		var types []string
+		for _, field := range fields.List {
+			t := qualifierRegex.ReplaceAllString(exprToString(field.Type), "")
+			for range max(1, len(field.Names)) {
+				types = append(types, t)
+			}
+		}
+		return strings.Join(types, ",")
+	}
+	return "(" + list(ft.Params) + ")(" + list(ft.Results) + ")"
+}
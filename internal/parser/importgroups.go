@@ -0,0 +1,219 @@
+package parser
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Import group categories, in the order Go convention expects them
+const (
+	ImportStd      = "std"
+	ImportExternal = "external"
+	ImportInternal = "internal"
+)
+
+var importGroupOrder = map[string]int{
+	ImportStd:      0,
+	ImportExternal: 1,
+	ImportInternal: 2,
+}
+
+// ImportIssue is a Go file whose imports don't follow the
+// std / third-party / internal grouping convention
+type ImportIssue struct {
+	File     string
+	Problems []string
+	Actual   [][]string // Import paths per block, as written
+	Expected [][]string // Import paths per block, as they should be
+}
+
+// CheckImportGrouping reports Go files whose imports aren't split into
+// std, external and internal blocks (separated by blank lines, in that
+// order) or aren't sorted within a block. Internal imports are those
+// under the module path in go.mod.
+func CheckImportGrouping(root string) []ImportIssue {
+	var issues []ImportIssue
+	module := readModulePath(root)
+	fset := token.NewFileSet()
+
+	ignore := LoadArcsiIgnore(root)
+	filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if skip, result := ignore.Skip(path, info); skip {
+			return result
+		}
+		if err != nil {
+			return nil
+		}
+		if info.IsDir() {
+			name := info.Name()
+			if path != root && (strings.HasPrefix(name, ".") || name == "vendor" || name == "testdata" || name == "node_modules") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(path, ".go") {
+			return nil
+		}
+
+		node, err := parser.ParseFile(fset, path, nil, parser.ImportsOnly|parser.ParseComments)
+		if err != nil || len(node.Imports) < 2 {
+			return nil
+		}
+
+		rel, _ := filepath.Rel(root, path)
+		if issue, ok := checkFileImports(fset, node, module); !ok {
+			issue.File = rel
+			issues = append(issues, issue)
+		}
+		return nil
+	})
+
+	return issues
+}
+
+// checkFileImports compares a file's import blocks against the
+// convention. It returns false when the file doesn't comply.
+func checkFileImports(fset *token.FileSet, file *ast.File, module string) (ImportIssue, bool) {
+	// Lines covered by comments don't separate groups, blank lines do
+	commentLines := make(map[int]bool)
+	for _, group := range file.Comments {
+		for line := fset.Position(group.Pos()).Line; line <= fset.Position(group.End()).Line; line++ {
+			commentLines[line] = true
+		}
+	}
+
+	var blocks [][]*ast.ImportSpec
+	for _, decl := range file.Decls {
+		genDecl, ok := decl.(*ast.GenDecl)
+		if !ok || genDecl.Tok != token.IMPORT {
+			continue
+		}
+
+		var block []*ast.ImportSpec
+		prevEnd := 0
+		for _, spec := range genDecl.Specs {
+			imp := spec.(*ast.ImportSpec)
+			start := fset.Position(imp.Pos()).Line
+			if imp.Doc != nil {
+				start = fset.Position(imp.Doc.Pos()).Line
+			}
+
+			if len(block) > 0 && hasBlankLine(prevEnd+1, start, commentLines) {
+				blocks = append(blocks, block)
+				block = nil
+			}
+			block = append(block, imp)
+			prevEnd = fset.Position(imp.End()).Line
+		}
+		if len(block) > 0 {
+			blocks = append(blocks, block)
+		}
+	}
+
+	issue := ImportIssue{}
+	byCategory := make(map[string][]string)
+	seen := make(map[string]int) // Category -> block it first appeared in
+	lastOrder := -1
+
+	for i, block := range blocks {
+		var paths []string
+		categories := make(map[string]bool)
+		for _, imp := range block {
+			path := strings.Trim(imp.Path.Value, "\"`")
+			paths = append(paths, path)
+			category := importCategory(path, module)
+			categories[category] = true
+			byCategory[category] = append(byCategory[category], path)
+		}
+		issue.Actual = append(issue.Actual, paths)
+
+		if len(categories) > 1 {
+			issue.Problems = append(issue.Problems, fmt.Sprintf("block %d mixes %s", i+1, joinCategories(categories)))
+		}
+		if !sort.StringsAreSorted(paths) {
+			issue.Problems = append(issue.Problems, fmt.Sprintf("block %d is not sorted", i+1))
+		}
+
+		for _, category := range []string{ImportStd, ImportExternal, ImportInternal} {
+			if !categories[category] {
+				continue
+			}
+			if first, ok := seen[category]; ok && first != i {
+				issue.Problems = append(issue.Problems, category+" imports are split across blocks")
+			} else if !ok {
+				seen[category] = i
+			}
+		}
+
+		if len(categories) == 1 {
+			for category := range categories {
+				if importGroupOrder[category] < lastOrder {
+					issue.Problems = append(issue.Problems, fmt.Sprintf("%s block %d comes after a later group", category, i+1))
+				}
+				lastOrder = max(lastOrder, importGroupOrder[category])
+			}
+		}
+	}
+
+	for _, category := range []string{ImportStd, ImportExternal, ImportInternal} {
+		if paths := byCategory[category]; len(paths) > 0 {
+			sorted := append([]string(nil), paths...)
+			sort.Strings(sorted)
+			issue.Expected = append(issue.Expected, sorted)
+		}
+	}
+
+	issue.Problems = dedupe(issue.Problems)
+	return issue, len(issue.Problems) == 0
+}
+
+// importCategory classifies an import path. Standard library paths have
+// no dot in their first element.
+func importCategory(path, module string) string {
+	if module != "" && (path == module || strings.HasPrefix(path, module+"/")) {
+		return ImportInternal
+	}
+	first, _, _ := strings.Cut(path, "/")
+	if !strings.Contains(first, ".") {
+		return ImportStd
+	}
+	return ImportExternal
+}
+
+// hasBlankLine reports whether any line in [from, to) is free of comments
+func hasBlankLine(from, to int, commentLines map[int]bool) bool {
+	for line := from; line < to; line++ {
+		if !commentLines[line] {
+			return true
+		}
+	}
+	return false
+}
+
+func joinCategories(categories map[string]bool) string {
+	var names []string
+	for _, category := range []string{ImportStd, ImportExternal, ImportInternal} {
+		if categories[category] {
+			names = append(names, category)
+		}
+	}
+	return strings.Join(names, " and ")
+}
+
+func dedupe(items []string) []string {
+	seen := make(map[string]bool)
+	var out []string
+	for _, item := range items {
+		if !seen[item] {
+			seen[item] = true
+			out = append(out, item)
+		}
+	}
+	return out
+}
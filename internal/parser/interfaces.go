@@ -0,0 +1,116 @@
+package parser
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// InterfaceInfo is a Go interface with its method set as declared
+type InterfaceInfo struct {
+	Name       string
+	TypeParams string // Go type parameter list, e.g. "[K comparable]"; "" if not generic
+	Package    string
+	Methods    []MethodInfo // In declaration order; Receiver is the interface
+	Embedded   []string     // Embedded interfaces and type constraints, e.g. "io.Reader", "~int | ~string"
+	File       string
+	Line       int
+	Doc        string // Doc comment text, without comment markers
+}
+
+// ParseInterfaces lists the interfaces declared in the project's Go
+// files, ordered by package, then name. Parameters and results list one
+// type per value, so "(a, b int)" is "int", "int".
+func ParseInterfaces(root string) []InterfaceInfo {
+	fset := token.NewFileSet()
+
+	results := parseFiles(goSourceFiles(root), func(path string) []InterfaceInfo {
+		node, err := parser.ParseFile(fset, path, nil, parser.ParseComments|parser.SkipObjectResolution)
+		if err != nil {
+			return nil
+		}
+
+		var ifaces []InterfaceInfo
+		for _, decl := range node.Decls {
+			genDecl, ok := decl.(*ast.GenDecl)
+			if !ok || genDecl.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range genDecl.Specs {
+				typeSpec, ok := spec.(*ast.TypeSpec)
+				if !ok {
+					continue
+				}
+				iface, ok := typeSpec.Type.(*ast.InterfaceType)
+				if !ok {
+					continue
+				}
+
+				// A lone type's doc sits on the declaration
+				doc := typeSpec.Doc
+				if doc == nil && len(genDecl.Specs) == 1 {
+					doc = genDecl.Doc
+				}
+				info := InterfaceInfo{
+					Name:       typeSpec.Name.Name,
+					TypeParams: typeParams(typeSpec.TypeParams),
+					Package:    node.Name.Name,
+					File:       path,
+					Line:       fset.Position(typeSpec.Pos()).Line,
+					Doc:        strings.TrimSpace(doc.Text()),
+				}
+
+				for _, field := range iface.Methods.List {
+					ft, ok := field.Type.(*ast.FuncType)
+					if !ok {
+						info.Embedded = append(info.Embedded, exprToString(field.Type))
+						continue
+					}
+					params, returns := fieldTypes(ft.Params), fieldTypes(ft.Results)
+					for _, name := range field.Names {
+						info.Methods = append(info.Methods, MethodInfo{
+							Name:       name.Name,
+							Receiver:   typeSpec.Name.Name,
+							Parameters: params,
+							Returns:    returns,
+							Line:       fset.Position(name.Pos()).Line,
+						})
+					}
+				}
+				ifaces = append(ifaces, info)
+			}
+		}
+		return ifaces
+	})
+
+	var ifaces []InterfaceInfo
+	for _, result := range results {
+		ifaces = append(ifaces, result...)
+	}
+	sort.SliceStable(ifaces, func(i, j int) bool {
+		a, b := ifaces[i], ifaces[j]
+		if pa, pb := filepath.Dir(a.File), filepath.Dir(b.File); pa != pb {
+			return pa < pb
+		}
+		return a.Name < b.Name
+	})
+	return ifaces
+}
+
+// fieldTypes lists the type of each value in a parameter or result list
+func fieldTypes(fields *ast.FieldList) []string {
+	if fields == nil {
+		return nil
+	}
+	var types []string
+	for _, field := range fields.List {
+		t := exprToString(field.Type)
+		for range max(1, len(field.Names)) {
+			types = append(types, t)
+		}
+	}
+	return types
+}
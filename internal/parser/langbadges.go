@@ -0,0 +1,57 @@
+package parser
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// languageTags maps source extensions to the short tags shown in tree
+// badges. Files with other extensions (docs, data, config) don't count.
+var languageTags = map[string]string{
+	".go": "go", ".py": "py", ".rs": "rs", ".rb": "rb", ".php": "php",
+	".ts": "ts", ".tsx": "ts", ".js": "js", ".jsx": "js", ".mjs": "js",
+	".java": "java", ".kt": "kt", ".kts": "kt", ".swift": "swift", ".cs": "cs",
+	".hs": "hs", ".ex": "ex", ".exs": "ex", ".erl": "erl", ".zig": "zig", ".dart": "dart", ".c": "c", ".h": "c", ".cpp": "cpp", ".cc": "cpp", ".hpp": "cpp",
+	".css": "css", ".scss": "scss", ".html": "html", ".vue": "vue", ".svelte": "svelte",
+	".sh": "sh", ".sql": "sql", ".proto": "proto",
+}
+
+// AnnotateLanguages sets Languages on node and every directory below it
+// from the files in the tree, so badges only reflect files the tree
+// shows. Tags are ordered by file count, most common first.
+func AnnotateLanguages(node *FileNode) {
+	annotateLanguages(node)
+}
+
+func annotateLanguages(node *FileNode) map[string]int {
+	counts := make(map[string]int)
+	if node == nil {
+		return counts
+	}
+	if !node.IsDir {
+		if tag, ok := languageTags[strings.ToLower(filepath.Ext(node.Name))]; ok {
+			counts[tag]++
+		}
+		return counts
+	}
+
+	for _, child := range node.Children {
+		for tag, n := range annotateLanguages(child) {
+			counts[tag] += n
+		}
+	}
+
+	node.Languages = make([]string, 0, len(counts))
+	for tag := range counts {
+		node.Languages = append(node.Languages, tag)
+	}
+	sort.Slice(node.Languages, func(i, j int) bool {
+		a, b := node.Languages[i], node.Languages[j]
+		if counts[a] != counts[b] {
+			return counts[a] > counts[b]
+		}
+		return a < b
+	})
+	return counts
+}
@@ -1,30 +1,253 @@
 package parser
 
 import (
-	"bufio"
 	"os"
 	"path/filepath"
 	"regexp"
 	"strings"
+	"unicode"
 )
 
 // Language patterns for parsing different languages
 type LanguagePattern struct {
-	Extensions []string
-	ClassRegex *regexp.Regexp
-	FuncRegex  *regexp.Regexp
-	ImportRegex *regexp.Regexp
-	StructRegex *regexp.Regexp
+	Extensions     []string
+	ClassRegex     *regexp.Regexp
+	FuncRegex      *regexp.Regexp
+	ImportRegex    *regexp.Regexp
+	StructRegex    *regexp.Regexp
 	InterfaceRegex *regexp.Regexp
+
+	// FieldRegex matches a field declared directly in a class body, with
+	// "name" and optional "type" groups. Brace languages only look one
+	// level inside the class's braces; NoBraces languages, whose blocks
+	// end by indentation or "end", match anywhere after the class line.
+	FieldRegex *regexp.Regexp
+	NoBraces   bool
+
+	// MethodRegex matches a method declared directly in a class body, for
+	// languages whose FuncRegex only finds free functions
+	MethodRegex *regexp.Regexp
+
+	// A method's return type is captured by ReturnPrefix from the text
+	// before its name, as in Java's "int size(", or by ReturnSuffix from
+	// the text after its parameters, as in TypeScript's "): number {"
+	ReturnPrefix *regexp.Regexp
+	ReturnSuffix *regexp.Regexp
+
+	// Comment markers and string quotes, for commentFilter
+	LineComments  []string
+	BlockComments [][2]string
+	Quotes        string
+
+	// Keywords lists words that rule out a FuncRegex match when they are
+	// the captured name or come before it, such as the "return" in a C++
+	// "return make(x);" that looks like a declaration
+	Keywords map[string]bool
+
+	// Clauses marks languages that write one function as several clauses
+	// (or a signature and equations), each of which FuncRegex matches, so
+	// only the first line naming a function counts
+	Clauses bool
+
+	// FreeFunctions marks languages whose functions don't belong to the
+	// types declared above them, so none are taken as methods
+	FreeFunctions bool
+}
+
+// cKeywords are C and C++ statements that FuncRegex can mistake for a
+// return type or function name
+var cKeywords = map[string]bool{
+	"if": true, "else": true, "for": true, "while": true, "switch": true,
+	"return": true, "case": true, "goto": true, "sizeof": true, "new": true,
+	"delete": true, "throw": true, "catch": true, "co_return": true, "co_yield": true,
+}
+
+// haskellKeywords are declaration keywords that the start of a Haskell
+// equation would otherwise match
+var haskellKeywords = map[string]bool{
+	"module": true, "import": true, "data": true, "newtype": true, "type": true,
+	"class": true, "instance": true, "deriving": true, "where": true,
+	"infix": true, "infixl": true, "infixr": true, "foreign": true, "default": true,
+	"let": true, "in": true, "if": true, "then": true, "else": true, "case": true, "of": true, "do": true,
+}
+
+// dartKeywords are Dart statements and modifiers that FuncRegex can
+// mistake for a return type, such as the "await" in "await load(x);", or
+// FieldRegex for a type
+var dartKeywords = map[string]bool{
+	"if": true, "else": true, "for": true, "while": true, "switch": true, "case": true,
+	"return": true, "await": true, "yield": true, "throw": true, "new": true, "const": true,
+	"assert": true, "catch": true, "final": true, "var": true, "is": true, "as": true,
+}
+
+// tsFieldRegex matches TypeScript and JavaScript class properties, such
+// as "private readonly name: string;", "count = 0" or "#secret?: Key"
+var tsFieldRegex = regexp.MustCompile(`^\s*(?:(?:public|private|protected|readonly|static|declare|override|accessor)\s+)*(?P<name>#?[A-Za-z_$][\w$]*)[?!]?\s*(?::\s*(?P<type>(?:[^=;{]|=>)+?)\s*(?:=[^>].*)?|=[^>].*)\s*;?\s*$`)
+
+// tsMethodRegex matches TypeScript and JavaScript class methods, such as
+// "static async load<T>(" or "get size("
+var tsMethodRegex = regexp.MustCompile(`^\s*(?:(?:public|private|protected|static|async|override|abstract|get|set)\s+)*\*?\s*(#?[A-Za-z_$][\w$]*)[?!]?\s*(?:<[^>]*>)?\s*\(`)
+
+// tsReturnRegex matches a TypeScript return type annotation, "): T {"
+var tsReturnRegex = regexp.MustCompile(`^\s*:\s*(.+?)\s*(?:\{|;|=>|$)`)
+
+// cFieldRegex matches C struct and C++ class members, e.g. "int *next;"
+// or "std::vector<int> items{};"
+var cFieldRegex = regexp.MustCompile(`^\s*(?:(?:static|const|mutable|volatile|unsigned|signed|struct|enum)\s+)*(?P<type>[A-Za-z_][\w:]*(?:<[^;()]*>)?[\s*&]+)(?P<name>\w+)\s*(?:\[[^\]]*\])?\s*(?:=[^;]*|\{[^}]*\})?;\s*$`)
+
+// field returns the field declared on line, if any
+func (lang *LanguagePattern) field(line string) (FieldInfo, bool) {
+	matches := lang.FieldRegex.FindStringSubmatch(line)
+	if matches == nil {
+		return FieldInfo{}, false
+	}
+	field := FieldInfo{Name: matches[lang.FieldRegex.SubexpIndex("name")]}
+	if i := lang.FieldRegex.SubexpIndex("type"); i >= 0 {
+		field.Type = strings.TrimSpace(matches[i])
+	}
+	if lang.Keywords[field.Type] || lang.Keywords[field.Name] {
+		return FieldInfo{}, false
+	}
+	return field, field.Name != ""
+}
+
+// funcName returns the function declared on line, or ""
+func (lang *LanguagePattern) funcName(line string) string {
+	if _, start, end := lang.funcSpan(lang.FuncRegex, line); start >= 0 {
+		return line[start:end]
+	}
+	return ""
+}
+
+// funcSpan returns where re's match on line begins and the bounds of the
+// name it captured, with start -1 when there is no declaration
+func (lang *LanguagePattern) funcSpan(re *regexp.Regexp, line string) (match, start, end int) {
+	loc := re.FindStringSubmatchIndex(line)
+	if loc == nil {
+		return -1, -1, -1
+	}
+	// Languages with two function forms capture the name in either group
+	start, end = -1, -1
+	for g := 2; g+1 < len(loc); g += 2 {
+		if loc[g] >= 0 && loc[g+1] > loc[g] {
+			start, end = loc[g], loc[g+1]
+			break
+		}
+	}
+	if start < 0 {
+		return -1, -1, -1
+	}
+	if lang.Keywords != nil {
+		if lang.Keywords[line[start:end]] {
+			return -1, -1, -1
+		}
+		for _, word := range strings.FieldsFunc(line[loc[0]:start], func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_'
+		}) {
+			if lang.Keywords[word] {
+				return -1, -1, -1
+			}
+		}
+	}
+	return loc[0], start, end
+}
+
+// method returns the method declared on line, with its raw parameters
+// and, where the declaration spells it out, its return type. blanked is
+// line with string contents blanked out, as commentFilter.strip returns
+// it; declarations are found on it and their text taken from line.
+// inBody reports whether the line sits directly in the class body, where
+// MethodRegex applies.
+func (lang *LanguagePattern) method(line, blanked string, inBody bool) (MethodInfo, bool) {
+	match, start, end := lang.funcSpan(lang.FuncRegex, blanked)
+	if start < 0 && inBody && lang.MethodRegex != nil {
+		match, start, end = lang.funcSpan(lang.MethodRegex, blanked)
+	}
+	if start < 0 {
+		return MethodInfo{}, false
+	}
+	method := MethodInfo{Name: blanked[start:end]}
+
+	open := strings.IndexByte(blanked[end:], '(')
+	if open < 0 {
+		return method, true
+	}
+	params, after := splitParams(line, blanked, end+open)
+	// Python's explicit receiver isn't a parameter to callers
+	if len(params) > 0 && (params[0] == "self" || params[0] == "cls") {
+		params = params[1:]
+	}
+	method.Parameters = params
+
+	var returns string
+	if lang.ReturnPrefix != nil {
+		if m := lang.ReturnPrefix.FindStringSubmatch(line[match:start]); m != nil {
+			returns = m[1]
+		}
+	}
+	if lang.ReturnSuffix != nil && after >= 0 {
+		if m := lang.ReturnSuffix.FindStringSubmatch(line[after:]); m != nil {
+			returns = m[1]
+		}
+	}
+	switch returns = strings.TrimSpace(returns); returns {
+	case "", "void", "None":
+	default:
+		method.Returns = []string{returns}
+	}
+	return method, true
+}
+
+// splitParams splits the parameter list that opens at line[open] on its
+// top-level commas, and returns the index just past its closing paren.
+// A list that continues onto the next line is cut at the end of this one,
+// and the index is -1.
+func splitParams(line, blanked string, open int) ([]string, int) {
+	var params []string
+	depth, from := 0, open+1
+	add := func(to int) {
+		if param := strings.TrimSpace(line[from:to]); param != "" {
+			params = append(params, param)
+		}
+		from = to + 1
+	}
+	for i := open + 1; i < len(blanked); i++ {
+		switch blanked[i] {
+		case '(', '[', '{', '<':
+			depth++
+		case '>':
+			// The arrow of a function type isn't a closing bracket
+			if blanked[i-1] != '=' {
+				depth--
+			}
+		case ']', '}':
+			depth--
+		case ')':
+			if depth == 0 {
+				add(i)
+				return params, i + 1
+			}
+			depth--
+		case ',':
+			if depth == 0 {
+				add(i)
+			}
+		}
+	}
+	add(len(line))
+	return params, -1
 }
 
 var languagePatterns = map[string]*LanguagePattern{
 	"go": {
 		Extensions:     []string{".go"},
-		ClassRegex:     regexp.MustCompile(`type\s+(\w+)\s+struct\s*\{`),
-		FuncRegex:      regexp.MustCompile(`func\s+(?:\([^)]+\)\s+)?(\w+)\s*\(`),
+		ClassRegex:     regexp.MustCompile(`type\s+(\w+)(?:\[.*?\])?\s+struct\s*\{`),
+		FuncRegex:      regexp.MustCompile(`func\s+(?:\([^)]+\)\s+)?(\w+)(?:\[.*?\])?\s*\(`),
 		ImportRegex:    regexp.MustCompile(`import\s+(?:\(\s*)?["']([^"']+)["']`),
-		InterfaceRegex: regexp.MustCompile(`type\s+(\w+)\s+interface\s*\{`),
+		InterfaceRegex: regexp.MustCompile(`type\s+(\w+)(?:\[.*?\])?\s+interface\s*\{`),
+		LineComments:   slashComments,
+		BlockComments:  starComments,
+		Quotes:         "\"'`",
 	},
 	"java": {
 		Extensions:     []string{".java"},
@@ -32,6 +255,12 @@ var languagePatterns = map[string]*LanguagePattern{
 		FuncRegex:      regexp.MustCompile(`(?:public\s+|private\s+|protected\s+)?(?:static\s+)?(?:final\s+)?(?:synchronized\s+)?(?:\w+(?:<[^>]+>)?)\s+(\w+)\s*\(`),
 		ImportRegex:    regexp.MustCompile(`import\s+(?:static\s+)?([^;]+);`),
 		InterfaceRegex: regexp.MustCompile(`(?:public\s+|private\s+|protected\s+)?interface\s+(\w+)`),
+		FieldRegex:     regexp.MustCompile(`^\s*(?:(?:public|private|protected|static|final|transient|volatile)\s+)*(?P<type>[\w.]+(?:<[^;=()]*>)?(?:\[\])*)\s+(?P<name>\w+)\s*(?:=.*)?;\s*$`),
+		ReturnPrefix:   regexp.MustCompile(`^\s*(?:(?:public|private|protected|static|final|synchronized|abstract|default|native)\s+)*(?:<[^>]*>\s*)?(\S.*)$`),
+		LineComments:   slashComments,
+		BlockComments:  starComments,
+		Quotes:         `"'`,
+		Keywords:       cKeywords,
 	},
 	"kotlin": {
 		Extensions:     []string{".kt", ".kts"},
@@ -39,6 +268,10 @@ var languagePatterns = map[string]*LanguagePattern{
 		FuncRegex:      regexp.MustCompile(`fun\s+(?:<[^>]+>\s+)?(\w+)\s*\(`),
 		ImportRegex:    regexp.MustCompile(`import\s+([^\s]+)`),
 		InterfaceRegex: regexp.MustCompile(`interface\s+(\w+)`),
+		FieldRegex:     regexp.MustCompile(`^\s*(?:(?:private|protected|public|internal|override|open|lateinit|const)\s+)*(?:val|var)\s+(?P<name>\w+)\s*(?::\s*(?P<type>[^=]+?))?\s*(?:(?:=|by\s).*)?$`),
+		LineComments:   slashComments,
+		BlockComments:  starComments,
+		Quotes:         `"'`,
 	},
 	"python": {
 		Extensions:     []string{".py"},
@@ -46,6 +279,12 @@ var languagePatterns = map[string]*LanguagePattern{
 		FuncRegex:      regexp.MustCompile(`def\s+(\w+)\s*\(`),
 		ImportRegex:    regexp.MustCompile(`(?:from\s+(\S+)\s+)?import\s+([^#\n]+)`),
 		InterfaceRegex: nil, // Python uses ABC
+		FieldRegex:     regexp.MustCompile(`^\s*self\.(?P<name>\w+)\s*(?::\s*(?P<type>[^=]+?))?\s*=[^=]`),
+		ReturnSuffix:   regexp.MustCompile(`^\s*->\s*(.+?)\s*:`),
+		NoBraces:       true,
+		LineComments:   hashComments,
+		BlockComments:  [][2]string{{`"""`, `"""`}, {"'''", "'''"}},
+		Quotes:         `"'`,
 	},
 	"typescript": {
 		Extensions:     []string{".ts", ".tsx"},
@@ -53,6 +292,12 @@ var languagePatterns = map[string]*LanguagePattern{
 		FuncRegex:      regexp.MustCompile(`(?:export\s+)?(?:async\s+)?function\s+(\w+)|(?:const|let|var)\s+(\w+)\s*=\s*(?:async\s+)?\(`),
 		ImportRegex:    regexp.MustCompile(`import\s+(?:{[^}]+}|\*\s+as\s+\w+|\w+)\s+from\s+['"]([^'"]+)['"]`),
 		InterfaceRegex: regexp.MustCompile(`(?:export\s+)?interface\s+(\w+)`),
+		FieldRegex:     tsFieldRegex,
+		MethodRegex:    tsMethodRegex,
+		ReturnSuffix:   tsReturnRegex,
+		LineComments:   slashComments,
+		BlockComments:  starComments,
+		Quotes:         "\"'`",
 	},
 	"javascript": {
 		Extensions:     []string{".js", ".jsx", ".mjs"},
@@ -60,6 +305,11 @@ var languagePatterns = map[string]*LanguagePattern{
 		FuncRegex:      regexp.MustCompile(`(?:export\s+)?(?:async\s+)?function\s+(\w+)|(?:const|let|var)\s+(\w+)\s*=\s*(?:async\s+)?\(`),
 		ImportRegex:    regexp.MustCompile(`import\s+(?:{[^}]+}|\*\s+as\s+\w+|\w+)\s+from\s+['"]([^'"]+)['"]|require\s*\(\s*['"]([^'"]+)['"]\s*\)`),
 		InterfaceRegex: nil,
+		FieldRegex:     tsFieldRegex,
+		MethodRegex:    tsMethodRegex,
+		LineComments:   slashComments,
+		BlockComments:  starComments,
+		Quotes:         "\"'`",
 	},
 	"swift": {
 		Extensions:     []string{".swift"},
@@ -68,6 +318,10 @@ var languagePatterns = map[string]*LanguagePattern{
 		ImportRegex:    regexp.MustCompile(`import\s+(\w+)`),
 		InterfaceRegex: regexp.MustCompile(`protocol\s+(\w+)`),
 		StructRegex:    regexp.MustCompile(`struct\s+(\w+)`),
+		FieldRegex:     regexp.MustCompile(`^\s*(?:(?:private|fileprivate|internal|public|open|static|class|final|lazy|weak|unowned)(?:\(set\))?\s+)*(?:@\w+\s+)*(?:var|let)\s+(?P<name>\w+)\s*(?::\s*(?P<type>[^={]+?))?\s*(?:[={].*)?$`),
+		LineComments:   slashComments,
+		BlockComments:  starComments,
+		Quotes:         `"`,
 	},
 	"csharp": {
 		Extensions:     []string{".cs"},
@@ -76,6 +330,10 @@ var languagePatterns = map[string]*LanguagePattern{
 		ImportRegex:    regexp.MustCompile(`using\s+(?:static\s+)?([^;]+);`),
 		InterfaceRegex: regexp.MustCompile(`(?:public\s+|private\s+|protected\s+|internal\s+)?interface\s+(\w+)`),
 		StructRegex:    regexp.MustCompile(`(?:public\s+|private\s+)?struct\s+(\w+)`),
+		FieldRegex:     regexp.MustCompile(`^\s*(?:(?:public|private|protected|internal|static|readonly|const|volatile|required)\s+)*(?P<type>[\w.]+(?:<[^;=()]*>)?(?:\[\])?\??)\s+(?P<name>\w+)\s*(?:\{\s*(?:get|set|init).*|=.*|;)\s*$`),
+		LineComments:   slashComments,
+		BlockComments:  starComments,
+		Quotes:         `"'`,
 	},
 	"rust": {
 		Extensions:     []string{".rs"},
@@ -83,9 +341,148 @@ var languagePatterns = map[string]*LanguagePattern{
 		FuncRegex:      regexp.MustCompile(`(?:pub\s+)?(?:async\s+)?fn\s+(\w+)`),
 		ImportRegex:    regexp.MustCompile(`use\s+([^;]+);`),
 		InterfaceRegex: regexp.MustCompile(`trait\s+(\w+)`),
+		FieldRegex:     regexp.MustCompile(`^\s*(?:pub(?:\([^)]*\))?\s+)?(?P<name>\w+)\s*:\s*(?P<type>[^,]+?),?\s*$`),
+		LineComments:   slashComments,
+		BlockComments:  starComments,
+		Quotes:         `"`,
+	},
+	"ruby": {
+		Extensions: []string{".rb"},
+		// Namespaced names keep the last segment; "class << self" is skipped
+		ClassRegex:    regexp.MustCompile(`^\s*class\s+(?:\w+::)*([A-Z]\w*)`),
+		StructRegex:   regexp.MustCompile(`^\s*module\s+(?:\w+::)*([A-Z]\w*)`),
+		FuncRegex:     regexp.MustCompile(`^\s*def\s+(?:self\.)?(\w+[?!=]?)`),
+		ImportRegex:   regexp.MustCompile(`^\s*require(?:_relative)?[\s(]+['"]([^'"]+)['"]`),
+		FieldRegex:    regexp.MustCompile(`^\s*attr_(?:accessor|reader|writer)\s+:(?P<name>\w+)`),
+		NoBraces:      true,
+		LineComments:  hashComments,
+		BlockComments: [][2]string{{"=begin", "=end"}},
+		Quotes:        `"'`,
+	},
+	"php": {
+		Extensions:     []string{".php"},
+		ClassRegex:     regexp.MustCompile(`^\s*(?:(?:abstract|final|readonly)\s+)*class\s+(\w+)`),
+		InterfaceRegex: regexp.MustCompile(`^\s*interface\s+(\w+)`),
+		// Anchored so closures ("$f = function (...)") don't count
+		FuncRegex:     regexp.MustCompile(`^\s*(?:(?:public|private|protected|static|abstract|final)\s+)*function\s+&?(\w+)\s*\(`),
+		ImportRegex:   regexp.MustCompile(`^\s*(?:use\s+([\w\\]+)|(?:require|include)(?:_once)?[\s(]+['"]([^'"]+)['"])`),
+		FieldRegex:    regexp.MustCompile(`^\s*(?:(?:public|private|protected|static|readonly|var)\s+)+(?:\??(?P<type>[\w\\|]+)\s+)?\$(?P<name>\w+)\s*(?:=.*)?;`),
+		LineComments:  []string{"//", "#"},
+		BlockComments: starComments,
+		Quotes:        `"'`,
+	},
+	"haskell": {
+		Extensions: []string{".hs"},
+		// data, newtype and type declarations stand in for structs, and
+		// typeclasses for interfaces
+		StructRegex:    regexp.MustCompile(`^(?:data|newtype|type)\s+([A-Z][\w']*)`),
+		InterfaceRegex: regexp.MustCompile(`^class\s+(?:.*=>\s*)?([A-Z][\w']*)`),
+		// Top-level signatures, "name :: Type", and equations, "name args = ..."
+		FuncRegex:     regexp.MustCompile(`^([a-z_][\w']*)\s*::|^([a-z_][\w']*)(?:\s[^=]*)?\s=(?:\s|$)`),
+		ImportRegex:   regexp.MustCompile(`^import\s+(?:qualified\s+)?([A-Z][\w.]*)`),
+		FieldRegex:    regexp.MustCompile(`^\s*[{,]\s*(?P<name>[a-z_][\w']*)\s*::\s*(?P<type>[^,}]+?)\s*}?\s*$`),
+		NoBraces:      true,
+		Clauses:       true,
+		FreeFunctions: true,
+		Keywords:      haskellKeywords,
+		LineComments:  []string{"--"},
+		BlockComments: [][2]string{{"{-", "-}"}},
+		Quotes:        `"`,
+	},
+	"elixir": {
+		Extensions: []string{".ex", ".exs"},
+		// A defmodule holds the defs that follow it, up to the next one
+		ClassRegex:    regexp.MustCompile(`^\s*defmodule\s+([A-Z][\w.]*)`),
+		FuncRegex:     regexp.MustCompile(`^\s*defp?\s+([a-z_]\w*[?!]?)`),
+		ImportRegex:   regexp.MustCompile(`^\s*(?:alias|import|require|use)\s+([A-Z]\w*(?:\.[A-Z]\w*)*)`),
+		NoBraces:      true,
+		Clauses:       true,
+		LineComments:  hashComments,
+		BlockComments: [][2]string{{`"""`, `"""`}}, // @doc heredocs
+		Quotes:        `"'`,
+	},
+	"erlang": {
+		Extensions:   []string{".erl", ".hrl"},
+		ClassRegex:   regexp.MustCompile(`^-module\(\s*(\w+)\s*\)`),
+		FuncRegex:    regexp.MustCompile(`^([a-z]\w*)\s*\(.*->`),
+		ImportRegex:  regexp.MustCompile(`^-(?:import\(\s*(\w+)|include(?:_lib)?\(\s*"([^"]+)")`),
+		NoBraces:     true,
+		Clauses:      true,
+		LineComments: []string{"%"},
+		Quotes:       `"`,
+	},
+	"zig": {
+		Extensions: []string{".zig"},
+		// Types are values: "const Point = struct {"
+		ClassRegex:   regexp.MustCompile(`^\s*(?:pub\s+)?const\s+(\w+)\s*=\s*(?:extern\s+|packed\s+)?struct\b`),
+		FuncRegex:    regexp.MustCompile(`^\s*(?:pub\s+)?(?:export\s+|extern\s+|inline\s+)?fn\s+(\w+)\s*\(`),
+		ImportRegex:  regexp.MustCompile(`@import\(\s*"([^"]+)"\s*\)`),
+		FieldRegex:   regexp.MustCompile(`^\s*(?P<name>\w+)\s*:\s*(?P<type>[^=,;]+?)\s*(?:=[^,;]*)?,?\s*$`),
+		ReturnSuffix: regexp.MustCompile(`^\s*([^{]+?)\s*\{`),
+		LineComments: slashComments,
+		Quotes:       `"'`,
+	},
+	"dart": {
+		Extensions: []string{".dart"},
+		ClassRegex: regexp.MustCompile(`^\s*(?:(?:abstract|base|final|sealed|interface)\s+)*(?:class|mixin(?:\s+class)?)\s+(\w+)`),
+		// A return type is required, so calls and constructors, which
+		// share the class's name, don't count
+		FuncRegex:     regexp.MustCompile(`^\s*(?:(?:static|external|abstract)\s+)*[\w.]+(?:<[^()]*>)?\??\s+(\w+)\s*(?:<[^>]*>)?\s*\(`),
+		ImportRegex:   regexp.MustCompile(`^\s*(?:import|export)\s+['"]([^'"]+)['"]`),
+		FieldRegex:    regexp.MustCompile(`^\s*(?:(?:static|late|covariant|external)\s+)*(?:final\s+|const\s+)?(?P<type>[\w.]+(?:<[^;=()]*>)?\??)\s+(?P<name>\w+)\s*(?:=[^;]*)?;\s*$`),
+		ReturnPrefix:  regexp.MustCompile(`^\s*(?:(?:static|external|abstract)\s+)*(\S.*)$`),
+		Keywords:      dartKeywords,
+		LineComments:  slashComments,
+		BlockComments: starComments,
+		Quotes:        `"'`,
+	},
+	"c": {
+		Extensions: []string{".c", ".h"},
+		// Struct definitions, not "struct point *p;" uses or declarations
+		ClassRegex: regexp.MustCompile(`^\s*(?:typedef\s+)?struct\s+(\w+)\s*(?:\{.*)?$`),
+		// Definitions start in column 0; prototypes end in ';'
+		FuncRegex:     regexp.MustCompile(`^(?:(?:static|inline|extern)\s+)*(?:(?:const|unsigned|signed|struct|enum)\s+)*\w+[\s*]+(\w+)\s*\([^;]*$`),
+		ImportRegex:   regexp.MustCompile(`^\s*#\s*include\s*(?:<([^>]+)>|"([^"]+)")`),
+		Keywords:      cKeywords,
+		FieldRegex:    cFieldRegex,
+		LineComments:  slashComments,
+		BlockComments: starComments,
+		Quotes:        `"'`,
+	},
+	"cpp": {
+		Extensions: []string{".cpp", ".cc", ".hpp", ".hxx"},
+		// Skips forward declarations; allows an export macro before the name
+		ClassRegex: regexp.MustCompile(`^\s*(?:template\s*<.*>\s*)?(?:class|struct)\s+(?:[A-Z][A-Z0-9_]*\s+)?(\w+)\b[^;]*$`),
+		// Indented too, so methods declared in a class body attach to it
+		FuncRegex:     regexp.MustCompile(`^\s*(?:(?:static|inline|virtual|explicit|constexpr|friend|extern)\s+)*(?:const\s+)?[A-Za-z_][\w:]*(?:<[^()]*>)?[\s*&]+(?:\w+::)*~?(\w+)\s*\(`),
+		ImportRegex:   regexp.MustCompile(`^\s*#\s*include\s*(?:<([^>]+)>|"([^"]+)")`),
+		Keywords:      cKeywords,
+		FieldRegex:    cFieldRegex,
+		LineComments:  slashComments,
+		BlockComments: starComments,
+		Quotes:        `"'`,
 	},
 }
 
+// goPackageRegex finds the package clause of a Go file
+var goPackageRegex = regexp.MustCompile(`(?m)^package\s+(\w+)`)
+
+// goFallbackSource reads a Go file that go/parser rejected (for example
+// because it uses syntax newer than arcsii's toolchain) so the regex
+// patterns can still extract approximate symbols from it
+func goFallbackSource(path string) (src, pkg string, ok bool) {
+	src, ok = readSource(path)
+	if !ok {
+		return "", "", false
+	}
+
+	pkg = filepath.Base(filepath.Dir(path))
+	if matches := goPackageRegex.FindStringSubmatch(src); len(matches) > 1 {
+		pkg = matches[1]
+	}
+	return src, pkg, true
+}
+
 // getLanguageForFile returns the language pattern for a file extension
 func getLanguageForFile(filename string) *LanguagePattern {
 	ext := strings.ToLower(filepath.Ext(filename))
@@ -99,11 +496,39 @@ func getLanguageForFile(filename string) *LanguagePattern {
 	return nil
 }
 
+// languageName returns the languagePatterns key for a file, or ""
+func languageName(filename string) string {
+	ext := strings.ToLower(filepath.Ext(filename))
+	for name, pattern := range languagePatterns {
+		for _, e := range pattern.Extensions {
+			if e == ext {
+				return name
+			}
+		}
+	}
+	return ""
+}
+
+// todoRegex matches TODO markers in comments
+var todoRegex = regexp.MustCompile(`\bTODO\b`)
+
 // ParseClassesMultiLang extracts class/struct info from multiple languages
 func ParseClassesMultiLang(root string) []ClassInfo {
+	classes, _ := ParseTypesMultiLang(root)
+	return classes
+}
+
+// ParseTypesMultiLang extracts classes and structs, and separately
+// interfaces, from multiple languages
+func ParseTypesMultiLang(root string) ([]ClassInfo, []InterfaceInfo) {
 	var classes []ClassInfo
+	var ifaces []InterfaceInfo
 
+	ignore := LoadIgnore(root)
 	filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if skip, result := ignore.Skip(path, info); skip {
+			return result
+		}
 		if err != nil || info.IsDir() {
 			return nil
 		}
@@ -124,11 +549,10 @@ func ParseClassesMultiLang(root string) []ClassInfo {
 			return nil
 		}
 
-		file, err := os.Open(path)
-		if err != nil {
+		src, ok := readSource(path)
+		if !ok {
 			return nil
 		}
-		defer file.Close()
 
 		// Get relative package/module name
 		rel, _ := filepath.Rel(root, path)
@@ -137,84 +561,24 @@ func ParseClassesMultiLang(root string) []ClassInfo {
 			pkg = "root"
 		}
 
-		scanner := bufio.NewScanner(file)
-		lineNum := 0
-		var currentClass *ClassInfo
-
-		for scanner.Scan() {
-			line := scanner.Text()
-			lineNum++
-
-			// Find classes
-			if lang.ClassRegex != nil {
-				if matches := lang.ClassRegex.FindStringSubmatch(line); len(matches) > 1 {
-					if currentClass != nil {
-						classes = append(classes, *currentClass)
-					}
-					currentClass = &ClassInfo{
-						Name:    matches[1],
-						Package: pkg,
-						File:    path,
-					}
-				}
-			}
-
-			// Find structs (for languages that have them separately)
-			if lang.StructRegex != nil {
-				if matches := lang.StructRegex.FindStringSubmatch(line); len(matches) > 1 {
-					if currentClass != nil {
-						classes = append(classes, *currentClass)
-					}
-					currentClass = &ClassInfo{
-						Name:    matches[1],
-						Package: pkg,
-						File:    path,
-					}
-				}
-			}
-
-			// Find interfaces
-			if lang.InterfaceRegex != nil {
-				if matches := lang.InterfaceRegex.FindStringSubmatch(line); len(matches) > 1 {
-					classes = append(classes, ClassInfo{
-						Name:    matches[1] + " (interface)",
-						Package: pkg,
-						File:    path,
-					})
-				}
-			}
-
-			// Find methods for current class
-			if currentClass != nil && lang.FuncRegex != nil {
-				if matches := lang.FuncRegex.FindStringSubmatch(line); len(matches) > 1 {
-					methodName := matches[1]
-					if methodName == "" && len(matches) > 2 {
-						methodName = matches[2]
-					}
-					if methodName != "" && methodName != currentClass.Name {
-						currentClass.Methods = append(currentClass.Methods, MethodInfo{
-							Name: methodName,
-						})
-					}
-				}
-			}
-		}
-
-		if currentClass != nil {
-			classes = append(classes, *currentClass)
-		}
-
+		fileClasses, fileIfaces := scanClasses(src, path, pkg, lang)
+		classes = append(classes, fileClasses...)
+		ifaces = append(ifaces, fileIfaces...)
 		return nil
 	})
 
-	return classes
+	return classes, ifaces
 }
 
 // ParseFunctionsMultiLang extracts functions from multiple languages
 func ParseFunctionsMultiLang(root string) []FunctionInfo {
 	var funcs []FunctionInfo
 
+	ignore := LoadIgnore(root)
 	filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if skip, result := ignore.Skip(path, info); skip {
+			return result
+		}
 		if err != nil || info.IsDir() {
 			return nil
 		}
@@ -233,11 +597,10 @@ func ParseFunctionsMultiLang(root string) []FunctionInfo {
 			return nil
 		}
 
-		file, err := os.Open(path)
-		if err != nil {
+		src, ok := readSource(path)
+		if !ok {
 			return nil
 		}
-		defer file.Close()
 
 		rel, _ := filepath.Rel(root, path)
 		pkg := filepath.Dir(rel)
@@ -245,29 +608,7 @@ func ParseFunctionsMultiLang(root string) []FunctionInfo {
 			pkg = "root"
 		}
 
-		scanner := bufio.NewScanner(file)
-		lineNum := 0
-
-		for scanner.Scan() {
-			line := scanner.Text()
-			lineNum++
-
-			if matches := lang.FuncRegex.FindStringSubmatch(line); len(matches) > 1 {
-				funcName := matches[1]
-				if funcName == "" && len(matches) > 2 {
-					funcName = matches[2]
-				}
-				if funcName != "" {
-					funcs = append(funcs, FunctionInfo{
-						Name:    funcName,
-						Package: pkg,
-						File:    path,
-						Line:    lineNum,
-					})
-				}
-			}
-		}
-
+		funcs = append(funcs, scanFunctions(src, path, pkg, lang)...)
 		return nil
 	})
 
@@ -278,7 +619,11 @@ func ParseFunctionsMultiLang(root string) []FunctionInfo {
 func ParseDependenciesMultiLang(root string) []Dependency {
 	var deps []Dependency
 
+	ignore := LoadIgnore(root)
 	filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if skip, result := ignore.Skip(path, info); skip {
+			return result
+		}
 		if err != nil || info.IsDir() {
 			return nil
 		}
@@ -297,11 +642,10 @@ func ParseDependenciesMultiLang(root string) []Dependency {
 			return nil
 		}
 
-		file, err := os.Open(path)
-		if err != nil {
+		src, ok := readSource(path)
+		if !ok {
 			return nil
 		}
-		defer file.Close()
 
 		rel, _ := filepath.Rel(root, path)
 		pkg := filepath.Dir(rel)
@@ -309,30 +653,7 @@ func ParseDependenciesMultiLang(root string) []Dependency {
 			pkg = "root"
 		}
 
-		scanner := bufio.NewScanner(file)
-		seen := make(map[string]bool)
-
-		for scanner.Scan() {
-			line := scanner.Text()
-
-			if matches := lang.ImportRegex.FindStringSubmatch(line); len(matches) > 1 {
-				importPath := matches[1]
-				if importPath == "" && len(matches) > 2 {
-					importPath = matches[2]
-				}
-				importPath = strings.TrimSpace(importPath)
-
-				if importPath != "" && !seen[importPath] {
-					seen[importPath] = true
-					deps = append(deps, Dependency{
-						From:    rel,
-						To:      importPath,
-						Package: pkg,
-					})
-				}
-			}
-		}
-
+		deps = append(deps, scanImports(src, rel, pkg, lang)...)
 		return nil
 	})
 
@@ -344,7 +665,11 @@ func ParseStructureMultiLang(root string) Structure {
 	structure := Structure{}
 	packageMap := make(map[string]*ModuleInfo)
 
+	ignore := LoadIgnore(root)
 	filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if skip, result := ignore.Skip(path, info); skip {
+			return result
+		}
 		if err != nil || info.IsDir() {
 			return nil
 		}
@@ -381,20 +706,23 @@ func ParseStructureMultiLang(root string) Structure {
 		mod.Files = append(mod.Files, name)
 
 		// Check for entry points
-		if name == "main.go" || name == "main.py" || name == "index.js" || name == "index.ts" || name == "App.tsx" || name == "Main.java" || name == "Program.cs" || name == "main.swift" || name == "main.rs" {
+		if name == "main.go" || name == "main.py" || name == "index.js" || name == "index.ts" || name == "App.tsx" || name == "Main.java" || name == "Program.cs" || name == "main.swift" || name == "main.rs" || name == "main.c" || name == "main.cpp" {
 			structure.MainFiles = append(structure.MainFiles, path)
 		}
 
-		// Parse for structs and functions
-		file, err := os.Open(path)
-		if err != nil {
+		// Parse for structs and functions; a file that can't be read
+		// counts no lines
+		src, ok := readSource(path)
+		if !ok {
 			return nil
 		}
-		defer file.Close()
+		mod.Lines += len(strings.Split(src, "\n"))
 
-		scanner := bufio.NewScanner(file)
+		scanner := newLineScanner(src)
+		comments := newCommentFilter(lang)
+		seen := make(map[string]bool)
 		for scanner.Scan() {
-			line := scanner.Text()
+			_, line := comments.strip(scanner.Text())
 
 			if lang.ClassRegex != nil {
 				if matches := lang.ClassRegex.FindStringSubmatch(line); len(matches) > 1 {
@@ -409,14 +737,9 @@ func ParseStructureMultiLang(root string) Structure {
 			}
 
 			if lang.FuncRegex != nil {
-				if matches := lang.FuncRegex.FindStringSubmatch(line); len(matches) > 1 {
-					funcName := matches[1]
-					if funcName == "" && len(matches) > 2 {
-						funcName = matches[2]
-					}
-					if funcName != "" {
-						mod.Funcs = append(mod.Funcs, funcName)
-					}
+				if funcName := lang.funcName(line); funcName != "" && !(lang.Clauses && seen[funcName]) {
+					seen[funcName] = true
+					mod.Funcs = append(mod.Funcs, funcName)
 				}
 			}
 		}
@@ -430,3 +753,213 @@ func ParseStructureMultiLang(root string) Structure {
 
 	return structure
 }
+
+// scanClasses extracts classes and structs, and separately interfaces,
+// from one source file. An interface's body is read like a class's, for
+// its methods; fields found there are dropped.
+func scanClasses(src, path, pkg string, lang *LanguagePattern) ([]ClassInfo, []InterfaceInfo) {
+	var classes []ClassInfo
+	var ifaces []InterfaceInfo
+
+	scanner := newLineScanner(src)
+	comments := newCommentFilter(lang)
+	lineNum := 0
+	var currentClass *ClassInfo
+	isInterface := false // currentClass is an interface being read
+
+	// Fields are taken from the current class's body: bodyDepth is the
+	// brace depth inside it, -1 once the body has closed
+	depth, bodyDepth := 0, -1
+	bodyOpen := false
+	var seenFields, seenMethods map[string]bool
+
+	finishClass := func() {
+		switch {
+		case currentClass == nil:
+		case isInterface:
+			ifaces = append(ifaces, InterfaceInfo{
+				Name:    currentClass.Name,
+				Package: currentClass.Package,
+				Methods: currentClass.Methods,
+				File:    currentClass.File,
+				Line:    currentClass.Line,
+			})
+		default:
+			classes = append(classes, *currentClass)
+		}
+	}
+
+	startClass := func(name string, iface bool) {
+		finishClass()
+		isInterface = iface
+		currentClass = &ClassInfo{
+			Name:    name,
+			Package: pkg,
+			File:    path,
+			Line:    lineNum,
+		}
+		bodyDepth, bodyOpen = depth+1, false
+		seenFields, seenMethods = make(map[string]bool), make(map[string]bool)
+	}
+
+	for scanner.Scan() {
+		code, line := comments.strip(scanner.Text())
+		lineNum++
+		lineDepth := depth
+
+		// Find classes
+		if lang.ClassRegex != nil {
+			if matches := lang.ClassRegex.FindStringSubmatch(line); len(matches) > 1 {
+				startClass(matches[1], false)
+			}
+		}
+
+		// Find structs (for languages that have them separately)
+		if lang.StructRegex != nil {
+			if matches := lang.StructRegex.FindStringSubmatch(line); len(matches) > 1 {
+				startClass(matches[1], false)
+			}
+		}
+
+		// Find interfaces
+		if lang.InterfaceRegex != nil {
+			if matches := lang.InterfaceRegex.FindStringSubmatch(line); len(matches) > 1 {
+				startClass(matches[1], true)
+			}
+		}
+
+		// Find fields declared directly in the current class
+		if currentClass != nil && !isInterface && lang.FieldRegex != nil && (lang.NoBraces || lineDepth == bodyDepth) {
+			if field, ok := lang.field(line); ok && !seenFields[field.Name] {
+				seenFields[field.Name] = true
+				currentClass.Fields = append(currentClass.Fields, field)
+			}
+		}
+
+		// Find methods for current class
+		if currentClass != nil && lang.FuncRegex != nil && !lang.FreeFunctions {
+			inBody := !lang.NoBraces && lineDepth == bodyDepth
+			if method, ok := lang.method(code, line, inBody); ok && method.Name != currentClass.Name && !(lang.Clauses && seenMethods[method.Name]) {
+				seenMethods[method.Name] = true
+				method.Line = lineNum
+				currentClass.Methods = append(currentClass.Methods, method)
+			}
+		}
+
+		if !lang.NoBraces {
+			opens := strings.Count(line, "{")
+			depth += opens - strings.Count(line, "}")
+			switch {
+			case bodyDepth < 0:
+			case depth >= bodyDepth:
+				bodyOpen = true
+			case bodyOpen || opens > 0:
+				// Closed, possibly on the line that opened it: "class A {}"
+				bodyDepth, bodyOpen = -1, false
+			}
+		}
+	}
+
+	finishClass()
+	return classes, ifaces
+}
+
+// scanFunctions extracts functions from one source file
+func scanFunctions(src, path, pkg string, lang *LanguagePattern) []FunctionInfo {
+	var funcs []FunctionInfo
+
+	scanner := newLineScanner(src)
+	comments := newCommentFilter(lang)
+	lineNum := 0
+	var doc []string // Comment lines directly above the current line
+	seen := make(map[string]bool)
+
+	for scanner.Scan() {
+		raw := scanner.Text()
+		_, line := comments.strip(raw)
+		lineNum++
+
+		code := strings.TrimSpace(line)
+		if code == "" && strings.TrimSpace(raw) != "" {
+			doc = append(doc, comments.docText(raw))
+			continue
+		}
+
+		funcName := lang.funcName(line)
+		if funcName != "" && lang.Clauses && seen[funcName] {
+			// A further equation of a function already listed
+			doc = nil
+		} else if funcName != "" {
+			seen[funcName] = true
+			_, _, end := lang.funcSpan(lang.FuncRegex, line)
+			funcs = append(funcs, FunctionInfo{
+				Name:       funcName,
+				TypeParams: bracketed(line[end:]),
+				Package:    pkg,
+				File:       path,
+				Line:       lineNum,
+				Doc:        strings.TrimSpace(strings.Join(doc, "\n")),
+			})
+			doc = nil
+		} else if !strings.HasPrefix(code, "@") && !strings.HasPrefix(code, "#[") {
+			// Annotations may sit between a doc comment and its function
+			doc = nil
+		}
+	}
+
+	return funcs
+}
+
+// bracketed returns the square-bracketed list s opens with, such as a Go
+// type parameter list, or "" when s doesn't start with one
+func bracketed(s string) string {
+	if !strings.HasPrefix(s, "[") {
+		return ""
+	}
+	depth := 0
+	for i, r := range s {
+		switch r {
+		case '[':
+			depth++
+		case ']':
+			depth--
+			if depth == 0 {
+				return s[:i+1]
+			}
+		}
+	}
+	return ""
+}
+
+// scanImports extracts unique imports from one source file
+func scanImports(src, rel, pkg string, lang *LanguagePattern) []Dependency {
+	var deps []Dependency
+
+	scanner := newLineScanner(src)
+	comments := newCommentFilter(lang)
+	seen := make(map[string]bool)
+
+	for scanner.Scan() {
+		// Import paths are string literals, so only comments are removed
+		line, _ := comments.strip(scanner.Text())
+
+		if matches := lang.ImportRegex.FindStringSubmatch(line); len(matches) > 1 {
+			importPath := matches[1]
+			if importPath == "" && len(matches) > 2 {
+				importPath = matches[2]
+			}
+			importPath = strings.TrimSpace(importPath)
+
+			if importPath != "" && !seen[importPath] {
+				seen[importPath] = true
+				deps = append(deps, Dependency{
+					From:    rel,
+					To:      importPath,
+					Package: pkg,
+				})
+			}
+		}
+	}
+
+	return deps
+}
@@ -0,0 +1,229 @@
+package parser
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// LayerRulesFile is the optional per-project file defining architecture layers
+const LayerRulesFile = ".arcsii-layers"
+
+// LayerRule maps package name patterns to an architecture layer.
+// Rules are ordered from the top layer down; a layer may depend on the
+// layers below it but not on the ones above.
+type LayerRule struct {
+	Name     string
+	Patterns []string // Glob patterns matched against each package path segment
+}
+
+// Layer is a tier of packages in the layered view
+type Layer struct {
+	Name     string
+	Packages []string
+}
+
+// LayerEdge is an aggregated dependency between two layers
+type LayerEdge struct {
+	From       string
+	To         string
+	Count      int
+	Upward     bool     // Points against the intended direction
+	Violations []string // "pkg → pkg" pairs for upward edges
+}
+
+// LayerView is the result of classifying packages into layers
+type LayerView struct {
+	Layers     []Layer
+	Edges      []LayerEdge
+	Unassigned []string
+}
+
+// DefaultLayerRules returns the conventional handlers → services →
+// repositories → models layering
+func DefaultLayerRules() []LayerRule {
+	return []LayerRule{
+		{Name: "handlers", Patterns: []string{"handler*", "controller*", "api", "http", "web", "routes", "views", "cmd", "ui"}},
+		{Name: "services", Patterns: []string{"service*", "usecase*", "app", "application", "core"}},
+		{Name: "repositories", Patterns: []string{"repo", "repos", "repositor*", "store*", "storage", "db", "dao", "persistence"}},
+		{Name: "models", Patterns: []string{"model*", "entity", "entities", "domain", "types", "schema*"}},
+	}
+}
+
+// LoadLayerRules reads layer rules from root/.arcsii-layers, falling back
+// to DefaultLayerRules. Each line has the form "layer: pattern, pattern".
+func LoadLayerRules(root string) []LayerRule {
+	data, err := os.ReadFile(filepath.Join(root, LayerRulesFile))
+	if err != nil {
+		return DefaultLayerRules()
+	}
+
+	var rules []LayerRule
+	for _, line := range strings.Split(string(stripBOM(data)), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		name, patterns, found := strings.Cut(line, ":")
+		if !found {
+			continue
+		}
+
+		rule := LayerRule{Name: strings.TrimSpace(name)}
+		for _, p := range strings.Split(patterns, ",") {
+			if p = strings.ToLower(strings.TrimSpace(p)); p != "" {
+				rule.Patterns = append(rule.Patterns, p)
+			}
+		}
+		if rule.Name != "" && len(rule.Patterns) > 0 {
+			rules = append(rules, rule)
+		}
+	}
+
+	if len(rules) == 0 {
+		return DefaultLayerRules()
+	}
+	return rules
+}
+
+// ClassifyLayers assigns packages to layers and aggregates the
+// dependencies between them, flagging upward dependencies
+func ClassifyLayers(deps []Dependency, rules []LayerRule) LayerView {
+	view := LayerView{}
+
+	// Packages are identified by the directory of the importing file
+	dirs := make(map[string]bool)
+	for _, dep := range deps {
+		dirs[filepath.ToSlash(filepath.Dir(dep.From))] = true
+	}
+
+	layerOf := make(map[string]int)
+	view.Layers = make([]Layer, len(rules))
+	for i, rule := range rules {
+		view.Layers[i].Name = rule.Name
+	}
+
+	sortedDirs := make([]string, 0, len(dirs))
+	for dir := range dirs {
+		sortedDirs = append(sortedDirs, dir)
+	}
+	sort.Strings(sortedDirs)
+
+	for _, dir := range sortedDirs {
+		if idx := matchLayer(dir, rules); idx >= 0 {
+			layerOf[dir] = idx
+			view.Layers[idx].Packages = append(view.Layers[idx].Packages, dir)
+		} else {
+			view.Unassigned = append(view.Unassigned, dir)
+		}
+	}
+
+	edges := make(map[[2]int]*LayerEdge)
+	seen := make(map[string]bool)
+	for _, dep := range deps {
+		fromDir := filepath.ToSlash(filepath.Dir(dep.From))
+		toDir := resolveLocalPackage(dep.To, fromDir, dirs, sortedDirs)
+		if toDir == "" || toDir == fromDir {
+			continue
+		}
+
+		from, okFrom := layerOf[fromDir]
+		to, okTo := layerOf[toDir]
+		if !okFrom || !okTo || from == to {
+			continue
+		}
+
+		key := [2]int{from, to}
+		edge, exists := edges[key]
+		if !exists {
+			edge = &LayerEdge{
+				From:   rules[from].Name,
+				To:     rules[to].Name,
+				Upward: to < from,
+			}
+			edges[key] = edge
+		}
+		edge.Count++
+
+		pair := fromDir + " → " + toDir
+		if edge.Upward && !seen[pair] {
+			seen[pair] = true
+			edge.Violations = append(edge.Violations, pair)
+		}
+	}
+
+	keys := make([][2]int, 0, len(edges))
+	for key := range edges {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i][0] != keys[j][0] {
+			return keys[i][0] < keys[j][0]
+		}
+		return keys[i][1] < keys[j][1]
+	})
+	for _, key := range keys {
+		view.Edges = append(view.Edges, *edges[key])
+	}
+
+	return view
+}
+
+// matchLayer returns the index of the first rule matching any path
+// segment of dir, or -1
+func matchLayer(dir string, rules []LayerRule) int {
+	segments := strings.Split(strings.ToLower(dir), "/")
+	for i, rule := range rules {
+		for _, pattern := range rule.Patterns {
+			for _, segment := range segments {
+				if ok, _ := path.Match(pattern, segment); ok {
+					return i
+				}
+			}
+		}
+	}
+	return -1
+}
+
+// resolveLocalPackage maps an import to a project directory, or "" for
+// external imports. It handles relative JS/TS paths, Go import paths that
+// end in a project directory, and dotted Python/Java module names.
+func resolveLocalPackage(imp, fromDir string, dirs map[string]bool, sortedDirs []string) string {
+	imp = strings.Trim(imp, `"'`)
+
+	if strings.HasPrefix(imp, ".") && strings.Contains(imp, "/") {
+		joined := path.Clean(path.Join(fromDir, imp))
+		if dirs[joined] {
+			return joined
+		}
+		if dirs[path.Dir(joined)] {
+			return path.Dir(joined)
+		}
+		return ""
+	}
+
+	candidates := []string{imp}
+	if !strings.Contains(imp, "/") {
+		// Dotted module names may end in a class or symbol name
+		dotted := strings.ReplaceAll(imp, ".", "/")
+		candidates = []string{dotted, path.Dir(dotted)}
+	}
+
+	for _, c := range candidates {
+		if c == "." || c == "" {
+			continue
+		}
+		for _, dir := range sortedDirs {
+			if dir == "." {
+				continue
+			}
+			if dir == c || strings.HasSuffix(c, "/"+dir) || strings.HasSuffix(dir, "/"+c) {
+				return dir
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,207 @@
+package parser
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"runtime"
+	"sort"
+)
+
+// StructLayout represents the memory layout of a Go struct
+type StructLayout struct {
+	Name        string
+	Package     string
+	File        string
+	Size        int64
+	Align       int64
+	Padding     int64 // Total bytes lost to padding
+	OptimalSize int64 // Size with fields ordered by descending alignment
+	Fields      []FieldLayout
+	Suggested   []string // Field order that achieves OptimalSize
+
+	// Fields, as "name type", whose types didn't resolve, such as ones
+	// from a dependency that isn't available. The struct's size is then
+	// unknown and only Name, Package and File are set.
+	Unresolved []string
+}
+
+// FieldLayout represents a single field within a struct layout
+type FieldLayout struct {
+	Name    string
+	Type    string
+	Offset  int64
+	Size    int64
+	Align   int64
+	Padding int64 // Bytes of padding inserted after this field
+}
+
+// DefaultArch returns the architecture used when none is specified
+func DefaultArch() string {
+	return runtime.GOARCH
+}
+
+// ParseStructLayouts type-checks Go packages and computes struct sizes,
+// field offsets and padding for the given architecture
+func ParseStructLayouts(root string, arch string) ([]StructLayout, error) {
+	if arch == "" {
+		arch = DefaultArch()
+	}
+	sizes := types.SizesFor("gc", arch)
+	if sizes == nil {
+		return nil, fmt.Errorf("%w %q", ErrUnknownArch, arch)
+	}
+
+	fset, packages := loadGoPackages(root, sizes)
+
+	var layouts []StructLayout
+	for _, cp := range packages {
+		pkg := cp.Pkg
+		for _, file := range cp.Files {
+			for _, decl := range file.Decls {
+				genDecl, ok := decl.(*ast.GenDecl)
+				if !ok || genDecl.Tok != token.TYPE {
+					continue
+				}
+
+				for _, spec := range genDecl.Specs {
+					typeSpec, ok := spec.(*ast.TypeSpec)
+					if !ok || typeSpec.TypeParams != nil {
+						continue
+					}
+					structType, ok := typeSpec.Type.(*ast.StructType)
+					if !ok {
+						continue
+					}
+
+					obj := pkg.Scope().Lookup(typeSpec.Name.Name)
+					if obj == nil {
+						continue
+					}
+					st, ok := obj.Type().Underlying().(*types.Struct)
+					if !ok {
+						continue
+					}
+
+					var layout StructLayout
+					if unresolved := unresolvedFields(st, structType); len(unresolved) > 0 {
+						layout.Unresolved = unresolved
+					} else {
+						layout = computeLayout(st, sizes)
+					}
+					layout.Name = typeSpec.Name.Name
+					layout.Package = pkg.Name()
+					layout.File = fset.Position(typeSpec.Pos()).Filename
+					layouts = append(layouts, layout)
+				}
+			}
+		}
+	}
+
+	sort.Slice(layouts, func(i, j int) bool {
+		if layouts[i].Package != layouts[j].Package {
+			return layouts[i].Package < layouts[j].Package
+		}
+		return layouts[i].Name < layouts[j].Name
+	})
+
+	return layouts, nil
+}
+
+// computeLayout fills in offsets, padding and the reordering suggestion
+func computeLayout(st *types.Struct, sizes types.Sizes) StructLayout {
+	var fields []*types.Var
+	for i := 0; i < st.NumFields(); i++ {
+		fields = append(fields, st.Field(i))
+	}
+
+	layout := StructLayout{
+		Size:  sizes.Sizeof(st),
+		Align: sizes.Alignof(st),
+	}
+
+	offsets := sizes.Offsetsof(fields)
+	for i, field := range fields {
+		size := sizes.Sizeof(field.Type())
+		end := layout.Size
+		if i+1 < len(fields) {
+			end = offsets[i+1]
+		}
+
+		fl := FieldLayout{
+			Name:    field.Name(),
+			Type:    types.TypeString(field.Type(), types.RelativeTo(field.Pkg())),
+			Offset:  offsets[i],
+			Size:    size,
+			Align:   sizes.Alignof(field.Type()),
+			Padding: end - offsets[i] - size,
+		}
+		layout.Padding += fl.Padding
+		layout.Fields = append(layout.Fields, fl)
+	}
+
+	// Ordering by descending alignment (then size) minimizes padding
+	sorted := make([]*types.Var, len(fields))
+	copy(sorted, fields)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		ai, aj := sizes.Alignof(sorted[i].Type()), sizes.Alignof(sorted[j].Type())
+		if ai != aj {
+			return ai > aj
+		}
+		return sizes.Sizeof(sorted[i].Type()) > sizes.Sizeof(sorted[j].Type())
+	})
+
+	layout.OptimalSize = sizes.Sizeof(types.NewStruct(sorted, nil))
+	if layout.OptimalSize < layout.Size {
+		for _, field := range sorted {
+			layout.Suggested = append(layout.Suggested, field.Name())
+		}
+	} else {
+		layout.OptimalSize = layout.Size
+	}
+
+	return layout
+}
+
+// unresolvedFields lists the fields of st whose types didn't resolve
+// during type-checking, with their types as written in the source
+func unresolvedFields(st *types.Struct, decl *ast.StructType) []string {
+	written := fieldTypes(decl.Fields) // One per field, as st has
+	var unresolved []string
+	for i := 0; i < st.NumFields(); i++ {
+		if isValidType(st.Field(i).Type()) {
+			continue
+		}
+		typ := "?"
+		if i < len(written) {
+			typ = written[i]
+		}
+		unresolved = append(unresolved, st.Field(i).Name()+" "+typ)
+	}
+	return unresolved
+}
+
+// isSizable reports whether every field type resolved during type-checking
+func isSizable(st *types.Struct) bool {
+	for i := 0; i < st.NumFields(); i++ {
+		if !isValidType(st.Field(i).Type()) {
+			return false
+		}
+	}
+	return true
+}
+
+func isValidType(t types.Type) bool {
+	switch u := t.Underlying().(type) {
+	case *types.Basic:
+		return u.Kind() != types.Invalid
+	case *types.Array:
+		return isValidType(u.Elem())
+	case *types.Struct:
+		return isSizable(u)
+	case *types.TypeParam:
+		return false
+	}
+	return true
+}
@@ -0,0 +1,50 @@
+package parser
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestStructLayoutsReportUnresolvedTypes(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"go.mod": "module example.com/sz\n\ngo 1.22\n",
+		"sz.go": `package sz
+
+import "example.com/missing/dep"
+
+type Client struct {
+	name string
+	conn dep.Conn
+}
+
+type Plain struct {
+	a bool
+	b int64
+	c bool
+}
+`,
+	})
+
+	layouts, err := ParseStructLayouts(dir, "amd64")
+	if err != nil {
+		t.Fatal(err)
+	}
+	byName := make(map[string]StructLayout)
+	for _, layout := range layouts {
+		byName[layout.Name] = layout
+	}
+
+	client, ok := byName["Client"]
+	if !ok {
+		t.Fatal("Client was dropped")
+	}
+	if !slices.Equal(client.Unresolved, []string{"conn dep.Conn"}) || client.Size != 0 {
+		t.Errorf("Client: unresolved %q, size %d; want conn dep.Conn and no size", client.Unresolved, client.Size)
+	}
+
+	plain := byName["Plain"]
+	if len(plain.Unresolved) > 0 || plain.Size != 24 || plain.OptimalSize != 16 {
+		t.Errorf("Plain: unresolved %q, size %d, optimal %d; want 24 and 16", plain.Unresolved, plain.Size, plain.OptimalSize)
+	}
+}
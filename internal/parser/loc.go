@@ -0,0 +1,99 @@
+package parser
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// LanguageLOC is the line accounting of one language's files
+type LanguageLOC struct {
+	Language string // languagePatterns key, e.g. "go"
+	Files    int
+	Code     int
+	Comments int
+	Blanks   int
+}
+
+// LOCReport splits the project's source lines into code, comments and
+// blanks per language, largest by code first
+type LOCReport struct {
+	Languages []LanguageLOC
+	Total     LanguageLOC // Language is empty
+}
+
+// CountLOC classifies every line of the project's source files with
+// their language's comment syntax: blank lines, lines that are entirely
+// comment, and code, which includes lines with a trailing comment. Block
+// comments are followed across lines, and Python's triple-quoted
+// strings count as comments, as docstrings should. Files in languages
+// without patterns aren't counted.
+func CountLOC(root string) LOCReport {
+	type fileLOC struct {
+		lang string
+		loc  LanguageLOC
+		ok   bool
+	}
+	results := parseFiles(walkFiles(root), func(path string) fileLOC {
+		name := languageName(filepath.Base(path))
+		if name == "" {
+			return fileLOC{}
+		}
+		src, ok := readSource(path)
+		if !ok {
+			return fileLOC{}
+		}
+		return fileLOC{lang: name, loc: countLines(src, languagePatterns[name]), ok: true}
+	})
+
+	byLang := make(map[string]*LanguageLOC)
+	var report LOCReport
+	for _, result := range results {
+		if !result.ok {
+			continue
+		}
+		total := byLang[result.lang]
+		if total == nil {
+			total = &LanguageLOC{Language: result.lang}
+			byLang[result.lang] = total
+		}
+		for _, t := range []*LanguageLOC{total, &report.Total} {
+			t.Files++
+			t.Code += result.loc.Code
+			t.Comments += result.loc.Comments
+			t.Blanks += result.loc.Blanks
+		}
+	}
+
+	for _, loc := range byLang {
+		report.Languages = append(report.Languages, *loc)
+	}
+	sort.Slice(report.Languages, func(i, j int) bool {
+		a, b := report.Languages[i], report.Languages[j]
+		if a.Code != b.Code {
+			return a.Code > b.Code
+		}
+		return a.Language < b.Language
+	})
+	return report
+}
+
+// countLines classifies the lines of one source file
+func countLines(src string, lang *LanguagePattern) LanguageLOC {
+	var loc LanguageLOC
+	scanner := newLineScanner(src)
+	comments := newCommentFilter(lang)
+	for scanner.Scan() {
+		line := scanner.Text()
+		code, _ := comments.strip(line)
+		switch {
+		case strings.TrimSpace(line) == "":
+			loc.Blanks++
+		case strings.TrimSpace(code) == "":
+			loc.Comments++
+		default:
+			loc.Code++
+		}
+	}
+	return loc
+}
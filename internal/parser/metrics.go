@@ -0,0 +1,122 @@
+package parser
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// PackageMetric aggregates the non-test Go files of one package directory
+type PackageMetric struct {
+	Dir       string // Relative to the project root, "." for the root itself
+	Name      string // From the package clause
+	Files     int
+	Funcs     int
+	Structs   int
+	Lines     int
+	FuncLines int // Lines spanned by functions, for AvgFuncLines
+}
+
+// AvgFuncLines returns the average length of the package's functions in
+// lines, or 0 if it has none
+func (m PackageMetric) AvgFuncLines() float64 {
+	if m.Funcs == 0 {
+		return 0
+	}
+	return float64(m.FuncLines) / float64(m.Funcs)
+}
+
+// PackageMetrics sums files, functions, structs and lines for each Go
+// package directory under root, largest by line count first. Files that
+// don't parse still count toward files and lines.
+func PackageMetrics(root string) []PackageMetric {
+	var paths []string
+	for _, path := range walkFiles(root) {
+		if strings.HasSuffix(path, ".go") && !strings.HasSuffix(path, "_test.go") {
+			paths = append(paths, path)
+		}
+	}
+
+	fset := token.NewFileSet()
+	results := parseFiles(paths, func(path string) PackageMetric {
+		return fileMetric(fset, path)
+	})
+
+	byDir := make(map[string]*PackageMetric)
+	var metrics []*PackageMetric
+	for i, path := range paths {
+		dir, err := filepath.Rel(root, filepath.Dir(path))
+		if err != nil {
+			dir = filepath.Dir(path)
+		}
+		dir = filepath.ToSlash(dir)
+
+		m := byDir[dir]
+		if m == nil {
+			m = &PackageMetric{Dir: dir}
+			byDir[dir] = m
+			metrics = append(metrics, m)
+		}
+		fm := results[i]
+		if m.Name == "" {
+			m.Name = fm.Name
+		}
+		m.Files++
+		m.Funcs += fm.Funcs
+		m.Structs += fm.Structs
+		m.Lines += fm.Lines
+		m.FuncLines += fm.FuncLines
+	}
+
+	sort.SliceStable(metrics, func(i, j int) bool {
+		return metrics[i].Lines > metrics[j].Lines
+	})
+
+	result := make([]PackageMetric, len(metrics))
+	for i, m := range metrics {
+		result[i] = *m
+	}
+	return result
+}
+
+// fileMetric measures one Go file
+func fileMetric(fset *token.FileSet, path string) PackageMetric {
+	var m PackageMetric
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return m
+	}
+	data = stripBOM(data)
+	m.Lines = len(strings.Split(string(data), "\n"))
+
+	node, err := parser.ParseFile(fset, path, data, 0)
+	if err != nil {
+		return m
+	}
+
+	m.Name = node.Name.Name
+	for _, decl := range node.Decls {
+		switch d := decl.(type) {
+		case *ast.FuncDecl:
+			m.Funcs++
+			m.FuncLines += fset.Position(d.End()).Line - fset.Position(d.Pos()).Line + 1
+		case *ast.GenDecl:
+			if d.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range d.Specs {
+				if ts, ok := spec.(*ast.TypeSpec); ok {
+					if _, ok := ts.Type.(*ast.StructType); ok {
+						m.Structs++
+					}
+				}
+			}
+		}
+	}
+	return m
+}
@@ -0,0 +1,178 @@
+package parser
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// orphanFile is what FindOrphans knows about one Go file
+type orphanFile struct {
+	rel      string          // Slash-separated, relative to the root
+	pkg      string          // From the package clause, "" if it didn't parse
+	declared []string        // Top-level functions, methods, types, vars and consts
+	uses     map[string]bool // Every identifier the file mentions
+	entry    bool            // Declares main or init, so runs without being referenced
+}
+
+// FindOrphans lists Go files, relative to root, that nothing else in the
+// project seems to use: files of an internal package no other package
+// imports, and files none of whose declarations are mentioned by another
+// file of the package or by a file importing it. Exported names of other
+// library packages may be used outside the project, so they always count
+// as used.
+//
+// It matches names, not types, so a method called only through
+// reflection or a same-named method elsewhere can hide or invent an
+// orphan. Entry points (main and init) and files that declare nothing
+// are never reported; test files neither count as uses nor are reported.
+func FindOrphans(root string) []string {
+	var modPath string
+	if mod, err := ParseGoMod(root); err == nil {
+		modPath = mod.Path
+	}
+
+	// The import graph: which files import each package
+	importers := make(map[string][]string)
+	for _, dep := range ParseDependencies(root) {
+		from := filepath.ToSlash(dep.From)
+		if strings.HasSuffix(from, "_test.go") {
+			continue
+		}
+		importers[dep.To] = append(importers[dep.To], from)
+	}
+
+	fset := token.NewFileSet()
+	files := parseFiles(goSourceFiles(root), func(p string) orphanFile {
+		return scanOrphanFile(fset, root, p)
+	})
+
+	byRel := make(map[string]*orphanFile, len(files))
+	byDir := make(map[string][]*orphanFile)
+	for i := range files {
+		f := &files[i]
+		byRel[f.rel] = f
+		byDir[path.Dir(f.rel)] = append(byDir[path.Dir(f.rel)], f)
+	}
+
+	var orphans []string
+	for i := range files {
+		f := &files[i]
+		if f.pkg == "" || f.entry || len(f.declared) == 0 {
+			continue
+		}
+		dir := path.Dir(f.rel)
+
+		// Other files of the same package may use any declaration
+		var users []*orphanFile
+		for _, other := range byDir[dir] {
+			if other != f && other.pkg == f.pkg {
+				users = append(users, other)
+			}
+		}
+
+		// Importers only see exported names. Without a module path the
+		// package's import path is unknown, so only its own files count.
+		if modPath != "" && f.pkg != "main" {
+			importPath := modPath
+			if dir != "." {
+				importPath += "/" + dir
+			}
+			imported := false
+			for _, from := range importers[importPath] {
+				if other := byRel[from]; other != nil && path.Dir(from) != dir {
+					users = append(users, other)
+					imported = true
+				}
+			}
+
+			// Nothing outside the module can import an internal package
+			if !imported && isInternal(dir) {
+				orphans = append(orphans, f.rel)
+				continue
+			}
+		}
+
+		// Exported names of a library package may be public API
+		public := f.pkg != "main" && !isInternal(dir)
+
+		used := false
+		for _, name := range f.declared {
+			if public && ast.IsExported(name) {
+				used = true
+				break
+			}
+			for _, other := range users {
+				if other.uses[name] && (path.Dir(other.rel) == dir || ast.IsExported(name)) {
+					used = true
+					break
+				}
+			}
+			if used {
+				break
+			}
+		}
+		if !used {
+			orphans = append(orphans, f.rel)
+		}
+	}
+
+	sort.Strings(orphans)
+	return orphans
+}
+
+// isInternal reports whether a slash-separated directory is, or is below,
+// an internal directory
+func isInternal(dir string) bool {
+	return strings.Contains("/"+dir+"/", "/internal/")
+}
+
+// scanOrphanFile collects a file's declarations and the identifiers it uses
+func scanOrphanFile(fset *token.FileSet, root, p string) orphanFile {
+	rel, err := filepath.Rel(root, p)
+	if err != nil {
+		rel = p
+	}
+	f := orphanFile{rel: filepath.ToSlash(rel), uses: make(map[string]bool)}
+
+	node, err := parser.ParseFile(fset, p, nil, parser.SkipObjectResolution)
+	if err != nil {
+		return f
+	}
+	f.pkg = node.Name.Name
+
+	for _, decl := range node.Decls {
+		switch d := decl.(type) {
+		case *ast.FuncDecl:
+			if d.Recv == nil && (d.Name.Name == "main" || d.Name.Name == "init") {
+				f.entry = true
+			}
+			f.declared = append(f.declared, d.Name.Name)
+		case *ast.GenDecl:
+			for _, spec := range d.Specs {
+				switch s := spec.(type) {
+				case *ast.TypeSpec:
+					f.declared = append(f.declared, s.Name.Name)
+				case *ast.ValueSpec:
+					for _, name := range s.Names {
+						if name.Name != "_" {
+							f.declared = append(f.declared, name.Name)
+						}
+					}
+				}
+			}
+		}
+	}
+
+	ast.Inspect(node, func(n ast.Node) bool {
+		if ident, ok := n.(*ast.Ident); ok {
+			f.uses[ident.Name] = true
+		}
+		return true
+	})
+	return f
+}
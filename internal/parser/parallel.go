@@ -0,0 +1,52 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+)
+
+// walkFiles lists every file under root that .gitignore doesn't exclude,
+// in the order filepath.Walk visits them. Callers filter by name; merging
+// results in this order keeps output stable from run to run.
+func walkFiles(root string) []string {
+	var paths []string
+
+	ignore := LoadIgnore(root)
+	filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if skip, result := ignore.Skip(path, info); skip {
+			return result
+		}
+		if err != nil || info.IsDir() {
+			return nil
+		}
+		paths = append(paths, path)
+		return nil
+	})
+
+	return paths
+}
+
+// parseFiles calls parse for every path on up to runtime.NumCPU()
+// goroutines. Results are returned in the order of paths.
+func parseFiles[T any](paths []string, parse func(path string) T) []T {
+	results := make([]T, len(paths))
+	jobs := make(chan int)
+
+	var wg sync.WaitGroup
+	for range min(runtime.NumCPU(), len(paths)) {
+		wg.Go(func() {
+			for i := range jobs {
+				results[i] = parse(paths[i])
+			}
+		})
+	}
+	for i := range paths {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	return results
+}
@@ -7,6 +7,7 @@ import (
 	"os"
 	"path/filepath"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -19,22 +20,27 @@ type FileNode struct {
 	Children []*FileNode
 	Size     int64
 	ModTime  time.Time
+
+	Languages []string // Directory language tags, set by AnnotateLanguages
 }
 
 // ClassInfo represents a struct/class
 type ClassInfo struct {
 	Name       string
+	TypeParams string // Go type parameter list, e.g. "[K comparable, V any]"; "" if not generic
 	Package    string
 	Fields     []FieldInfo
 	Methods    []MethodInfo
 	Implements []string
 	File       string
+	Line       int
 }
 
 // FieldInfo represents a struct field
 type FieldInfo struct {
 	Name string
 	Type string
+	Tag  string // Struct tag without the quotes, e.g. json:"id"; "" if absent
 }
 
 // MethodInfo represents a method
@@ -43,16 +49,20 @@ type MethodInfo struct {
 	Receiver   string
 	Parameters []string
 	Returns    []string
+	Line       int
 }
 
 // FunctionInfo represents a function
 type FunctionInfo struct {
 	Name       string
+	TypeParams string // Go type parameter list, e.g. "[T any]"; "" if not generic
 	Package    string
 	File       string
 	Parameters []string
 	Returns    []string
 	Line       int
+	EndLine    int    // Last line of the body, when known
+	Doc        string // Doc comment text, without comment markers
 }
 
 // Dependency represents an import dependency
@@ -69,8 +79,13 @@ type ProjectStats struct {
 	TotalPackages int
 	TotalFuncs    int
 	TotalStructs  int
-	Languages     map[string]int
+	Languages     map[string]int // Files per extension
+	LanguageLines map[string]int // Lines per recognized source language
+	TodoCount     int            // TODO markers in source files
 	LargestFiles  []FileInfo
+	LargestFuncs  []FunctionSize // Go functions and methods, longest first
+	FallbackFiles int            // Go files analyzed with regex because go/parser failed
+	ParseErrors   []*ParseError
 }
 
 // FileInfo for stats
@@ -80,6 +95,14 @@ type FileInfo struct {
 	Size  int64
 }
 
+// FunctionSize is the line span of a Go function or method
+type FunctionSize struct {
+	Name  string // "Func", or "Type.Method" for methods
+	File  string
+	Line  int
+	Lines int
+}
+
 // RecentChange represents a recently modified file
 type RecentChange struct {
 	Path    string
@@ -101,6 +124,7 @@ type ModuleInfo struct {
 	Files   []string
 	Structs []string
 	Funcs   []string
+	Lines   int // Across Files; unreadable files count 0
 }
 
 // ParseFileTree builds a file tree structure
@@ -122,7 +146,15 @@ func ParseFileTree(root string) *FileNode {
 		IsDir: true,
 	}
 
+	// Directory nodes by path. Each node describes its own path, so a
+	// file and a directory never share a FileInfo.
+	nodes := treeNodes{absRoot: rootNode}
+
+	ignore := LoadIgnore(absRoot)
 	filepath.Walk(absRoot, func(path string, info os.FileInfo, err error) error {
+		if skip, result := ignore.Skip(path, info); skip {
+			return result
+		}
 		if err != nil {
 			return nil
 		}
@@ -140,31 +172,20 @@ func ParseFileTree(root string) *FileNode {
 			return nil
 		}
 
-		rel, _ := filepath.Rel(absRoot, path)
-		parts := strings.Split(rel, string(os.PathSeparator))
-
-		current := rootNode
-		for i, part := range parts {
-			found := false
-			for _, child := range current.Children {
-				if child.Name == part {
-					current = child
-					found = true
-					break
-				}
-			}
-			if !found {
-				isLast := i == len(parts)-1
-				newNode := &FileNode{
-					Name:    part,
-					Path:    filepath.Join(absRoot, strings.Join(parts[:i+1], string(os.PathSeparator))),
-					IsDir:   info.IsDir() && isLast || !isLast,
-					Size:    info.Size(),
-					ModTime: info.ModTime(),
-				}
-				current.Children = append(current.Children, newNode)
-				current = newNode
-			}
+		parent := nodes.dir(filepath.Dir(path))
+		if parent == nil {
+			return nil
+		}
+		node := &FileNode{
+			Name:    name,
+			Path:    path,
+			IsDir:   info.IsDir(),
+			Size:    info.Size(),
+			ModTime: info.ModTime(),
+		}
+		parent.Children = append(parent.Children, node)
+		if node.IsDir {
+			nodes[path] = node
 		}
 		return nil
 	})
@@ -172,68 +193,87 @@ func ParseFileTree(root string) *FileNode {
 	return rootNode
 }
 
+// treeNodes indexes the directory nodes of a tree being built by path
+type treeNodes map[string]*FileNode
+
+// dir returns the node for directory path. Walk visits a directory
+// before its contents, so it is normally known already; otherwise it and
+// any missing parents are added from their own stat. It returns nil for
+// paths outside the tree or that are not directories.
+func (nodes treeNodes) dir(path string) *FileNode {
+	if node, ok := nodes[path]; ok {
+		return node
+	}
+	parentPath := filepath.Dir(path)
+	if parentPath == path {
+		return nil
+	}
+	parent := nodes.dir(parentPath)
+	if parent == nil {
+		return nil
+	}
+	info, err := os.Stat(path)
+	if err != nil || !info.IsDir() {
+		return nil
+	}
+	node := &FileNode{
+		Name:    info.Name(),
+		Path:    path,
+		IsDir:   true,
+		ModTime: info.ModTime(),
+		Size:    info.Size(),
+	}
+	parent.Children = append(parent.Children, node)
+	nodes[path] = node
+	return node
+}
+
 // ParseClasses extracts struct/class information from Go files
 func ParseClasses(root string) []ClassInfo {
-	var classes []ClassInfo
 	fset := token.NewFileSet()
 
-	filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
-		if err != nil || info.IsDir() || !strings.HasSuffix(path, ".go") {
-			return nil
-		}
-		if strings.Contains(path, "_test.go") {
-			return nil
-		}
-
+	// Files are parsed concurrently; interfaces and methods are matched
+	// up afterwards, once every file's types are known
+	type fileClasses struct {
+		node    *ast.File
+		path    string
+		classes []ClassInfo
+	}
+	results := parseFiles(goSourceFiles(root), func(path string) fileClasses {
 		node, err := parser.ParseFile(fset, path, nil, parser.ParseComments)
 		if err != nil {
-			return nil
+			if src, pkg, ok := goFallbackSource(path); ok {
+				classes, _ := scanClasses(src, path, pkg, languagePatterns["go"])
+				return fileClasses{path: path, classes: classes}
+			}
+			return fileClasses{path: path}
 		}
+		return fileClasses{node: node, path: path, classes: goStructs(fset, node, path)}
+	})
+
+	var classes []ClassInfo
+	for _, result := range results {
+		classes = append(classes, result.classes...)
+	}
+
+	implements := newImplementsIndex()
+	for _, result := range results {
+		if result.node == nil {
+			continue
+		}
+		node, path := result.node, result.path
 
 		for _, decl := range node.Decls {
 			genDecl, ok := decl.(*ast.GenDecl)
 			if !ok || genDecl.Tok != token.TYPE {
 				continue
 			}
-
 			for _, spec := range genDecl.Specs {
-				typeSpec, ok := spec.(*ast.TypeSpec)
-				if !ok {
-					continue
-				}
-
-				structType, ok := typeSpec.Type.(*ast.StructType)
-				if !ok {
-					continue
-				}
-
-				class := ClassInfo{
-					Name:    typeSpec.Name.Name,
-					Package: node.Name.Name,
-					File:    path,
-				}
-
-				if structType.Fields != nil {
-					for _, field := range structType.Fields.List {
-						fieldType := exprToString(field.Type)
-						if len(field.Names) > 0 {
-							for _, name := range field.Names {
-								class.Fields = append(class.Fields, FieldInfo{
-									Name: name.Name,
-									Type: fieldType,
-								})
-							}
-						} else {
-							// Embedded field
-							class.Fields = append(class.Fields, FieldInfo{
-								Name: fieldType,
-								Type: "(embedded)",
-							})
-						}
+				if typeSpec, ok := spec.(*ast.TypeSpec); ok {
+					if iface, ok := typeSpec.Type.(*ast.InterfaceType); ok {
+						implements.addInterface(typeSpec.Name.Name, node.Name.Name, path, iface)
 					}
 				}
-
-				classes = append(classes, class)
 			}
 		}
 
@@ -247,12 +287,15 @@ func ParseClasses(root string) []ClassInfo {
 			receiverType := ""
 			if len(funcDecl.Recv.List) > 0 {
 				receiverType = exprToString(funcDecl.Recv.List[0].Type)
-				receiverType = strings.TrimPrefix(receiverType, "*")
+				receiverType = baseTypeName(strings.TrimPrefix(receiverType, "*"))
 			}
 
+			implements.addMethod(receiverType, path, funcDecl)
+
 			method := MethodInfo{
 				Name:     funcDecl.Name.Name,
 				Receiver: receiverType,
+				Line:     fset.Position(funcDecl.Pos()).Line,
 			}
 
 			if funcDecl.Type.Params != nil {
@@ -275,31 +318,96 @@ func ParseClasses(root string) []ClassInfo {
 				}
 			}
 		}
+	}
+
+	implements.apply(classes)
+	return classes
+}
+
+// goStructs lists the structs declared in a parsed Go file, with fields
+func goStructs(fset *token.FileSet, node *ast.File, path string) []ClassInfo {
+	var classes []ClassInfo
+
+	for _, decl := range node.Decls {
+		genDecl, ok := decl.(*ast.GenDecl)
+		if !ok || genDecl.Tok != token.TYPE {
+			continue
+		}
 
-		return nil
-	})
+		for _, spec := range genDecl.Specs {
+			typeSpec, ok := spec.(*ast.TypeSpec)
+			if !ok {
+				continue
+			}
+
+			structType, ok := typeSpec.Type.(*ast.StructType)
+			if !ok {
+				continue
+			}
+
+			class := ClassInfo{
+				Name:       typeSpec.Name.Name,
+				TypeParams: typeParams(typeSpec.TypeParams),
+				Package:    node.Name.Name,
+				File:       path,
+				Line:       fset.Position(typeSpec.Pos()).Line,
+			}
+
+			if structType.Fields != nil {
+				for _, field := range structType.Fields.List {
+					fieldType := exprToString(field.Type)
+					tag := fieldTag(field)
+					if len(field.Names) > 0 {
+						for _, name := range field.Names {
+							class.Fields = append(class.Fields, FieldInfo{
+								Name: name.Name,
+								Type: fieldType,
+								Tag:  tag,
+							})
+						}
+					} else {
+						// Embedded field
+						class.Fields = append(class.Fields, FieldInfo{
+							Name: fieldType,
+							Type: "(embedded)",
+							Tag:  tag,
+						})
+					}
+				}
+			}
+
+			classes = append(classes, class)
+		}
+	}
 
 	return classes
 }
 
+// goSourceFiles lists the non-test Go files under root
+func goSourceFiles(root string) []string {
+	var paths []string
+	for _, path := range walkFiles(root) {
+		if strings.HasSuffix(path, ".go") && !strings.Contains(path, "_test.go") {
+			paths = append(paths, path)
+		}
+	}
+	return paths
+}
+
 // ParseFunctions extracts all functions
 func ParseFunctions(root string) []FunctionInfo {
-	var funcs []FunctionInfo
 	fset := token.NewFileSet()
 
-	filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
-		if err != nil || info.IsDir() || !strings.HasSuffix(path, ".go") {
-			return nil
-		}
-		if strings.Contains(path, "_test.go") {
-			return nil
-		}
-
+	results := parseFiles(goSourceFiles(root), func(path string) []FunctionInfo {
 		node, err := parser.ParseFile(fset, path, nil, parser.ParseComments)
 		if err != nil {
+			if src, pkg, ok := goFallbackSource(path); ok {
+				return scanFunctions(src, path, pkg, languagePatterns["go"])
+			}
 			return nil
 		}
 
+		var funcs []FunctionInfo
 		for _, decl := range node.Decls {
 			funcDecl, ok := decl.(*ast.FuncDecl)
 			if !ok {
@@ -307,10 +415,12 @@ func ParseFunctions(root string) []FunctionInfo {
 			}
 
 			fn := FunctionInfo{
-				Name:    funcDecl.Name.Name,
-				Package: node.Name.Name,
-				File:    path,
-				Line:    fset.Position(funcDecl.Pos()).Line,
+				Name:       funcDecl.Name.Name,
+				TypeParams: typeParams(funcDecl.Type.TypeParams),
+				Package:    node.Name.Name,
+				File:       path,
+				Line:       fset.Position(funcDecl.Pos()).Line,
+				Doc:        strings.TrimSpace(funcDecl.Doc.Text()),
 			}
 
 			if funcDecl.Recv != nil {
@@ -332,10 +442,13 @@ func ParseFunctions(root string) []FunctionInfo {
 
 			funcs = append(funcs, fn)
 		}
-
-		return nil
+		return funcs
 	})
 
+	var funcs []FunctionInfo
+	for _, result := range results {
+		funcs = append(funcs, result...)
+	}
 	return funcs
 }
 
@@ -344,17 +457,25 @@ func ParseDependencies(root string) []Dependency {
 	var deps []Dependency
 	fset := token.NewFileSet()
 
+	ignore := LoadIgnore(root)
 	filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if skip, result := ignore.Skip(path, info); skip {
+			return result
+		}
 		if err != nil || info.IsDir() || !strings.HasSuffix(path, ".go") {
 			return nil
 		}
 
+		rel, _ := filepath.Rel(root, path)
+
 		node, err := parser.ParseFile(fset, path, nil, parser.ImportsOnly)
 		if err != nil {
+			if src, pkg, ok := goFallbackSource(path); ok {
+				deps = append(deps, scanImports(src, rel, pkg, languagePatterns["go"])...)
+			}
 			return nil
 		}
 
-		rel, _ := filepath.Rel(root, path)
 		for _, imp := range node.Imports {
 			importPath := strings.Trim(imp.Path.Value, `"`)
 			deps = append(deps, Dependency{
@@ -370,11 +491,68 @@ func ParseDependencies(root string) []Dependency {
 	return deps
 }
 
-// ParseRecentChanges finds recently modified files
-func ParseRecentChanges(root string) []RecentChange {
+// Dependents is the reverse of a dependency graph: for each imported
+// path, the dependencies importing it
+type Dependents map[string][]Dependency
+
+// IndexDependents inverts deps, ordering each path's importers by file
+func IndexDependents(deps []Dependency) Dependents {
+	index := make(Dependents)
+	for _, dep := range deps {
+		index[dep.To] = append(index[dep.To], dep)
+	}
+	for _, importers := range index {
+		sort.SliceStable(importers, func(i, j int) bool {
+			return importers[i].From < importers[j].From
+		})
+	}
+	return index
+}
+
+// Of returns what imports target. A target no import names exactly
+// resolves to the one path ending in "/"+target, so "internal/parser"
+// finds the module's parser package; the resolved path is returned.
+func (d Dependents) Of(target string) (string, []Dependency) {
+	if importers, ok := d[target]; ok {
+		return target, importers
+	}
+	var match string
+	for path := range d {
+		if strings.HasSuffix(path, "/"+target) {
+			if match != "" {
+				return target, nil // Ambiguous
+			}
+			match = path
+		}
+	}
+	if match == "" {
+		return target, nil
+	}
+	return match, d[match]
+}
+
+// DefaultRecentChanges is the number of files ParseRecentChanges returns
+// when no limit is given
+const DefaultRecentChanges = 20
+
+// ParseRecentChanges finds the most recently modified files, up to limit
+func ParseRecentChanges(root string, limit int) []RecentChange {
+	if limit <= 0 {
+		limit = DefaultRecentChanges
+	}
+
 	var changes []RecentChange
 
+	ignore := LoadIgnore(root)
 	filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if skip, result := ignore.Skip(path, info); skip {
+			return result
+		}
+		// Git's own files change with every commit and would bury the
+		// project's changes
+		if err == nil && info.IsDir() && info.Name() == ".git" {
+			return filepath.SkipDir
+		}
 		if err != nil || info.IsDir() {
 			return nil
 		}
@@ -397,100 +575,252 @@ func ParseRecentChanges(root string) []RecentChange {
 		return changes[i].ModTime.After(changes[j].ModTime)
 	})
 
-	// Return top 20
-	if len(changes) > 20 {
-		changes = changes[:20]
+	if len(changes) > limit {
+		changes = changes[:limit]
 	}
 
 	return changes
 }
 
+// fileStats is what ParseStats learns from one file
+type fileStats struct {
+	language  string // languagePatterns key, or ""
+	langLines int
+	todos     int
+
+	goFile   bool // Non-test Go source, counted in TotalLines
+	lines    int
+	size     int64
+	pkg      string
+	funcs    int
+	structs  int
+	sizes    []FunctionSize
+	parseErr *ParseError
+	fallback bool
+}
+
 // ParseStats gathers project statistics
 func ParseStats(root string) ProjectStats {
 	stats := ProjectStats{
-		Languages: make(map[string]int),
+		Languages:     make(map[string]int),
+		LanguageLines: make(map[string]int),
 	}
 
-	packages := make(map[string]bool)
-	fset := token.NewFileSet()
-
-	filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
-		if err != nil || info.IsDir() {
-			return nil
+	var paths []string
+	for _, path := range walkFiles(root) {
+		if !strings.HasPrefix(filepath.Base(path), ".") {
+			paths = append(paths, path)
 		}
+	}
 
-		name := info.Name()
-		if strings.HasPrefix(name, ".") {
-			return nil
-		}
+	fset := token.NewFileSet()
+	results := parseFiles(paths, func(path string) fileStats {
+		return statFile(fset, path)
+	})
+
+	packages := make(map[string]bool)
+	for i, path := range paths {
+		fs := results[i]
 
-		ext := filepath.Ext(name)
-		if ext != "" {
+		if ext := filepath.Ext(path); ext != "" {
 			stats.Languages[ext]++
 		}
-
 		stats.TotalFiles++
 
-		// Count lines and parse Go files
-		if strings.HasSuffix(path, ".go") && !strings.Contains(path, "_test.go") {
-			data, err := os.ReadFile(path)
-			if err == nil {
-				lines := len(strings.Split(string(data), "\n"))
-				stats.TotalLines += lines
-
-				stats.LargestFiles = append(stats.LargestFiles, FileInfo{
-					Path:  path,
-					Lines: lines,
-					Size:  info.Size(),
-				})
-			}
-
-			node, err := parser.ParseFile(fset, path, nil, 0)
-			if err == nil {
-				packages[node.Name.Name] = true
-
-				for _, decl := range node.Decls {
-					switch d := decl.(type) {
-					case *ast.FuncDecl:
-						stats.TotalFuncs++
-					case *ast.GenDecl:
-						if d.Tok == token.TYPE {
-							for _, spec := range d.Specs {
-								if ts, ok := spec.(*ast.TypeSpec); ok {
-									if _, ok := ts.Type.(*ast.StructType); ok {
-										stats.TotalStructs++
-									}
-								}
-							}
-						}
-					}
-				}
-			}
+		if fs.language != "" {
+			stats.LanguageLines[fs.language] += fs.langLines
+			stats.TodoCount += fs.todos
 		}
 
-		return nil
-	})
+		if !fs.goFile {
+			continue
+		}
+		if fs.lines > 0 {
+			stats.TotalLines += fs.lines
+			stats.LargestFiles = append(stats.LargestFiles, FileInfo{
+				Path:  path,
+				Lines: fs.lines,
+				Size:  fs.size,
+			})
+		}
+		if fs.parseErr != nil {
+			stats.ParseErrors = append(stats.ParseErrors, fs.parseErr)
+		}
+		if fs.fallback {
+			stats.FallbackFiles++
+		}
+		if fs.pkg != "" {
+			packages[fs.pkg] = true
+		}
+		stats.TotalFuncs += fs.funcs
+		stats.TotalStructs += fs.structs
+		stats.LargestFuncs = append(stats.LargestFuncs, fs.sizes...)
+	}
 
 	stats.TotalPackages = len(packages)
 
 	// Sort largest files
-	sort.Slice(stats.LargestFiles, func(i, j int) bool {
+	sort.SliceStable(stats.LargestFiles, func(i, j int) bool {
 		return stats.LargestFiles[i].Lines > stats.LargestFiles[j].Lines
 	})
 	if len(stats.LargestFiles) > 5 {
 		stats.LargestFiles = stats.LargestFiles[:5]
 	}
 
+	sort.SliceStable(stats.LargestFuncs, func(i, j int) bool {
+		return stats.LargestFuncs[i].Lines > stats.LargestFuncs[j].Lines
+	})
+	if len(stats.LargestFuncs) > 10 {
+		stats.LargestFuncs = stats.LargestFuncs[:10]
+	}
+
 	return stats
 }
 
+// statFile gathers one file's contribution to ParseStats
+func statFile(fset *token.FileSet, path string) fileStats {
+	var fs fileStats
+
+	// Lines and TODOs per source language
+	if lang := languageName(filepath.Base(path)); lang != "" {
+		if src, ok := readSource(path); ok {
+			fs.language = lang
+			fs.langLines = lineCount(src)
+			fs.todos = len(todoRegex.FindAllStringIndex(src, -1))
+		}
+	}
+
+	// Count lines and parse Go files
+	if !strings.HasSuffix(path, ".go") || strings.Contains(path, "_test.go") {
+		return fs
+	}
+	fs.goFile = true
+
+	if data, err := os.ReadFile(path); err == nil {
+		fs.lines = len(strings.Split(string(stripBOM(data)), "\n"))
+		fs.size = int64(len(data))
+	}
+
+	node, err := parser.ParseFile(fset, path, nil, 0)
+	if err != nil {
+		fs.parseErr = &ParseError{File: path, Err: err}
+		if src, pkg, ok := goFallbackSource(path); ok {
+			fs.fallback = true
+			fs.pkg = pkg
+			fs.funcs = len(scanFunctions(src, path, pkg, languagePatterns["go"]))
+			fs.structs = len(languagePatterns["go"].ClassRegex.FindAllString(src, -1))
+		}
+		return fs
+	}
+
+	fs.pkg = node.Name.Name
+	for _, decl := range node.Decls {
+		switch d := decl.(type) {
+		case *ast.FuncDecl:
+			fs.funcs++
+
+			name := d.Name.Name
+			if d.Recv != nil && len(d.Recv.List) > 0 {
+				name = strings.TrimPrefix(exprToString(d.Recv.List[0].Type), "*") + "." + name
+			}
+			start, end := fset.Position(d.Pos()).Line, fset.Position(d.End()).Line
+			fs.sizes = append(fs.sizes, FunctionSize{Name: name, File: path, Line: start, Lines: end - start + 1})
+		case *ast.GenDecl:
+			if d.Tok == token.TYPE {
+				for _, spec := range d.Specs {
+					if ts, ok := spec.(*ast.TypeSpec); ok {
+						if _, ok := ts.Type.(*ast.StructType); ok {
+							fs.structs++
+						}
+					}
+				}
+			}
+		}
+	}
+	return fs
+}
+
+// QuickFileStats reads a single file's line count and size. It returns a
+// zero FileInfo for directories and files that can't be read, and counts
+// no lines for binary files.
+func QuickFileStats(path string) FileInfo {
+	info, err := os.Stat(path)
+	if err != nil || info.IsDir() {
+		return FileInfo{}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return FileInfo{}
+	}
+	data = stripBOM(data)
+
+	fi := FileInfo{
+		Path: path,
+		Size: info.Size(),
+	}
+	if !isBinary(data) {
+		fi.Lines = lineCount(string(data))
+	}
+	return fi
+}
+
+// lineCount counts the lines of src as an editor or wc -l would, with a
+// final line that lacks its newline still counted
+func lineCount(src string) int {
+	n := strings.Count(src, "\n")
+	if src != "" && !strings.HasSuffix(src, "\n") {
+		n++
+	}
+	return n
+}
+
+// ParseFileStats collects QuickFileStats for every project file, keyed by
+// path relative to root
+func ParseFileStats(root string) map[string]FileInfo {
+	files := make(map[string]FileInfo)
+
+	ignore := LoadIgnore(root)
+	filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if skip, result := ignore.Skip(path, info); skip {
+			return result
+		}
+		if err != nil {
+			return nil
+		}
+
+		name := info.Name()
+		if path != root && (strings.HasPrefix(name, ".") || name == "node_modules" || name == "vendor" || name == "__pycache__" || name == "dist") {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if info.IsDir() {
+			return nil
+		}
+
+		if fi := QuickFileStats(path); fi.Path != "" {
+			rel, _ := filepath.Rel(root, path)
+			files[rel] = fi
+		}
+		return nil
+	})
+
+	return files
+}
+
 // ParseStructure analyzes overall project structure
 func ParseStructure(root string) Structure {
 	structure := Structure{}
 	packageMap := make(map[string]*ModuleInfo)
 	fset := token.NewFileSet()
 
+	ignore := LoadIgnore(root)
 	filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if skip, result := ignore.Skip(path, info); skip {
+			return result
+		}
 		if err != nil || info.IsDir() || !strings.HasSuffix(path, ".go") {
 			return nil
 		}
@@ -500,12 +830,21 @@ func ParseStructure(root string) Structure {
 			return nil
 		}
 
-		node, err := parser.ParseFile(fset, path, nil, 0)
+		// An unreadable file counts no lines and is left to go/parser
+		data, _ := os.ReadFile(path)
+
+		// Fall back to the regex patterns if go/parser rejects the file
+		var src, pkgName string
+		node, err := parser.ParseFile(fset, path, data, 0)
 		if err != nil {
-			return nil
+			var ok bool
+			if src, pkgName, ok = goFallbackSource(path); !ok {
+				return nil
+			}
+		} else {
+			pkgName = node.Name.Name
 		}
 
-		pkgName := node.Name.Name
 		dir := filepath.Dir(path)
 
 		if _, exists := packageMap[dir]; !exists {
@@ -518,11 +857,25 @@ func ParseStructure(root string) Structure {
 
 		mod := packageMap[dir]
 		mod.Files = append(mod.Files, filepath.Base(path))
+		if len(data) > 0 {
+			mod.Lines += len(strings.Split(string(stripBOM(data)), "\n"))
+		}
 
 		if name == "main.go" {
 			structure.MainFiles = append(structure.MainFiles, path)
 		}
 
+		if node == nil {
+			classes, _ := scanClasses(src, path, pkgName, languagePatterns["go"])
+			for _, class := range classes {
+				mod.Structs = append(mod.Structs, class.Name)
+			}
+			for _, fn := range scanFunctions(src, path, pkgName, languagePatterns["go"]) {
+				mod.Funcs = append(mod.Funcs, fn.Name)
+			}
+			return nil
+		}
+
 		for _, decl := range node.Decls {
 			switch d := decl.(type) {
 			case *ast.FuncDecl:
@@ -552,6 +905,41 @@ func ParseStructure(root string) Structure {
 	return structure
 }
 
+// fieldTag returns a struct field's tag with its quotes removed
+func fieldTag(field *ast.Field) string {
+	if field.Tag == nil {
+		return ""
+	}
+	if tag, err := strconv.Unquote(field.Tag.Value); err == nil {
+		return tag
+	}
+	return strings.Trim(field.Tag.Value, "`")
+}
+
+// typeParams formats a generic declaration's type parameter list, e.g.
+// "[K comparable, V any]", or returns "" for a non-generic one
+func typeParams(list *ast.FieldList) string {
+	if list == nil || len(list.List) == 0 {
+		return ""
+	}
+	groups := make([]string, len(list.List))
+	for i, field := range list.List {
+		names := make([]string, len(field.Names))
+		for j, name := range field.Names {
+			names[j] = name.Name
+		}
+		groups[i] = strings.Join(names, ", ") + " " + exprToString(field.Type)
+	}
+	return "[" + strings.Join(groups, ", ") + "]"
+}
+
+// baseTypeName drops the type parameters or arguments from a type name,
+// so "Stack[T any]" and a receiver's "Stack[T]" both give Stack
+func baseTypeName(name string) string {
+	base, _, _ := strings.Cut(name, "[")
+	return base
+}
+
 func exprToString(expr ast.Expr) string {
 	switch t := expr.(type) {
 	case *ast.Ident:
@@ -570,6 +958,20 @@ func exprToString(expr ast.Expr) string {
 		return "func"
 	case *ast.ChanType:
 		return "chan " + exprToString(t.Value)
+	case *ast.Ellipsis: // Variadic parameters
+		return "..." + exprToString(t.Elt)
+	case *ast.IndexExpr: // Generic instantiation, e.g. an embedded Box[T]
+		return exprToString(t.X) + "[" + exprToString(t.Index) + "]"
+	case *ast.IndexListExpr:
+		args := make([]string, len(t.Indices))
+		for i, index := range t.Indices {
+			args[i] = exprToString(index)
+		}
+		return exprToString(t.X) + "[" + strings.Join(args, ", ") + "]"
+	case *ast.UnaryExpr: // Constraint terms such as ~int
+		return t.Op.String() + exprToString(t.X)
+	case *ast.BinaryExpr: // Constraint unions such as ~int | ~string
+		return exprToString(t.X) + " " + t.Op.String() + " " + exprToString(t.Y)
 	default:
 		return "?"
 	}
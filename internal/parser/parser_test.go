@@ -0,0 +1,237 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeFiles creates files, keyed by slash-separated path, under dir
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestParseFileTree(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"main.go":              "package main",
+		"cmd/tool/tool.go":     "package tool",
+		"cmd/tool/README.md":   "# tool",
+		"internal/a/a.go":      "package a",
+		"internal/a/a_test.go": "package a_test",
+		"internal/b/b.go":      "package b // b",
+	})
+
+	type want struct {
+		dir    bool
+		size   int64  // Files only
+		parent string // Slash-separated, "" for the root
+	}
+	wants := map[string]want{
+		"main.go":              {size: 12},
+		"cmd":                  {dir: true},
+		"cmd/tool":             {dir: true, parent: "cmd"},
+		"cmd/tool/tool.go":     {size: 12, parent: "cmd/tool"},
+		"cmd/tool/README.md":   {size: 6, parent: "cmd/tool"},
+		"internal":             {dir: true},
+		"internal/a":           {dir: true, parent: "internal"},
+		"internal/a/a.go":      {size: 9, parent: "internal/a"},
+		"internal/a/a_test.go": {size: 14, parent: "internal/a"},
+		"internal/b":           {dir: true, parent: "internal"},
+		"internal/b/b.go":      {size: 14, parent: "internal/b"},
+	}
+
+	root := ParseFileTree(dir)
+	if !root.IsDir {
+		t.Fatal("root isn't a directory")
+	}
+
+	seen := make(map[string]bool)
+	var walk func(node *FileNode, parent string)
+	walk = func(node *FileNode, parent string) {
+		for _, child := range node.Children {
+			rel, err := filepath.Rel(dir, child.Path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			rel = filepath.ToSlash(rel)
+			seen[rel] = true
+
+			w, ok := wants[rel]
+			switch {
+			case !ok:
+				t.Errorf("unexpected node %s", rel)
+			case child.IsDir != w.dir:
+				t.Errorf("%s: IsDir = %v, want %v", rel, child.IsDir, w.dir)
+			case parent != w.parent:
+				t.Errorf("%s: under %q, want %q", rel, parent, w.parent)
+			case !w.dir && child.Size != w.size:
+				t.Errorf("%s: size %d, want %d", rel, child.Size, w.size)
+			}
+			if child.Name != filepath.Base(child.Path) {
+				t.Errorf("%s: name %q", rel, child.Name)
+			}
+			if !child.IsDir && len(child.Children) > 0 {
+				t.Errorf("%s: file has children", rel)
+			}
+			walk(child, rel)
+		}
+	}
+	walk(root, "")
+
+	for rel := range wants {
+		if !seen[rel] {
+			t.Errorf("missing node %s", rel)
+		}
+	}
+}
+
+func TestGoTypeParamsStayOutOfNames(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"store.go": `package store
+
+type Map[K comparable, V any] struct {
+	items map[K]V
+}
+
+func (m *Map[K, V]) Get(key K) V { return m.items[key] }
+
+type Getter[K comparable, V any] interface {
+	Get(key K) V
+}
+
+func Keys[K comparable, V any](m map[K]V) []K { return nil }
+`,
+	})
+
+	classes := ParseClasses(dir)
+	if len(classes) != 1 {
+		t.Fatalf("ParseClasses = %d classes, want 1", len(classes))
+	}
+	if c := classes[0]; c.Name != "Map" || c.TypeParams != "[K comparable, V any]" {
+		t.Errorf("class = %q %q, want \"Map\" \"[K comparable, V any]\"", c.Name, c.TypeParams)
+	}
+	if methods := classes[0].Methods; len(methods) != 1 || methods[0].Name != "Get" {
+		t.Errorf("Map methods = %+v, want Get", methods)
+	}
+
+	ifaces := ParseInterfaces(dir)
+	if len(ifaces) != 1 || ifaces[0].Name != "Getter" || ifaces[0].TypeParams != "[K comparable, V any]" {
+		t.Errorf("ParseInterfaces = %+v, want Getter [K comparable, V any]", ifaces)
+	}
+
+	for _, funcs := range [][]FunctionInfo{ParseFunctions(dir), ParseFunctionsMultiLang(dir)} {
+		found := false
+		for _, fn := range funcs {
+			if fn.Name == "Keys" {
+				found = true
+				if fn.TypeParams != "[K comparable, V any]" {
+					t.Errorf("Keys type params = %q, want \"[K comparable, V any]\"", fn.TypeParams)
+				}
+			}
+		}
+		if !found {
+			t.Errorf("Keys missing from %+v", funcs)
+		}
+	}
+}
+
+func TestFunctionSizesEndAtLastLine(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"a.py": "def f():\n    return 1\n\n\ndef g():\n    return 2\n",
+	})
+	path := filepath.Join(dir, "a.py")
+
+	total := QuickFileStats(path).Lines
+	if total != 6 {
+		t.Fatalf("QuickFileStats lines = %d, want 6", total)
+	}
+	covered := 0
+	for _, fn := range FunctionSizes(path) {
+		covered += fn.Lines()
+		if fn.EndLine > total {
+			t.Errorf("%s ends at line %d of %d", fn.Name, fn.EndLine, total)
+		}
+	}
+	if covered != total {
+		t.Errorf("functions cover %d lines, want %d", covered, total)
+	}
+}
+
+func TestStatsLanguageLinesMatchLOC(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"main.go":   "package main\n\n// main does nothing\nfunc main() {}\n",
+		"util.go":   "package main\n\nfunc helper() {}", // No final newline
+		"script.py": "def f():\n    return 1\n",
+	})
+
+	stats := ParseStats(dir)
+	for _, lang := range CountLOC(dir).Languages {
+		want := lang.Code + lang.Comments + lang.Blanks
+		if got := stats.LanguageLines[lang.Language]; got != want {
+			t.Errorf("%s: /stats counts %d lines, /loc %d", lang.Language, got, want)
+		}
+	}
+	if got := stats.LanguageLines["go"]; got != 7 {
+		t.Errorf("go lines = %d, want 7", got)
+	}
+}
+
+func TestIgnoreRules(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		".gitignore": "# build output\n*.log\n!keep.log\nbuild/\n/root.txt\ndocs/*.md\n!docs/README.md\n**/gen\n*.tmp\n",
+		// Read last, so it can re-include what .gitignore excludes
+		ArcsiIgnoreFile: "!notes.tmp\n",
+	})
+	ig := LoadIgnore(dir)
+
+	tests := []struct {
+		path  string
+		isDir bool
+		want  bool
+	}{
+		{"app.log", false, true},     // Unanchored: matches by name
+		{"sub/app.log", false, true}, // ... at any depth
+		{"keep.log", false, false},   // Negated by a later rule
+		{"sub/keep.log", false, false},
+		{"build", true, true},   // Dir-only rule
+		{"build", false, false}, // ... skips files of that name
+		{"sub/build", true, true},
+		{"build/out.bin", false, true}, // Inside an ignored directory
+		{"root.txt", false, true},      // Anchored by a leading "/"
+		{"sub/root.txt", false, false},
+		{"docs/guide.md", false, true}, // Anchored by an inner "/"
+		{"sub/docs/guide.md", false, false},
+		{"docs/README.md", false, false}, // Last match wins
+		{"gen", true, true},              // "**" matches zero directories
+		{"a/b/gen", true, true},          // ... or several
+		{"x.tmp", false, true},
+		{"notes.tmp", false, false}, // .arcsiignore overrides .gitignore
+		{"main.go", false, false},
+		{".", true, false}, // The root itself is never ignored
+	}
+	for _, tt := range tests {
+		path := filepath.Join(dir, filepath.FromSlash(tt.path))
+		if got := ig.Match(path, tt.isDir); got != tt.want {
+			t.Errorf("Match(%q, dir=%v) = %v, want %v", tt.path, tt.isDir, got, tt.want)
+		}
+	}
+
+	var nilIgnore *Ignore
+	if nilIgnore.Match(filepath.Join(dir, "app.log"), false) {
+		t.Error("a nil Ignore matched a path")
+	}
+}
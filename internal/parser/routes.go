@@ -0,0 +1,210 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Route represents an HTTP route registration
+type Route struct {
+	Method  string
+	Path    string
+	Handler string
+	File    string
+	Line    int
+}
+
+// routePattern describes one way a framework registers routes. Group
+// indexes refer to regex submatches; 0 means the value isn't captured.
+type routePattern struct {
+	regex   *regexp.Regexp
+	method  int
+	path    int
+	handler int
+	methods int // A list of methods, e.g. Flask's methods=[...]
+	// Decorators and annotations name the handler on a following line
+	decorator bool
+}
+
+var (
+	goRoutes = []routePattern{
+		// net/http and gorilla/mux: HandleFunc("/path", handler), Go 1.22 "GET /path"
+		{regex: regexp.MustCompile(`\.Handle(?:Func)?\(\s*"([^"]+)"\s*,\s*([\w.]+)`), path: 1, handler: 2},
+		// gin, echo, fiber: r.GET("/path", handler)
+		{regex: regexp.MustCompile(`\.(GET|POST|PUT|PATCH|DELETE|HEAD|OPTIONS|Any)\(\s*"([^"]+)"\s*,\s*([\w.]+)`), method: 1, path: 2, handler: 3},
+		// chi: r.Get("/path", handler)
+		{regex: regexp.MustCompile(`\.(Get|Post|Put|Patch|Delete|Head|Options)\(\s*"([^"]+)"\s*,\s*([\w.]+)`), method: 1, path: 2, handler: 3},
+	}
+
+	jsRoutes = []routePattern{
+		// Express, Koa router, Fastify: app.get('/path', handler)
+		{regex: regexp.MustCompile(`\b(?:app|router|server|fastify)\.(get|post|put|patch|delete|head|options|all)\(\s*['"` + "`" + `]([^'"` + "`" + `]+)['"` + "`" + `]\s*(?:,\s*([\w.]+))?`), method: 1, path: 2, handler: 3},
+	}
+
+	pyRoutes = []routePattern{
+		// Flask: @app.route('/path', methods=['GET', 'POST'])
+		{regex: regexp.MustCompile(`@\w+\.route\(\s*['"]([^'"]+)['"](?:.*methods\s*=\s*[\[(]([^\])]+)[\])])?`), path: 1, methods: 2, decorator: true},
+		// FastAPI and Flask 2 shortcuts: @app.get('/path')
+		{regex: regexp.MustCompile(`@\w+\.(get|post|put|patch|delete|head|options)\(\s*['"]([^'"]+)['"]`), method: 1, path: 2, decorator: true},
+	}
+
+	javaRoutes = []routePattern{
+		// Spring: @GetMapping("/path"), @RequestMapping(value = "/path")
+		{regex: regexp.MustCompile(`@(Get|Post|Put|Patch|Delete|Request)Mapping\(\s*(?:(?:value|path)\s*=\s*)?"([^"]+)"`), method: 1, path: 2, decorator: true},
+	}
+
+	routePatterns = map[string][]routePattern{
+		".go":   goRoutes,
+		".js":   jsRoutes,
+		".jsx":  jsRoutes,
+		".mjs":  jsRoutes,
+		".ts":   jsRoutes,
+		".tsx":  jsRoutes,
+		".py":   pyRoutes,
+		".java": javaRoutes,
+		".kt":   javaRoutes,
+	}
+)
+
+// ParseRoutes finds HTTP route registrations for common web frameworks
+func ParseRoutes(root string) []Route {
+	var routes []Route
+
+	ignore := LoadArcsiIgnore(root)
+	filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if skip, result := ignore.Skip(path, info); skip {
+			return result
+		}
+		if err != nil || info.IsDir() {
+			return nil
+		}
+
+		name := info.Name()
+		if strings.HasPrefix(name, ".") || strings.Contains(name, "_test.") || strings.Contains(name, ".test.") || strings.Contains(name, ".spec.") {
+			return nil
+		}
+
+		if strings.Contains(path, "node_modules") || strings.Contains(path, "vendor") || strings.Contains(path, "__pycache__") || strings.Contains(path, ".git") {
+			return nil
+		}
+
+		patterns := routePatterns[strings.ToLower(filepath.Ext(name))]
+		if patterns == nil {
+			return nil
+		}
+		lang := getLanguageForFile(name)
+
+		src, ok := readSource(path)
+		if !ok {
+			return nil
+		}
+
+		rel, _ := filepath.Rel(root, path)
+		scanner := newLineScanner(src)
+		lineNum := 0
+		var pending []Route // Decorated routes waiting for their handler
+
+		// Commented-out and example registrations aren't routes. Paths
+		// are strings, so literals are kept.
+		var comments *commentFilter
+		if lang != nil {
+			comments = newCommentFilter(lang)
+		}
+
+		for scanner.Scan() {
+			line := scanner.Text()
+			if comments != nil {
+				line, _ = comments.strip(line)
+			}
+			lineNum++
+
+			if len(pending) > 0 && lang != nil && lang.FuncRegex != nil {
+				if handler := lang.funcName(line); handler != "" {
+					for _, route := range pending {
+						route.Handler = handler
+						routes = append(routes, route)
+					}
+					pending = nil
+				}
+			}
+
+			for _, pattern := range patterns {
+				matches := pattern.regex.FindStringSubmatch(line)
+				if matches == nil {
+					continue
+				}
+
+				found := routesFromMatch(pattern, matches)
+				for i := range found {
+					found[i].File = rel
+					found[i].Line = lineNum
+				}
+
+				if pattern.decorator {
+					pending = append(pending, found...)
+				} else {
+					routes = append(routes, found...)
+				}
+				break
+			}
+		}
+
+		// Decorators without a recognizable handler still count
+		routes = append(routes, pending...)
+		return nil
+	})
+
+	return routes
+}
+
+// routesFromMatch builds routes from a single pattern match
+func routesFromMatch(pattern routePattern, matches []string) []Route {
+	path := matches[pattern.path]
+	method := "ANY"
+	if pattern.method > 0 {
+		method = strings.ToUpper(matches[pattern.method])
+	}
+
+	// Spring's @RequestMapping and gin's Any accept every method
+	if method == "REQUEST" {
+		method = "ANY"
+	}
+
+	// Go 1.22 method patterns: "GET /users/{id}"
+	if parts := strings.SplitN(path, " ", 2); len(parts) == 2 && pattern.method == 0 {
+		method = strings.ToUpper(parts[0])
+		path = strings.TrimSpace(parts[1])
+	}
+
+	handler := ""
+	if pattern.handler > 0 {
+		handler = matches[pattern.handler]
+	}
+
+	methods := []string{method}
+	if pattern.methods > 0 {
+		if list := matches[pattern.methods]; list != "" {
+			methods = nil
+			for _, m := range strings.Split(list, ",") {
+				m = strings.Trim(strings.TrimSpace(m), `'"`)
+				if m != "" {
+					methods = append(methods, strings.ToUpper(m))
+				}
+			}
+		} else {
+			methods = []string{"GET"} // Flask's default
+		}
+	}
+
+	var routes []Route
+	for _, m := range methods {
+		routes = append(routes, Route{
+			Method:  m,
+			Path:    path,
+			Handler: handler,
+		})
+	}
+	return routes
+}
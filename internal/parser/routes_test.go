@@ -0,0 +1,37 @@
+package parser
+
+import "testing"
+
+func TestParseRoutesSkipsComments(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"server.go": `package main
+
+// Register with r.GET("/commented", handler)
+/*
+	mux.HandleFunc("/blocked", old)
+*/
+func routes() {
+	r.GET("/users", listUsers) // was r.GET("/people", listPeople)
+}
+`,
+		"app.py": `# @app.route('/old')
+@app.get('/items')
+def items():
+    pass
+`,
+	})
+
+	got := make(map[string]bool)
+	for _, route := range ParseRoutes(dir) {
+		got[route.Method+" "+route.Path] = true
+	}
+	for _, want := range []string{"GET /users", "GET /items"} {
+		if !got[want] {
+			t.Errorf("missing route %s; got %v", want, got)
+		}
+	}
+	if len(got) != 2 {
+		t.Errorf("got routes %v, want only GET /users and GET /items", got)
+	}
+}
@@ -0,0 +1,212 @@
+package parser
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Symbol kinds reported by SearchSymbols, in display order
+const (
+	SymbolFunction  = "function"
+	SymbolMethod    = "method"
+	SymbolStruct    = "struct" // Also classes in other languages
+	SymbolInterface = "interface"
+)
+
+var symbolKindOrder = map[string]int{
+	SymbolFunction:  0,
+	SymbolMethod:    1,
+	SymbolStruct:    2,
+	SymbolInterface: 3,
+}
+
+// SymbolHit is one symbol whose name matched a search
+type SymbolHit struct {
+	Name     string
+	Kind     string
+	Receiver string // Type a method belongs to
+	Package  string
+	File     string // Relative to the project root
+	Line     int
+}
+
+// SearchSymbols finds the functions, methods, structs, classes and
+// interfaces under root whose name contains query, ignoring case. Go
+// files are read with go/parser and other languages with the regex
+// patterns. Hits are sorted by kind, then name, then location.
+func SearchSymbols(root, query string) []SymbolHit {
+	query = strings.ToLower(strings.TrimSpace(query))
+	if query == "" {
+		return nil
+	}
+
+	var hits []SymbolHit
+	add := func(hit SymbolHit) {
+		if strings.Contains(strings.ToLower(hit.Name), query) {
+			hits = append(hits, hit)
+		}
+	}
+	fset := token.NewFileSet()
+
+	ignore := LoadIgnore(root)
+	filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if skip, result := ignore.Skip(path, info); skip {
+			return result
+		}
+		if err != nil {
+			return nil
+		}
+		name := info.Name()
+		if info.IsDir() {
+			if path != root && (strings.HasPrefix(name, ".") || name == "vendor" || name == "node_modules" || name == "__pycache__") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if strings.HasPrefix(name, ".") || strings.Contains(name, "_test.") || strings.Contains(name, ".test.") || strings.Contains(name, ".spec.") {
+			return nil
+		}
+
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			rel = path
+		}
+
+		if strings.HasSuffix(name, ".go") {
+			if node, err := parser.ParseFile(fset, path, nil, 0); err == nil {
+				for _, hit := range goSymbols(fset, node) {
+					hit.File = rel
+					add(hit)
+				}
+				return nil
+			}
+			// Fall through to the regex patterns for files go/parser rejects
+		}
+
+		lang := getLanguageForFile(name)
+		if lang == nil {
+			return nil
+		}
+		src, ok := readSource(path)
+		if !ok {
+			return nil
+		}
+		pkg := filepath.Dir(rel)
+		if pkg == "." {
+			pkg = "root"
+		}
+		for _, hit := range regexSymbols(src, path, pkg, lang) {
+			hit.File = rel
+			add(hit)
+		}
+		return nil
+	})
+
+	sort.SliceStable(hits, func(i, j int) bool {
+		a, b := hits[i], hits[j]
+		if a.Kind != b.Kind {
+			return symbolKindOrder[a.Kind] < symbolKindOrder[b.Kind]
+		}
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		return a.Line < b.Line
+	})
+	return hits
+}
+
+// goSymbols lists the top-level functions, methods, structs and
+// interfaces of a parsed Go file
+func goSymbols(fset *token.FileSet, node *ast.File) []SymbolHit {
+	var symbols []SymbolHit
+	pkg := node.Name.Name
+
+	for _, decl := range node.Decls {
+		switch d := decl.(type) {
+		case *ast.FuncDecl:
+			hit := SymbolHit{
+				Name:    d.Name.Name,
+				Kind:    SymbolFunction,
+				Package: pkg,
+				Line:    fset.Position(d.Pos()).Line,
+			}
+			if d.Recv != nil && len(d.Recv.List) > 0 {
+				hit.Kind = SymbolMethod
+				hit.Receiver = strings.TrimPrefix(exprToString(d.Recv.List[0].Type), "*")
+			}
+			symbols = append(symbols, hit)
+
+		case *ast.GenDecl:
+			if d.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range d.Specs {
+				typeSpec, ok := spec.(*ast.TypeSpec)
+				if !ok {
+					continue
+				}
+				var kind string
+				switch typeSpec.Type.(type) {
+				case *ast.StructType:
+					kind = SymbolStruct
+				case *ast.InterfaceType:
+					kind = SymbolInterface
+				default:
+					continue
+				}
+				symbols = append(symbols, SymbolHit{
+					Name:    typeSpec.Name.Name,
+					Kind:    kind,
+					Package: pkg,
+					Line:    fset.Position(typeSpec.Pos()).Line,
+				})
+			}
+		}
+	}
+	return symbols
+}
+
+// regexSymbols lists the symbols the regex patterns find in one file.
+// Functions scanClasses attached to a class are reported as its methods.
+func regexSymbols(src, path, pkg string, lang *LanguagePattern) []SymbolHit {
+	var symbols []SymbolHit
+	methodLines := make(map[int]bool)
+
+	classes, ifaces := scanClasses(src, path, pkg, lang)
+	for _, iface := range ifaces {
+		symbols = append(symbols, SymbolHit{Name: iface.Name, Kind: SymbolInterface, Package: pkg, Line: iface.Line})
+		for _, method := range iface.Methods {
+			methodLines[method.Line] = true
+		}
+	}
+	for _, class := range classes {
+		symbols = append(symbols, SymbolHit{Name: class.Name, Kind: SymbolStruct, Package: pkg, Line: class.Line})
+		for _, method := range class.Methods {
+			methodLines[method.Line] = true
+			symbols = append(symbols, SymbolHit{
+				Name:     method.Name,
+				Kind:     SymbolMethod,
+				Receiver: class.Name,
+				Package:  pkg,
+				Line:     method.Line,
+			})
+		}
+	}
+
+	if lang.FuncRegex != nil {
+		for _, fn := range scanFunctions(src, path, pkg, lang) {
+			if !methodLines[fn.Line] {
+				symbols = append(symbols, SymbolHit{Name: fn.Name, Kind: SymbolFunction, Package: pkg, Line: fn.Line})
+			}
+		}
+	}
+	return symbols
+}
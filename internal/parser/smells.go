@@ -0,0 +1,167 @@
+package parser
+
+import (
+	"math"
+	"path/filepath"
+	"sort"
+)
+
+// GodObjectSigma is how many standard deviations above the project mean
+// a metric must be before it's flagged
+const GodObjectSigma = 2.0
+
+// minSmellSample is the smallest population a threshold is computed
+// from; below it the distribution says nothing useful
+const minSmellSample = 4
+
+// SmellMetric is one measurement that exceeded its threshold
+type SmellMetric struct {
+	Name      string
+	Value     int
+	Threshold float64 // mean + GodObjectSigma·stddev over the project
+}
+
+// Excess returns how far the value is above the threshold
+func (m SmellMetric) Excess() float64 {
+	return float64(m.Value) - m.Threshold
+}
+
+// GodObject is a struct or package that is unusually large compared to
+// the rest of the project
+type GodObject struct {
+	Name    string
+	Package string
+	Kind    string // "struct" or "package"
+	File    string // Defining file for structs, directory for packages
+	Metrics []SmellMetric
+}
+
+// DetectGodObjects flags structs with an unusually high number of fields
+// or methods and packages with an unusually high number of types or
+// methods. Thresholds come from the project's own distribution, so a
+// large codebase isn't judged by a small one's standards.
+func DetectGodObjects(classes []ClassInfo) []GodObject {
+	var objects []GodObject
+
+	fields := make([]int, len(classes))
+	methods := make([]int, len(classes))
+	for i, class := range classes {
+		fields[i] = len(class.Fields)
+		methods[i] = len(class.Methods)
+	}
+	fieldLimit, fieldOK := smellThreshold(fields)
+	methodLimit, methodOK := smellThreshold(methods)
+
+	for i, class := range classes {
+		var metrics []SmellMetric
+		if fieldOK && float64(fields[i]) > fieldLimit {
+			metrics = append(metrics, SmellMetric{Name: "fields", Value: fields[i], Threshold: fieldLimit})
+		}
+		if methodOK && float64(methods[i]) > methodLimit {
+			metrics = append(metrics, SmellMetric{Name: "methods", Value: methods[i], Threshold: methodLimit})
+		}
+		if len(metrics) > 0 {
+			objects = append(objects, GodObject{
+				Name:    class.Name,
+				Package: class.Package,
+				Kind:    "struct",
+				File:    class.File,
+				Metrics: metrics,
+			})
+		}
+	}
+
+	// Packages are identified by directory, since names like "main" repeat
+	type packageSize struct {
+		name    string
+		types   int
+		methods int
+	}
+	packages := make(map[string]*packageSize)
+	for _, class := range classes {
+		dir := filepath.Dir(class.File)
+		pkg, ok := packages[dir]
+		if !ok {
+			pkg = &packageSize{name: class.Package}
+			packages[dir] = pkg
+		}
+		pkg.types++
+		pkg.methods += len(class.Methods)
+	}
+
+	dirs := make([]string, 0, len(packages))
+	var types, pkgMethods []int
+	for dir, pkg := range packages {
+		dirs = append(dirs, dir)
+		types = append(types, pkg.types)
+		pkgMethods = append(pkgMethods, pkg.methods)
+	}
+	sort.Strings(dirs)
+	typeLimit, typeOK := smellThreshold(types)
+	pkgMethodLimit, pkgMethodOK := smellThreshold(pkgMethods)
+
+	for _, dir := range dirs {
+		pkg := packages[dir]
+		var metrics []SmellMetric
+		if typeOK && float64(pkg.types) > typeLimit {
+			metrics = append(metrics, SmellMetric{Name: "types", Value: pkg.types, Threshold: typeLimit})
+		}
+		if pkgMethodOK && float64(pkg.methods) > pkgMethodLimit {
+			metrics = append(metrics, SmellMetric{Name: "methods", Value: pkg.methods, Threshold: pkgMethodLimit})
+		}
+		if len(metrics) > 0 {
+			objects = append(objects, GodObject{
+				Name:    pkg.name,
+				Package: pkg.name,
+				Kind:    "package",
+				File:    dir,
+				Metrics: metrics,
+			})
+		}
+	}
+
+	// Worst offenders first
+	sort.SliceStable(objects, func(i, j int) bool {
+		return worstExcess(objects[i]) > worstExcess(objects[j])
+	})
+
+	return objects
+}
+
+// smellThreshold returns mean + GodObjectSigma·stddev of values. The
+// second result is false when there are too few values, or they are all
+// equal, to call anything an outlier.
+func smellThreshold(values []int) (float64, bool) {
+	if len(values) < minSmellSample {
+		return 0, false
+	}
+
+	var sum float64
+	for _, v := range values {
+		sum += float64(v)
+	}
+	mean := sum / float64(len(values))
+
+	var variance float64
+	for _, v := range values {
+		d := float64(v) - mean
+		variance += d * d
+	}
+	stddev := math.Sqrt(variance / float64(len(values)))
+	if stddev == 0 {
+		return 0, false
+	}
+
+	return mean + GodObjectSigma*stddev, true
+}
+
+// worstExcess returns the largest relative excess of an object's metrics
+func worstExcess(obj GodObject) float64 {
+	worst := 0.0
+	for _, m := range obj.Metrics {
+		if m.Threshold > 0 {
+			worst = math.Max(worst, m.Excess()/m.Threshold)
+		}
+	}
+	return worst
+}
@@ -0,0 +1,74 @@
+package parser
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"strings"
+	"unicode/utf8"
+)
+
+// utf8BOM is the byte order mark some Windows editors put at the start
+// of UTF-8 files
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// stripBOM removes a leading UTF-8 byte order mark, which would otherwise
+// stop line-anchored patterns from matching the first line
+func stripBOM(data []byte) []byte {
+	return bytes.TrimPrefix(data, utf8BOM)
+}
+
+// readSource reads a text file for the regex parsers. Binary files are
+// rejected, a leading BOM is dropped, and content that isn't valid UTF-8
+// is decoded as Latin-1 so symbol names never contain invalid byte
+// sequences.
+func readSource(path string) (string, bool) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", false
+	}
+	data = stripBOM(data)
+
+	if isBinary(data) {
+		return "", false
+	}
+
+	if utf8.Valid(data) {
+		return string(data), true
+	}
+
+	return decodeLatin1(data), true
+}
+
+// newLineScanner returns a line scanner over src. The default 64KB token
+// limit stops scanning at the first longer line (common in minified or
+// generated code), so the limit is raised to the size of the source itself.
+func newLineScanner(src string) *bufio.Scanner {
+	scanner := bufio.NewScanner(strings.NewReader(src))
+	scanner.Buffer(make([]byte, 0, 64*1024), len(src)+1)
+	return scanner
+}
+
+// isBinary checks if data appears to be binary
+func isBinary(data []byte) bool {
+	if len(data) > 512 {
+		data = data[:512]
+	}
+	for _, b := range data {
+		if b == 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// decodeLatin1 converts ISO-8859-1 bytes to a UTF-8 string. Every byte
+// maps to the code point of the same value, so the result is always valid.
+func decodeLatin1(data []byte) string {
+	var sb strings.Builder
+	sb.Grow(len(data))
+	for _, b := range data {
+		sb.WriteRune(rune(b))
+	}
+	return sb.String()
+}
@@ -0,0 +1,97 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// maxTodoText caps the comment text kept for one marker
+const maxTodoText = 120
+
+// TodoItem is a TODO, FIXME, HACK or XXX marker in a comment
+type TodoItem struct {
+	Text string // Comment text after the marker
+	Path string // Relative to the project root
+	Line int
+	Tag  string // "TODO", "FIXME", "HACK" or "XXX"
+}
+
+// todoMarkerRegex matches a marker that opens a comment, as in
+// "// TODO: x", "# FIXME(ana) x", "/* HACK x" or " * XXX x". Markers
+// elsewhere in a comment ("matches TODO markers") are prose, not tasks.
+var todoMarkerRegex = regexp.MustCompile(`(?:^\s*\*|//|#|/\*|<!--|--)\s*(TODO|FIXME|HACK|XXX)\b(?:\([^)]*\))?[:\s-]*(.*)`)
+
+// ParseTodos lists the TODO, FIXME, HACK and XXX comments in every
+// source file under root, in any language arcsii recognizes, sorted by
+// path and line
+func ParseTodos(root string) []TodoItem {
+	var todos []TodoItem
+
+	ignore := LoadIgnore(root)
+	filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if skip, result := ignore.Skip(path, info); skip {
+			return result
+		}
+		if err != nil || info.IsDir() {
+			return nil
+		}
+
+		name := info.Name()
+		if strings.HasPrefix(name, ".") {
+			return nil
+		}
+		if strings.Contains(path, "node_modules") || strings.Contains(path, "vendor") || strings.Contains(path, "__pycache__") || strings.Contains(path, ".git") {
+			return nil
+		}
+		if _, ok := languageTags[strings.ToLower(filepath.Ext(name))]; !ok && getLanguageForFile(name) == nil {
+			return nil
+		}
+
+		src, ok := readSource(path)
+		if !ok {
+			return nil
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			rel = path
+		}
+
+		scanner := newLineScanner(src)
+		lineNum := 0
+		for scanner.Scan() {
+			lineNum++
+			matches := todoMarkerRegex.FindStringSubmatch(scanner.Text())
+			if matches == nil {
+				continue
+			}
+			todos = append(todos, TodoItem{
+				Text: todoText(matches[2]),
+				Path: rel,
+				Line: lineNum,
+				Tag:  matches[1],
+			})
+		}
+		return nil
+	})
+
+	sort.SliceStable(todos, func(i, j int) bool {
+		if todos[i].Path != todos[j].Path {
+			return todos[i].Path < todos[j].Path
+		}
+		return todos[i].Line < todos[j].Line
+	})
+	return todos
+}
+
+// todoText trims comment closers from a marker's text and caps its length
+func todoText(text string) string {
+	text = strings.TrimSpace(text)
+	text = strings.TrimSpace(strings.TrimSuffix(strings.TrimSuffix(text, "*/"), "-->"))
+	if len(text) > maxTodoText {
+		text = strings.ToValidUTF8(text[:maxTodoText-1], "") + "…"
+	}
+	return text
+}
@@ -0,0 +1,171 @@
+package parser
+
+import (
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// checkedPackage is a type-checked Go package
+type checkedPackage struct {
+	Dir   string
+	Pkg   *types.Package
+	Files []*ast.File
+	Info  *types.Info
+}
+
+// loadGoPackages parses and type-checks every Go package under root.
+// Type errors (e.g. unresolved imports) are tolerated so partial results
+// are still available.
+func loadGoPackages(root string, sizes types.Sizes) (*token.FileSet, []checkedPackage) {
+	fset := token.NewFileSet()
+	files := make(map[string][]*ast.File)
+	dirs := make(map[string]string)
+
+	ignore := LoadArcsiIgnore(root)
+	filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if skip, result := ignore.Skip(path, info); skip {
+			return result
+		}
+		if err != nil {
+			return nil
+		}
+
+		name := info.Name()
+		if info.IsDir() {
+			if path != root && (strings.HasPrefix(name, ".") || name == "vendor" || name == "testdata" || name == "node_modules") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") {
+			return nil
+		}
+
+		node, err := parser.ParseFile(fset, path, nil, 0)
+		if err != nil {
+			return nil
+		}
+
+		// Key by directory and package name so stray files don't collide
+		dir := filepath.Dir(path)
+		key := dir + "|" + node.Name.Name
+		files[key] = append(files[key], node)
+		dirs[key] = dir
+		return nil
+	})
+
+	keys := make([]string, 0, len(files))
+	for key := range files {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	loader := &projectImporter{
+		fallback:   importer.Default(),
+		modulePath: readModulePath(root),
+		root:       root,
+		byDir:      make(map[string]string),
+		checked:    make(map[string]*checkedPackage),
+		files:      files,
+		dirs:       dirs,
+		sizes:      sizes,
+		fset:       fset,
+	}
+	for _, key := range keys {
+		// Prefer the non-main package when a directory holds several
+		if _, exists := loader.byDir[dirs[key]]; !exists || !strings.HasSuffix(key, "|main") {
+			loader.byDir[dirs[key]] = key
+		}
+	}
+
+	var packages []checkedPackage
+	for _, key := range keys {
+		if cp := loader.check(key); cp != nil {
+			packages = append(packages, *cp)
+		}
+	}
+
+	return fset, packages
+}
+
+// projectImporter resolves imports of packages inside the project from
+// source, so calls and types across packages link to the same objects.
+// Everything else is delegated to the default importer.
+type projectImporter struct {
+	fallback   types.Importer
+	modulePath string
+	root       string
+	byDir      map[string]string
+	checked    map[string]*checkedPackage
+	files      map[string][]*ast.File
+	dirs       map[string]string
+	sizes      types.Sizes
+	fset       *token.FileSet
+}
+
+func (p *projectImporter) Import(path string) (*types.Package, error) {
+	if p.modulePath != "" && (path == p.modulePath || strings.HasPrefix(path, p.modulePath+"/")) {
+		rel := strings.TrimPrefix(strings.TrimPrefix(path, p.modulePath), "/")
+		dir := filepath.Join(p.root, filepath.FromSlash(rel))
+		if key, ok := p.byDir[dir]; ok {
+			if cp := p.check(key); cp != nil {
+				return cp.Pkg, nil
+			}
+		}
+	}
+	return p.fallback.Import(path)
+}
+
+// check type-checks a package once, importing project packages on demand
+func (p *projectImporter) check(key string) *checkedPackage {
+	if cp, done := p.checked[key]; done {
+		return cp
+	}
+	p.checked[key] = nil // Guards against import cycles
+
+	info := &types.Info{
+		Uses: make(map[*ast.Ident]types.Object),
+		Defs: make(map[*ast.Ident]types.Object),
+	}
+	conf := types.Config{
+		Importer: p,
+		Sizes:    p.sizes,
+		Error:    func(error) {}, // Keep going on unresolved imports
+	}
+	pkg, _ := conf.Check(p.dirs[key], p.fset, p.files[key], info)
+	if pkg == nil {
+		return nil
+	}
+
+	cp := &checkedPackage{
+		Dir:   p.dirs[key],
+		Pkg:   pkg,
+		Files: p.files[key],
+		Info:  info,
+	}
+	p.checked[key] = cp
+	return cp
+}
+
+// readModulePath returns the module path declared in root/go.mod
+func readModulePath(root string) string {
+	data, err := os.ReadFile(filepath.Join(root, "go.mod"))
+	if err != nil {
+		return ""
+	}
+	for _, line := range strings.Split(string(stripBOM(data)), "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "module ") {
+			return strings.Trim(strings.TrimSpace(strings.TrimPrefix(line, "module ")), `"`)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,210 @@
+package parser
+
+import (
+	"encoding/json"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// workspaceFiles are checked in order; the first one present wins
+var workspaceFiles = []string{"go.work", "pnpm-workspace.yaml", "package.json"}
+
+// WorkspaceFile returns the name of the workspace file defining root as
+// a multi-module workspace, or "". A package.json only counts when it
+// declares workspaces.
+func WorkspaceFile(root string) string {
+	for _, name := range workspaceFiles {
+		data, err := os.ReadFile(filepath.Join(root, name))
+		if err != nil {
+			continue
+		}
+		if name == "package.json" && len(packageJSONWorkspaces(data)) == 0 {
+			continue
+		}
+		return name
+	}
+	return ""
+}
+
+// ParseWorkspace returns the member directories of a go.work,
+// pnpm-workspace.yaml or package.json workspace at root, relative to
+// root. Glob patterns are expanded; JS members must have a package.json.
+func ParseWorkspace(root string) ([]string, error) {
+	name := WorkspaceFile(root)
+	if name == "" {
+		return nil, ErrNoWorkspace
+	}
+
+	data, err := os.ReadFile(filepath.Join(root, name))
+	if err != nil {
+		return nil, err
+	}
+	data = stripBOM(data)
+
+	var members []string
+	switch name {
+	case "go.work":
+		for _, dir := range goWorkUses(data) {
+			if info, err := os.Stat(filepath.Join(root, dir)); err == nil && info.IsDir() {
+				members = append(members, filepath.Clean(dir))
+			}
+		}
+	case "pnpm-workspace.yaml":
+		members = expandWorkspaceGlobs(root, pnpmPackages(data))
+	case "package.json":
+		members = expandWorkspaceGlobs(root, packageJSONWorkspaces(data))
+	}
+
+	sort.Strings(members)
+	return members, nil
+}
+
+// goWorkUses reads the directories of go.work use directives, in both
+// the single-line and block forms
+func goWorkUses(data []byte) []string {
+	var dirs []string
+	inUse := false
+	for _, line := range strings.Split(string(data), "\n") {
+		code, _, _ := strings.Cut(line, "//")
+		code = strings.TrimSpace(code)
+
+		if inUse {
+			if code == ")" {
+				inUse = false
+			} else if code != "" {
+				dirs = append(dirs, strings.Trim(code, `"`))
+			}
+			continue
+		}
+
+		fields := strings.Fields(code)
+		if len(fields) < 2 || fields[0] != "use" {
+			continue
+		}
+		if fields[1] == "(" {
+			inUse = true
+		} else {
+			dirs = append(dirs, strings.Trim(fields[1], `"`))
+		}
+	}
+	return dirs
+}
+
+// pnpmPackages reads the "packages:" list of pnpm-workspace.yaml. Only
+// the simple list form pnpm documents is supported.
+func pnpmPackages(data []byte) []string {
+	var patterns []string
+	inPackages := false
+	for _, line := range strings.Split(string(data), "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+
+		if !strings.HasPrefix(line, " ") && !strings.HasPrefix(line, "\t") && !strings.HasPrefix(trimmed, "-") {
+			inPackages = strings.HasPrefix(trimmed, "packages:")
+			continue
+		}
+		if inPackages && strings.HasPrefix(trimmed, "-") {
+			pattern := strings.TrimSpace(strings.TrimPrefix(trimmed, "-"))
+			pattern, _, _ = strings.Cut(pattern, " #")
+			patterns = append(patterns, strings.Trim(pattern, `"'`))
+		}
+	}
+	return patterns
+}
+
+// packageJSONWorkspaces reads npm/yarn "workspaces", either an array or
+// an object with a "packages" array
+func packageJSONWorkspaces(data []byte) []string {
+	var pkg struct {
+		Workspaces json.RawMessage `json:"workspaces"`
+	}
+	if json.Unmarshal(stripBOM(data), &pkg) != nil || len(pkg.Workspaces) == 0 {
+		return nil
+	}
+
+	var list []string
+	if json.Unmarshal(pkg.Workspaces, &list) == nil {
+		return list
+	}
+	var obj struct {
+		Packages []string `json:"packages"`
+	}
+	if json.Unmarshal(pkg.Workspaces, &obj) == nil {
+		return obj.Packages
+	}
+	return nil
+}
+
+// expandWorkspaceGlobs finds directories with a package.json matching
+// the patterns. "**" matches any number of path segments, and patterns
+// starting with "!" exclude.
+func expandWorkspaceGlobs(root string, patterns []string) []string {
+	var include, exclude []string
+	for _, p := range patterns {
+		p = strings.TrimPrefix(strings.TrimSuffix(p, "/"), "./")
+		if strings.HasPrefix(p, "!") {
+			exclude = append(exclude, strings.TrimPrefix(p, "!"))
+		} else if p != "" {
+			include = append(include, p)
+		}
+	}
+
+	var members []string
+	filepath.Walk(root, func(p string, info os.FileInfo, err error) error {
+		if err != nil || !info.IsDir() || p == root {
+			return nil
+		}
+		name := info.Name()
+		if strings.HasPrefix(name, ".") || name == "node_modules" {
+			return filepath.SkipDir
+		}
+
+		rel, _ := filepath.Rel(root, p)
+		rel = filepath.ToSlash(rel)
+		if !matchesAnyGlob(rel, include) || matchesAnyGlob(rel, exclude) {
+			return nil
+		}
+		if _, err := os.Stat(filepath.Join(p, "package.json")); err == nil {
+			members = append(members, filepath.FromSlash(rel))
+		}
+		return nil
+	})
+	return members
+}
+
+func matchesAnyGlob(rel string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if matchGlob(strings.Split(pattern, "/"), strings.Split(rel, "/")) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchGlob matches path segments against pattern segments, where a
+// "**" segment matches zero or more segments
+func matchGlob(pattern, segments []string) bool {
+	if len(pattern) == 0 {
+		return len(segments) == 0
+	}
+	if pattern[0] == "**" {
+		for i := 0; i <= len(segments); i++ {
+			if matchGlob(pattern[1:], segments[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(segments) == 0 {
+		return false
+	}
+	if ok, _ := path.Match(pattern[0], segments[0]); !ok {
+		return false
+	}
+	return matchGlob(pattern[1:], segments[1:])
+}
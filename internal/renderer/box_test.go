@@ -0,0 +1,55 @@
+package renderer
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/barisercan/arcsii/internal/parser"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// assertAligned fails unless every line of box has the same display width
+// and truncation left no rune cut in half
+func assertAligned(t *testing.T, box string) {
+	t.Helper()
+	if !utf8.ValidString(box) {
+		t.Errorf("box holds a split rune:\n%s", box)
+	}
+	lines := strings.Split(strings.TrimRight(box, "\n"), "\n")
+	want := lipgloss.Width(lines[0])
+	for i, line := range lines {
+		if w := lipgloss.Width(line); w != want {
+			t.Errorf("line %d is %d columns wide, want %d:\n%s", i, w, want, box)
+		}
+	}
+}
+
+func TestClassBoxAlignsNonASCIINames(t *testing.T) {
+	for _, name := range []string{"Café", "Ünïcödé", "Straße"} {
+		box := renderClassBox(parser.ClassInfo{
+			Name:    name,
+			Package: "menü",
+			Fields:  []parser.FieldInfo{{Name: "crème", Type: "brûlée"}},
+			Methods: []parser.MethodInfo{{Name: "Größe", Returns: []string{"int"}}},
+		})
+		assertAligned(t, box)
+	}
+}
+
+func TestModuleBoxAlignsEmojiIcons(t *testing.T) {
+	// The box lists four files, so each extension gets a box of its own
+	exts := []string{".go", ".js", ".ts", ".py", ".rs", ".java", ".kt", ".swift", ".cs", ".txt"}
+	for _, ext := range exts {
+		mod := parser.ModuleInfo{
+			Name:    "café",
+			Structs: []string{"Café", "Ünïcödé", strings.Repeat("Ü", 80)},
+			Funcs:   []string{"Größe", strings.Repeat("é", 80)},
+			Files:   []string{"main" + ext, "crème" + ext, strings.Repeat("ñ", 80) + ext},
+			Lines:   42,
+		}
+		for _, width := range []int{30, 60} {
+			assertAligned(t, renderCoolModuleBox(mod, 0, width))
+		}
+	}
+}
@@ -1,129 +1,299 @@
 package renderer
 
 import (
+	"encoding/json"
 	"fmt"
+	"html"
+	"io"
+	"path"
 	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/barisercan/arcsii/internal/parser"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
 )
 
+// Color palette and shared styles, set from the active theme by SetTheme
 var (
-	// Color palette
-	cyan       = lipgloss.Color("#4ECDC4")
-	pink       = lipgloss.Color("#FF6B6B")
-	yellow     = lipgloss.Color("#FFE66D")
-	purple     = lipgloss.Color("#A855F7")
-	green      = lipgloss.Color("#10B981")
-	blue       = lipgloss.Color("#3B82F6")
-	orange     = lipgloss.Color("#F97316")
-	gray       = lipgloss.Color("#6B7280")
-	white      = lipgloss.Color("#FFFFFF")
-	darkGray   = lipgloss.Color("#374151")
-
-	// Styles
-	headerStyle = lipgloss.NewStyle().
-			Bold(true).
-			Foreground(cyan).
-			BorderStyle(lipgloss.DoubleBorder()).
-			BorderForeground(cyan).
-			Padding(0, 2)
+	cyan, pink, yellow, purple lipgloss.TerminalColor
+	green, blue, orange        lipgloss.TerminalColor
+	amber, red                 lipgloss.TerminalColor
+	gray, white                lipgloss.TerminalColor
+
+	headerStyle    lipgloss.Style
+	boxStyle       lipgloss.Style
+	classBoxStyle  lipgloss.Style
+	methodStyle    lipgloss.Style
+	fieldStyle     lipgloss.Style
+	fileStyle      lipgloss.Style
+	dirStyle       lipgloss.Style
+	labelStyle     lipgloss.Style
+	dimStyle       lipgloss.Style
+	highlightStyle lipgloss.Style
+)
 
-	boxStyle = lipgloss.NewStyle().
-			Border(lipgloss.RoundedBorder()).
-			BorderForeground(purple).
-			Padding(0, 1)
+// CommandInfo describes a command on the welcome and help screens
+type CommandInfo struct {
+	Name        string
+	Description string
+	Shortcut    string // Key that runs the command, e.g. "F2"; may be empty
+}
 
-	classBoxStyle = lipgloss.NewStyle().
-			Border(lipgloss.RoundedBorder()).
-			BorderForeground(blue).
-			Padding(0, 1)
+// defaultWidth is used when the terminal width isn't known yet
+const defaultWidth = 80
 
-	methodStyle = lipgloss.NewStyle().
-			Foreground(green)
+// RenderWelcome renders the welcome screen, centered within width. On
+// narrow terminals the logo loses its frame and command descriptions
+// move below their names.
+func RenderWelcome(commands []CommandInfo, width int) string {
+	if width <= 0 {
+		width = defaultWidth
+	}
 
-	fieldStyle = lipgloss.NewStyle().
-			Foreground(yellow)
+	var sb strings.Builder
+	sb.WriteString("\n")
+	sb.WriteString(lipgloss.PlaceHorizontal(width, lipgloss.Center, renderLogo(width)))
+	sb.WriteString("\n\n")
+	sb.WriteString(lipgloss.PlaceHorizontal(width, lipgloss.Center, renderCommandBox(commands, width)))
+	sb.WriteString("\n\n")
+	sb.WriteString(dimStyle.Width(width).Align(lipgloss.Center).Render("💡 Tip: Type a command and press Enter to explore your codebase"))
+	sb.WriteString("\n")
 
-	fileStyle = lipgloss.NewStyle().
-			Foreground(cyan)
+	return sb.String()
+}
 
-	dirStyle = lipgloss.NewStyle().
-			Foreground(purple).
-			Bold(true)
+// RenderHelp renders the help screen
+func RenderHelp(commands []CommandInfo, width int) string {
+	return RenderWelcome(commands, width)
+}
 
-	labelStyle = lipgloss.NewStyle().
-			Foreground(pink).
-			Bold(true)
+func renderLogo(width int) string {
+	art := strings.Join([]string{
+		"▄▀▄ █▀▄ ▄▀▀ ▄▀▀ █ █",
+		"█▀█ █▀▄ █   ▀▀█ █ █",
+		"▀ ▀ ▀ ▀  ▀▀ ▀▀▀ ▀ ▀",
+	}, "\n")
+	subtitle := "Terminal Architecture Visualizer"
+
+	style := lipgloss.NewStyle().Foreground(cyan).Align(lipgloss.Center)
+	if width < len(subtitle)+8 {
+		return style.Render(art)
+	}
 
-	dimStyle = lipgloss.NewStyle().
-			Foreground(gray)
+	// The frame shrinks with the terminal, up to the classic 59 columns
+	inner := min(59, width-6)
+	return style.
+		Width(inner).
+		Border(lipgloss.DoubleBorder()).
+		BorderForeground(cyan).
+		Padding(1, 0).
+		Render(art + "\n\n" + subtitle)
+}
 
-	highlightStyle = lipgloss.NewStyle().
-			Foreground(white).
-			Background(purple).
-			Padding(0, 1)
-)
+func renderCommandBox(commands []CommandInfo, width int) string {
+	nameWidth, keyWidth, descWidth := 0, 0, 0
+	for _, cmd := range commands {
+		nameWidth = max(nameWidth, len(cmd.Name)+1)
+		keyWidth = max(keyWidth, len(cmd.Shortcut))
+		descWidth = max(descWidth, lipgloss.Width(cmd.Description))
+	}
+	// Shortcuts get their own column after the names
+	rowWidth := nameWidth
+	if keyWidth > 0 {
+		rowWidth += 2 + keyWidth
+	}
 
-// RenderWelcome renders the welcome screen
-func RenderWelcome() string {
-	logo := `
-    ╔═══════════════════════════════════════════════════════════╗
-    ║                                                           ║
-    ║     ▄▀▄ █▀▄ ▄▀▀ ▄▀▀ █ █                                   ║
-    ║     █▀█ █▀▄ █   ▀▀█ █ █                                   ║
-    ║     ▀ ▀ ▀ ▀  ▀▀ ▀▀▀ ▀ ▀                                   ║
-    ║                                                           ║
-    ║         Terminal Architecture Visualizer                  ║
-    ║                                                           ║
-    ╚═══════════════════════════════════════════════════════════╝
-`
-	logoStyled := lipgloss.NewStyle().Foreground(cyan).Render(logo)
-
-	commands := `
-    ┌─────────────────────────────────────────────────────────────┐
-    │  COMMANDS                                                   │
-    ├─────────────────────────────────────────────────────────────┤
-    │                                                             │
-    │   /tree      ─────────────  File structure                  │
-    │   /uml       ─────────────  Class diagrams                  │
-    │   /ascii     ─────────────  ASCII architecture art          │
-    │   /deps      ─────────────  Dependency graph                │
-    │   /changes   ─────────────  Recent modifications            │
-    │   /stats     ─────────────  Project statistics              │
-    │   /funcs     ─────────────  List all functions              │
-    │   /help      ─────────────  Show this help                  │
-    │                                                             │
-    └─────────────────────────────────────────────────────────────┘
-`
-	commandsStyled := lipgloss.NewStyle().Foreground(purple).Render(commands)
+	// Wide rows are "  /name  ───  description"; the leader shrinks
+	// before descriptions move to their own line
+	inner := min(width-6, 2+rowWidth+2+13+2+descWidth)
+	leader := min(13, inner-(2+rowWidth+2+2+descWidth))
+	stacked := leader < 3
+
+	nameStyle := lipgloss.NewStyle().Foreground(cyan).Bold(true)
+	descStyle := lipgloss.NewStyle().Foreground(purple)
+	keyStyle := lipgloss.NewStyle().Foreground(yellow)
+
+	var rows []string
+	rows = append(rows, labelStyle.Render("COMMANDS"))
+	rows = append(rows, dimStyle.Render(strings.Repeat("─", max(0, inner-2))))
+	for _, cmd := range commands {
+		name := nameStyle.Render("/" + cmd.Name)
+		if stacked {
+			if cmd.Shortcut != "" {
+				name += "  " + keyStyle.Render(cmd.Shortcut)
+			}
+			rows = append(rows, name)
+			rows = append(rows, descStyle.Width(max(10, inner-6)).Render(cmd.Description))
+			continue
+		}
+		name += strings.Repeat(" ", nameWidth-1-len(cmd.Name))
+		if keyWidth > 0 {
+			name += "  " + keyStyle.Render(fmt.Sprintf("%-*s", keyWidth, cmd.Shortcut))
+		}
+		rows = append(rows, name+dimStyle.Render("  "+strings.Repeat("─", leader)+"  ")+descStyle.Render(cmd.Description))
+	}
+	if stacked {
+		// Indent descriptions under their names
+		for i := 3; i < len(rows); i += 2 {
+			rows[i] = lipgloss.NewStyle().PaddingLeft(4).Render(rows[i])
+		}
+	}
 
-	tip := dimStyle.Render("\n    💡 Tip: Type a command and press Enter to explore your codebase\n")
+	return lipgloss.NewStyle().
+		Border(lipgloss.NormalBorder()).
+		BorderForeground(purple).
+		Padding(0, 1).
+		Render(strings.Join(rows, "\n"))
+}
 
-	return logoStyled + commandsStyled + tip
+// RenderTree renders a file tree, cut off below maxDepth levels (0
+// draws every level)
+func RenderTree(root *parser.FileNode, maxDepth int) string {
+	var sb strings.Builder
+	RenderTreeTo(&sb, root, maxDepth)
+	return sb.String()
 }
 
-// RenderHelp renders the help screen
-func RenderHelp() string {
-	return RenderWelcome()
+// RenderTreeTo writes the file tree to w
+func RenderTreeTo(w io.Writer, root *parser.FileNode, maxDepth int) {
+	header := headerStyle.Render("📁 FILE TREE")
+	io.WriteString(w, header)
+	io.WriteString(w, "\n\n")
+
+	renderTreeNode(w, root, "", true, 0, &treeView{fileColor: plainFileColor, maxDepth: maxDepth})
 }
 
-// RenderTree renders a file tree
-func RenderTree(root *parser.FileNode) string {
+// RenderTreeInteractive renders the file tree with the directories in
+// collapsed, keyed by path, drawn closed. It also returns the node drawn
+// on each line of the output, nil for lines that aren't part of the
+// tree, so a click can be mapped back to a node.
+func RenderTreeInteractive(root *parser.FileNode, collapsed map[string]bool) (string, []*parser.FileNode) {
 	var sb strings.Builder
 
 	header := headerStyle.Render("📁 FILE TREE")
 	sb.WriteString(header)
+	sb.WriteString("\n")
+	sb.WriteString(dimStyle.Render("  Click a directory to fold or unfold it, a file to list its functions"))
 	sb.WriteString("\n\n")
 
-	renderTreeNode(&sb, root, "", true)
+	view := &treeView{
+		fileColor: plainFileColor,
+		collapsed: collapsed,
+		lines:     make([]*parser.FileNode, strings.Count(sb.String(), "\n")),
+		track:     true,
+	}
+	renderTreeNode(&sb, root, "", true, 0, view)
+	return sb.String(), view.lines
+}
+
+// treeView holds how renderTreeNode draws a tree
+type treeView struct {
+	fileColor func(*parser.FileNode) lipgloss.Style
+	collapsed map[string]bool // Directory paths drawn closed
+	maxDepth  int             // Levels drawn below the root; 0 draws all
+
+	// With track set, lines gets the node of each line written
+	track bool
+	lines []*parser.FileNode
+}
+
+func plainFileColor(*parser.FileNode) lipgloss.Style {
+	return fileStyle
+}
+
+// DefaultHeatThresholds are the file ages separating the colors of the
+// tree heatmap, from hottest to coldest
+var DefaultHeatThresholds = []time.Duration{
+	time.Hour,
+	24 * time.Hour,
+	7 * 24 * time.Hour,
+	30 * 24 * time.Hour,
+}
+
+// heatPalette runs from hot to cold; files older than the last threshold
+// are dimmed
+func heatPalette() []lipgloss.TerminalColor {
+	return []lipgloss.TerminalColor{pink, orange, yellow, green, blue}
+}
 
+// RenderTreeHeat renders the file tree with each file colored by how
+// recently it was modified, cut off like RenderTree
+func RenderTreeHeat(root *parser.FileNode, thresholds []time.Duration, maxDepth int) string {
+	var sb strings.Builder
+	RenderTreeHeatTo(&sb, root, thresholds, maxDepth)
 	return sb.String()
 }
 
-func renderTreeNode(sb *strings.Builder, node *parser.FileNode, prefix string, isLast bool) {
+// RenderTreeHeatTo writes the file tree heatmap to w
+func RenderTreeHeatTo(w io.Writer, root *parser.FileNode, thresholds []time.Duration, maxDepth int) {
+	if len(thresholds) == 0 {
+		thresholds = DefaultHeatThresholds
+	}
+
+	header := headerStyle.Render("🔥 FILE TREE HEATMAP")
+	io.WriteString(w, header)
+	io.WriteString(w, "\n\n")
+
+	// Legend
+	io.WriteString(w, "  ")
+	for i, limit := range thresholds {
+		io.WriteString(w, heatStyle(i, len(thresholds)).Render("■ <"+formatAge(limit)))
+		io.WriteString(w, "  ")
+	}
+	io.WriteString(w, dimStyle.Render("■ older"))
+	io.WriteString(w, "\n\n")
+
+	now := time.Now()
+	renderTreeNode(w, root, "", true, 0, &treeView{maxDepth: maxDepth, fileColor: func(node *parser.FileNode) lipgloss.Style {
+		age := now.Sub(node.ModTime)
+		for i, limit := range thresholds {
+			if age < limit {
+				return heatStyle(i, len(thresholds))
+			}
+		}
+		return dimStyle
+	}})
+}
+
+// heatStyle returns the style for bucket i of n, spreading the palette
+// over however many thresholds are configured
+func heatStyle(i, n int) lipgloss.Style {
+	palette := heatPalette()
+	color := palette[i*len(palette)/n]
+	style := lipgloss.NewStyle().Foreground(color)
+	if i == 0 {
+		style = style.Bold(true)
+	}
+	return style
+}
+
+// formatAge renders a duration in the largest whole unit: 30m, 1h, 7d, 2w
+func formatAge(d time.Duration) string {
+	day := 24 * time.Hour
+	switch {
+	case d >= 7*day && d%(7*day) == 0:
+		return fmt.Sprintf("%dw", d/(7*day))
+	case d >= day && d%day == 0:
+		return fmt.Sprintf("%dd", d/day)
+	case d >= time.Hour && d%time.Hour == 0:
+		return fmt.Sprintf("%dh", d/time.Hour)
+	case d >= time.Minute && d%time.Minute == 0:
+		return fmt.Sprintf("%dm", d/time.Minute)
+	}
+	return d.String()
+}
+
+// renderTreeNode draws node, depth levels below the root, and what's
+// under it. A directory at the view's maximum depth is drawn truncated,
+// with the number of entries it hides.
+func renderTreeNode(w io.Writer, node *parser.FileNode, prefix string, isLast bool, depth int, view *treeView) {
 	if node == nil {
 		return
 	}
@@ -134,25 +304,41 @@ func renderTreeNode(sb *strings.Builder, node *parser.FileNode, prefix string, i
 	}
 
 	icon := getFileIcon(node.Name, node.IsDir)
+	closed := node.IsDir && view.collapsed[node.Path]
+	truncated := node.IsDir && view.maxDepth > 0 && depth >= view.maxDepth && len(node.Children) > 0
 
 	var name string
 	if node.IsDir {
 		name = dirStyle.Render(node.Name + "/")
+		if len(node.Languages) > 0 {
+			name += " " + dimStyle.Render("["+strings.Join(node.Languages, " ")+"]")
+		}
+		if closed {
+			icon = "📁"
+			name += " " + dimStyle.Render(fmt.Sprintf("+%d", len(node.Children)))
+		} else if truncated {
+			icon = "📁"
+			name += " " + dimStyle.Render(fmt.Sprintf("… %d hidden", len(node.Children)))
+		}
 	} else {
-		name = fileStyle.Render(node.Name)
+		name = view.fileColor(node).Render(node.Name)
+	}
+	if view.track {
+		view.lines = append(view.lines, node)
 	}
 
-	if prefix != "" || !node.IsDir {
-		sb.WriteString(dimStyle.Render(prefix + connector))
-		sb.WriteString(icon + " " + name)
-		sb.WriteString("\n")
+	// The root is drawn without a connector
+	if depth > 0 {
+		io.WriteString(w, dimStyle.Render(prefix+connector))
+		io.WriteString(w, icon+" "+name)
+		io.WriteString(w, "\n")
 	} else {
-		sb.WriteString(icon + " " + name)
-		sb.WriteString("\n")
+		io.WriteString(w, icon+" "+name)
+		io.WriteString(w, "\n")
 	}
 
 	newPrefix := prefix
-	if prefix != "" || !node.IsDir {
+	if depth > 0 {
 		if isLast {
 			newPrefix = prefix + "    "
 		} else {
@@ -160,9 +346,12 @@ func renderTreeNode(sb *strings.Builder, node *parser.FileNode, prefix string, i
 		}
 	}
 
+	if closed || truncated {
+		return
+	}
 	for i, child := range node.Children {
 		isLastChild := i == len(node.Children)-1
-		renderTreeNode(sb, child, newPrefix, isLastChild)
+		renderTreeNode(w, child, newPrefix, isLastChild, depth+1, view)
 	}
 }
 
@@ -202,58 +391,443 @@ func getFileIcon(name string, isDir bool) string {
 	}
 }
 
-// RenderUML renders UML class diagrams
-func RenderUML(classes []parser.ClassInfo) string {
+// RenderUML renders UML class diagrams, with interfaces drawn as their
+// own boxes after the classes
+func RenderUML(classes []parser.ClassInfo, ifaces []parser.InterfaceInfo) string {
 	var sb strings.Builder
+	RenderUMLTo(&sb, classes, ifaces)
+	return sb.String()
+}
 
+// RenderUMLTo writes the UML class diagram to w
+func RenderUMLTo(w io.Writer, classes []parser.ClassInfo, ifaces []parser.InterfaceInfo) {
 	header := headerStyle.Render("📐 UML CLASS DIAGRAM")
-	sb.WriteString(header)
-	sb.WriteString("\n\n")
+	io.WriteString(w, header)
+	io.WriteString(w, "\n\n")
 
-	if len(classes) == 0 {
-		sb.WriteString(dimStyle.Render("  No structs/classes found in this project.\n"))
-		return sb.String()
+	if len(classes) == 0 && len(ifaces) == 0 {
+		io.WriteString(w, dimStyle.Render("  No structs/classes found in this project.\n"))
+		return
 	}
 
 	for _, class := range classes {
-		sb.WriteString(renderClassBox(class))
-		sb.WriteString("\n")
+		io.WriteString(w, renderClassBox(class))
+		io.WriteString(w, "\n")
+	}
+	for _, iface := range ifaces {
+		io.WriteString(w, renderInterfaceBox(iface))
+		io.WriteString(w, "\n")
 	}
 
 	// Render relationships
-	if len(classes) > 1 {
-		sb.WriteString(labelStyle.Render("  RELATIONSHIPS"))
-		sb.WriteString("\n")
-		sb.WriteString(dimStyle.Render("  ─────────────"))
-		sb.WriteString("\n\n")
+	implements := false
+	for _, class := range classes {
+		implements = implements || len(class.Implements) > 0
+	}
 
+	if len(classes) > 1 || implements {
+		io.WriteString(w, labelStyle.Render("  RELATIONSHIPS"))
+		io.WriteString(w, "\n")
+		io.WriteString(w, dimStyle.Render("  ─────────────"))
+		io.WriteString(w, "\n\n")
+
+		known := classNames(classes)
 		for _, class := range classes {
+			name := declName(class.Name, class.TypeParams)
 			for _, field := range class.Fields {
-				for _, other := range classes {
-					if strings.Contains(field.Type, other.Name) && other.Name != class.Name {
-						arrow := fmt.Sprintf("    %s ──────▶ %s",
-							lipgloss.NewStyle().Foreground(blue).Render(class.Name),
-							lipgloss.NewStyle().Foreground(green).Render(other.Name))
-						relation := dimStyle.Render(fmt.Sprintf(" (has %s)", field.Name))
-						sb.WriteString(arrow + relation + "\n")
+				// Embedded structs are composition; embedded interfaces
+				// and types from outside the project aren't drawn
+				if other, ok := embeddedClass(field, known); ok && other != name {
+					fmt.Fprintf(w, "    %s %s %s\n",
+						lipgloss.NewStyle().Foreground(blue).Render(name),
+						dimStyle.Render("═══ embeds ═══▶"),
+						lipgloss.NewStyle().Foreground(green).Render(other))
+					continue
+				}
+				for _, other := range fieldClasses(field, known) {
+					if other == name {
+						continue
 					}
+					arrow := fmt.Sprintf("    %s ──────▶ %s",
+						lipgloss.NewStyle().Foreground(blue).Render(name),
+						lipgloss.NewStyle().Foreground(green).Render(other))
+					relation := dimStyle.Render(fmt.Sprintf(" (has %s)", field.Name))
+					io.WriteString(w, arrow+relation+"\n")
+				}
+			}
+		}
+
+		for _, class := range classes {
+			for _, iface := range class.Implements {
+				fmt.Fprintf(w, "    %s %s %s\n",
+					lipgloss.NewStyle().Foreground(blue).Render(declName(class.Name, class.TypeParams)),
+					dimStyle.Render("─·─ implements ─·─▶"),
+					lipgloss.NewStyle().Foreground(purple).Render(iface))
+			}
+		}
+	}
+}
+
+// RenderUMLMermaid renders classes as a Mermaid classDiagram block for
+// pasting into Markdown. Relationships come from field types, as in
+// RenderUML, plus interface implementations. Interfaces carry Mermaid's
+// <<interface>> annotation.
+func RenderUMLMermaid(classes []parser.ClassInfo, ifaces []parser.InterfaceInfo) string {
+	var sb strings.Builder
+
+	sb.WriteString("```mermaid\n")
+	sb.WriteString("classDiagram\n")
+
+	for _, class := range classes {
+		fmt.Fprintf(&sb, "    class %s {\n", mermaidID(declName(class.Name, class.TypeParams)))
+		for _, field := range class.Fields {
+			if field.Type == "(embedded)" {
+				continue // Project structs are drawn as composition below
+			}
+			// Fields from dynamic languages may have no type
+			fmt.Fprintf(&sb, "        %s%s\n", mermaidVisibility(field.Name), strings.TrimSpace(mermaidType(field.Type)+" "+field.Name))
+		}
+		for _, method := range class.Methods {
+			sb.WriteString(mermaidMethod(method))
+		}
+		sb.WriteString("    }\n")
+	}
+	for _, iface := range ifaces {
+		fmt.Fprintf(&sb, "    class %s {\n", mermaidID(declName(iface.Name, iface.TypeParams)))
+		sb.WriteString("        <<interface>>\n")
+		for _, method := range iface.Methods {
+			sb.WriteString(mermaidMethod(method))
+		}
+		sb.WriteString("    }\n")
+	}
+
+	known := classNames(classes)
+	for _, class := range classes {
+		name := declName(class.Name, class.TypeParams)
+		for _, field := range class.Fields {
+			// As in the terminal view, only embedded project structs are drawn
+			if other, ok := embeddedClass(field, known); ok && other != name {
+				fmt.Fprintf(&sb, "    %s *-- %s : embeds\n", mermaidID(name), mermaidID(other))
+				continue
+			}
+			for _, other := range fieldClasses(field, known) {
+				if other != name {
+					fmt.Fprintf(&sb, "    %s --> %s : %s\n", mermaidID(name), mermaidID(other), field.Name)
 				}
 			}
 		}
+		for _, iface := range class.Implements {
+			fmt.Fprintf(&sb, "    %s <|.. %s\n", mermaidID(iface), mermaidID(name))
+		}
+	}
+
+	sb.WriteString("```\n")
+	return sb.String()
+}
+
+// mermaidMethod renders one method line of a Mermaid class body
+func mermaidMethod(method parser.MethodInfo) string {
+	params := make([]string, len(method.Parameters))
+	for i, p := range method.Parameters {
+		params[i] = mermaidType(p)
+	}
+	line := fmt.Sprintf("        %s%s(%s)", mermaidVisibility(method.Name), method.Name, strings.Join(params, ", "))
+	if len(method.Returns) > 0 {
+		returns := make([]string, len(method.Returns))
+		for i, r := range method.Returns {
+			returns[i] = mermaidType(r)
+		}
+		line += " " + strings.Join(returns, ", ")
+	}
+	return line + "\n"
+}
+
+// SVG layout, in pixels. SVG can't measure text before it is drawn, so
+// boxes are sized from the character count at an approximate monospace
+// advance.
+const (
+	svgCharWidth  = 8
+	svgLineHeight = 18
+	svgPadding    = 10
+	svgGap        = 24
+	svgColumns    = 3 // Class boxes per row
+)
+
+// svgLine is one line of text in a class box, with its CSS class
+type svgLine struct {
+	text  string
+	class string
+}
+
+// RenderUMLSVG renders the class boxes as a standalone SVG document, with
+// the same name, fields and methods sections as the terminal diagram.
+// Interface boxes follow the classes, with dashed borders.
+func RenderUMLSVG(classes []parser.ClassInfo, ifaces []parser.InterfaceInfo) string {
+	type box struct {
+		x, y, w, h int
+		lines      []svgLine
+		iface      bool
+	}
+
+	contents := make([][]svgLine, 0, len(classes)+len(ifaces))
+	for _, class := range classes {
+		contents = append(contents, svgClassLines(class))
+	}
+	for _, iface := range ifaces {
+		contents = append(contents, svgInterfaceLines(iface))
+	}
+
+	var boxes []box
+	x, y, rowHeight, width := svgGap, svgGap, 0, svgGap
+	for i, lines := range contents {
+		if i > 0 && i%svgColumns == 0 {
+			x, y, rowHeight = svgGap, y+rowHeight+svgGap, 0
+		}
+
+		chars := 0
+		for _, line := range lines {
+			chars = max(chars, ansi.StringWidth(line.text))
+		}
+		b := box{
+			x:     x,
+			y:     y,
+			w:     chars*svgCharWidth + 2*svgPadding,
+			h:     len(lines)*svgLineHeight + 2*svgPadding,
+			lines: lines,
+			iface: i >= len(classes),
+		}
+		boxes = append(boxes, b)
+
+		x += b.w + svgGap
+		width = max(width, x)
+		rowHeight = max(rowHeight, b.h)
+	}
+	height := y + rowHeight + svgGap
+
+	if len(contents) == 0 {
+		width, height = 400, 60
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "<svg xmlns=\"http://www.w3.org/2000/svg\" xml:space=\"preserve\" width=\"%d\" height=\"%d\" viewBox=\"0 0 %[1]d %[2]d\">\n", width, height)
+	sb.WriteString("<style>\n")
+	sb.WriteString("  text { font-family: ui-monospace, Menlo, Consolas, monospace; font-size: 13px; fill: #24292f; white-space: pre; }\n")
+	sb.WriteString("  .name { font-weight: bold; fill: #0550ae; }\n")
+	sb.WriteString("  .label { font-weight: bold; }\n")
+	sb.WriteString("  .dim { fill: #6e7781; }\n")
+	sb.WriteString("</style>\n")
+	fmt.Fprintf(&sb, "<rect width=\"%d\" height=\"%d\" fill=\"#ffffff\"/>\n", width, height)
+
+	if len(contents) == 0 {
+		fmt.Fprintf(&sb, "<text x=\"%d\" y=\"%d\" class=\"dim\">No structs/classes found in this project.</text>\n", svgGap, svgGap+svgLineHeight)
+	}
+
+	for _, b := range boxes {
+		dash := ""
+		if b.iface {
+			dash = ` stroke-dasharray="6 4"`
+		}
+		fmt.Fprintf(&sb, "<g>\n  <rect x=\"%d\" y=\"%d\" width=\"%d\" height=\"%d\" rx=\"6\" fill=\"#f6f8fa\" stroke=\"#57606a\"%s/>\n", b.x, b.y, b.w, b.h, dash)
+
+		// A rule under the name, as in the terminal box
+		ruleY := b.y + svgPadding + svgLineHeight + svgLineHeight/4
+		fmt.Fprintf(&sb, "  <line x1=\"%d\" y1=\"%d\" x2=\"%d\" y2=\"%d\" stroke=\"#d0d7de\"/>\n", b.x, ruleY, b.x+b.w, ruleY)
+
+		for i, line := range b.lines {
+			if line.text == "" {
+				continue
+			}
+			// Text sits on its baseline, about three quarters down the line
+			textY := b.y + svgPadding + i*svgLineHeight + svgLineHeight*3/4
+			class := ""
+			if line.class != "" {
+				class = fmt.Sprintf(" class=%q", line.class)
+			}
+			fmt.Fprintf(&sb, "  <text x=\"%d\" y=\"%d\"%s>%s</text>\n", b.x+svgPadding, textY, class, html.EscapeString(line.text))
+		}
+		sb.WriteString("</g>\n")
 	}
 
+	sb.WriteString("</svg>\n")
 	return sb.String()
 }
 
+// svgClassLines lays out one class box's text: the name and package, a
+// blank line for the rule, then the fields and methods sections
+func svgClassLines(class parser.ClassInfo) []svgLine {
+	lines := []svgLine{
+		{declName(class.Name, class.TypeParams) + "  pkg: " + class.Package, "name"},
+		{"", ""},
+	}
+
+	if len(class.Fields) > 0 {
+		lines = append(lines, svgLine{"Fields:", "label"})
+		for _, field := range class.Fields {
+			text := "  " + field.Name + " " + field.Type
+			if field.Tag != "" {
+				text += " `" + field.Tag + "`"
+			}
+			lines = append(lines, svgLine{text, ""})
+		}
+	}
+
+	if len(class.Methods) > 0 {
+		if len(class.Fields) > 0 {
+			lines = append(lines, svgLine{"", ""})
+		}
+		lines = append(lines, svgLine{"Methods:", "label"})
+		for _, method := range class.Methods {
+			text := "  " + method.Name + "(" + strings.Join(method.Parameters, ", ") + ")"
+			if len(method.Returns) > 0 {
+				text += " → " + strings.Join(method.Returns, ", ")
+			}
+			lines = append(lines, svgLine{text, ""})
+		}
+	}
+
+	if len(class.Implements) > 0 {
+		lines = append(lines, svgLine{"", ""}, svgLine{"implements " + strings.Join(class.Implements, ", "), "dim"})
+	}
+
+	return lines
+}
+
+// svgInterfaceLines lays out one interface box's text: the stereotyped
+// name and package, a blank line for the rule, then the methods
+func svgInterfaceLines(iface parser.InterfaceInfo) []svgLine {
+	lines := []svgLine{
+		{"«interface» " + declName(iface.Name, iface.TypeParams) + "  pkg: " + iface.Package, "name"},
+		{"", ""},
+	}
+	for _, method := range iface.Methods {
+		text := "  " + method.Name + "(" + strings.Join(method.Parameters, ", ") + ")"
+		if len(method.Returns) > 0 {
+			text += " → " + strings.Join(method.Returns, ", ")
+		}
+		lines = append(lines, svgLine{text, ""})
+	}
+	return lines
+}
+
+// classNames maps the names classes are referred to by to the names
+// they're drawn with, which include any type parameters
+func classNames(classes []parser.ClassInfo) map[string]string {
+	known := make(map[string]string, len(classes))
+	for _, class := range classes {
+		known[class.Name] = declName(class.Name, class.TypeParams)
+	}
+	return known
+}
+
+// declName is a name as declared, with any Go type parameters, e.g.
+// "Map[K comparable, V any]". Parsers keep the two apart so lookups
+// match on the name alone.
+func declName(name, typeParams string) string {
+	return name + typeParams
+}
+
+// fieldClasses returns the known classes a field's type names exactly:
+// "[]*User", "map[string]User" and "store.User" all give User, while
+// "UserID" and "Users" don't. Each class is listed once, in the order
+// the type mentions it.
+func fieldClasses(field parser.FieldInfo, known map[string]string) []string {
+	if field.Type == "(embedded)" {
+		return nil
+	}
+
+	var found []string
+	words := strings.FieldsFunc(field.Type, func(r rune) bool {
+		return r != '_' && r != '.' && !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for _, word := range words {
+		// Qualified names match on the type name alone
+		if i := strings.LastIndexByte(word, '.'); i >= 0 {
+			word = word[i+1:]
+		}
+		if name, ok := known[word]; ok && !slices.Contains(found, name) {
+			found = append(found, name)
+		}
+	}
+	return found
+}
+
+// embeddedClass returns the known class an embedded field embeds, so
+// "*Base", "store.Base" and "Base[T]" all give Base
+func embeddedClass(field parser.FieldInfo, known map[string]string) (string, bool) {
+	if field.Type != "(embedded)" {
+		return "", false
+	}
+	name := strings.TrimPrefix(field.Name, "*")
+	if i := strings.LastIndexByte(name, '.'); i >= 0 {
+		name = name[i+1:]
+	}
+	name, _, _ = strings.Cut(name, "[")
+	class, ok := known[name]
+	return class, ok
+}
+
+// mermaidID turns a type name into a valid Mermaid class identifier:
+// type parameters use Mermaid's ~T~ generics, without their constraints,
+// and anything else that isn't a letter, digit or underscore becomes an
+// underscore
+func mermaidID(name string) string {
+	base, params, generic := strings.Cut(strings.TrimPrefix(name, "*"), "[")
+	id := strings.Map(func(r rune) rune {
+		if r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return r
+		}
+		return '_'
+	}, base)
+	if generic {
+		// "K comparable, V any" declares K and V
+		args := strings.Split(strings.TrimSuffix(params, "]"), ",")
+		for i, arg := range args {
+			if fields := strings.Fields(arg); len(fields) > 0 {
+				args[i] = fields[0]
+			}
+		}
+		id += "~" + mermaidID(strings.Join(args, ",")) + "~"
+	}
+	return id
+}
+
+// mermaidType rewrites Go type syntax that Mermaid would misread:
+// pointers drop their star, slices become T[], and maps, channels and
+// type parameters use ~ generics
+func mermaidType(t string) string {
+	t = strings.TrimPrefix(t, "*")
+	switch {
+	case strings.HasPrefix(t, "[]"):
+		return mermaidType(t[2:]) + "[]"
+	case strings.HasPrefix(t, "map["):
+		key, value, _ := strings.Cut(t[4:], "]")
+		return "map~" + mermaidType(key) + ", " + mermaidType(value) + "~"
+	case strings.HasPrefix(t, "chan "):
+		return "chan~" + mermaidType(t[5:]) + "~"
+	case t == "interface{}":
+		return "any"
+	case strings.Contains(t, "["):
+		base, params, _ := strings.Cut(t, "[")
+		return base + "~" + strings.TrimSuffix(params, "]") + "~"
+	}
+	return t
+}
+
+// mermaidVisibility marks exported Go names public and others private
+func mermaidVisibility(name string) string {
+	if r, _ := utf8.DecodeRuneInString(name); unicode.IsUpper(r) {
+		return "+"
+	}
+	return "-"
+}
+
 func renderClassBox(class parser.ClassInfo) string {
 	var lines []string
 
-	// Class name header
-	nameWidth := len(class.Name) + 4
-	minWidth := 30
-	if nameWidth < minWidth {
-		nameWidth = minWidth
-	}
+	// Class name header, with the rule sized by display width so
+	// non-ASCII names don't over- or undershoot it
+	title := declName(class.Name, class.TypeParams)
+	nameWidth := max(lipgloss.Width(title)+4, 30)
 
 	// Package info
 	pkgInfo := dimStyle.Render(fmt.Sprintf("pkg: %s", class.Package))
@@ -264,7 +838,7 @@ func renderClassBox(class parser.ClassInfo) string {
 		Foreground(white).
 		Background(blue).
 		Padding(0, 1).
-		Render(class.Name)
+		Render(title)
 
 	lines = append(lines, className+"  "+pkgInfo)
 	lines = append(lines, strings.Repeat("─", nameWidth))
@@ -276,6 +850,9 @@ func renderClassBox(class parser.ClassInfo) string {
 			fieldLine := fmt.Sprintf("  %s %s",
 				fieldStyle.Render(field.Name),
 				dimStyle.Render(field.Type))
+			if field.Tag != "" {
+				fieldLine += " " + dimStyle.Render("`"+field.Tag+"`")
+			}
 			lines = append(lines, fieldLine)
 		}
 	}
@@ -303,8 +880,36 @@ func renderClassBox(class parser.ClassInfo) string {
 	return classBoxStyle.Render(content)
 }
 
-// RenderASCIIArt renders ASCII art architecture view
-func RenderASCIIArt(structure parser.Structure) string {
+// renderInterfaceBox draws an interface like a class box, marked
+// «interface» and in the implements arrows' color, with its methods and
+// any embedded interfaces
+func renderInterfaceBox(iface parser.InterfaceInfo) string {
+	name := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(white).
+		Background(purple).
+		Padding(0, 1).
+		Render(declName(iface.Name, iface.TypeParams))
+	title := dimStyle.Render("«interface» ") + name + "  " + dimStyle.Render("pkg: "+iface.Package)
+
+	lines := []string{title, strings.Repeat("─", max(lipgloss.Width(title), 30))}
+	for _, embedded := range iface.Embedded {
+		lines = append(lines, dimStyle.Render("  ↳ "+embedded))
+	}
+	for _, method := range iface.Methods {
+		line := fmt.Sprintf("  %s(%s)", methodStyle.Render(method.Name), dimStyle.Render(strings.Join(method.Parameters, ", ")))
+		if len(method.Returns) > 0 {
+			line += dimStyle.Render(" → " + strings.Join(method.Returns, ", "))
+		}
+		lines = append(lines, line)
+	}
+
+	return classBoxStyle.BorderForeground(purple).Render(strings.Join(lines, "\n"))
+}
+
+// RenderASCIIArt renders ASCII art architecture view, sizing module
+// boxes to fit width
+func RenderASCIIArt(structure parser.Structure, width int) string {
 	var sb strings.Builder
 
 	if len(structure.Modules) == 0 {
@@ -375,19 +980,26 @@ func RenderASCIIArt(structure parser.Structure) string {
 `))
 	}
 
-	// Render modules in a grid-like pattern
-	sb.WriteString(lipgloss.NewStyle().Foreground(purple).Bold(true).Render("\n    ══════════════════════════════════════════════════════════════════\n"))
-	sb.WriteString(lipgloss.NewStyle().Foreground(purple).Bold(true).Render("                           📦 MODULES\n"))
-	sb.WriteString(lipgloss.NewStyle().Foreground(purple).Bold(true).Render("    ══════════════════════════════════════════════════════════════════\n\n"))
-
+	// Render modules in a grid-like pattern. Lines are styled without
+	// their newline: lipgloss pads every line of a block to the widest,
+	// which would indent whatever follows a trailing empty line.
+	boxWidth := moduleBoxWidth(width)
+	moduleStyle := lipgloss.NewStyle().Foreground(purple).Bold(true)
+	rule := "    " + strings.Repeat("═", boxWidth)
+	title := "📦 MODULES"
+	sb.WriteString("\n" + moduleStyle.Render(rule) + "\n")
+	sb.WriteString(moduleStyle.Render(strings.Repeat(" ", 4+(boxWidth-lipgloss.Width(title))/2)+title) + "\n")
+	sb.WriteString(moduleStyle.Render(rule) + "\n\n")
+
+	connector := strings.Repeat(" ", 4+boxWidth/2)
 	for i, mod := range structure.Modules {
-		box := renderCoolModuleBox(mod, i)
+		box := renderCoolModuleBox(mod, i, boxWidth)
 		sb.WriteString(box)
 
 		// Draw connections between modules
 		if i < len(structure.Modules)-1 {
-			sb.WriteString(lipgloss.NewStyle().Foreground(gray).Render("                         │\n"))
-			sb.WriteString(lipgloss.NewStyle().Foreground(gray).Render("                         ▼\n"))
+			sb.WriteString(lipgloss.NewStyle().Foreground(gray).Render(connector+"│") + "\n")
+			sb.WriteString(lipgloss.NewStyle().Foreground(gray).Render(connector+"▼") + "\n")
 		}
 	}
 
@@ -405,7 +1017,24 @@ func RenderASCIIArt(structure parser.Structure) string {
 	return sb.String()
 }
 
-func renderCoolModuleBox(mod parser.ModuleInfo, index int) string {
+// Module boxes in the ASCII view grow with the terminal up to
+// maxModuleBoxWidth columns and never shrink below minModuleBoxWidth
+const (
+	minModuleBoxWidth = 40
+	maxModuleBoxWidth = 100
+)
+
+// moduleBoxWidth is the width of a module box, borders included, for a
+// view width columns wide. The box is indented four columns and keeps
+// four free on the right.
+func moduleBoxWidth(width int) int {
+	if width <= 0 {
+		width = defaultWidth
+	}
+	return max(minModuleBoxWidth, min(width-8, maxModuleBoxWidth))
+}
+
+func renderCoolModuleBox(mod parser.ModuleInfo, index int, width int) string {
 	var sb strings.Builder
 
 	// Decorative elements based on index
@@ -413,89 +1042,94 @@ func renderCoolModuleBox(mod parser.ModuleInfo, index int) string {
 	deco := decorations[index%len(decorations)]
 
 	// Module header with style
-	width := 60
+	inner := width - 2
 	name := mod.Name
 	if name == "." || name == "" {
 		name = "root"
 	}
+	border := lipgloss.NewStyle().Foreground(blue)
+
+	// row pads content to the inside of the box by its display width, so
+	// wide symbols don't push the right border out
+	row := func(content string) string {
+		return "    ║" + content + strings.Repeat(" ", max(0, inner-lipgloss.Width(content))) + "║"
+	}
+	section := func(title string, style lipgloss.Style) {
+		sb.WriteString(border.Render("    ║"))
+		sb.WriteString(style.Bold(true).Render(title))
+		sb.WriteString(border.Render(strings.Repeat(" ", max(0, inner-lipgloss.Width(title)))+"║") + "\n")
+	}
+	item := func(text string, style lipgloss.Style) {
+		sb.WriteString(style.Render(row("    └── "+text)) + "\n")
+	}
+
+	// Item text keeps one column free before the right border
+	limit := inner - 9
 
 	// Top border
-	topBorder := "    ╔" + strings.Repeat("═", width-2) + "╗"
-	sb.WriteString(lipgloss.NewStyle().Foreground(blue).Render(topBorder + "\n"))
+	sb.WriteString(border.Render("    ╔"+strings.Repeat("═", inner)+"╗") + "\n")
 
 	// Module name with decoration
 	nameDisplay := fmt.Sprintf("%s %s %s", deco, strings.ToUpper(name), deco)
-	padding := (width - 2 - len(nameDisplay)) / 2
-	nameLine := "    ║" + strings.Repeat(" ", padding) + nameDisplay + strings.Repeat(" ", width-2-padding-len(nameDisplay)) + "║"
-	sb.WriteString(lipgloss.NewStyle().Foreground(cyan).Bold(true).Render(nameLine + "\n"))
+	padding := max(0, (inner-lipgloss.Width(nameDisplay))/2)
+	nameLine := row(strings.Repeat(" ", padding) + nameDisplay)
+	sb.WriteString(lipgloss.NewStyle().Foreground(cyan).Bold(true).Render(nameLine) + "\n")
+
+	// Size, centered under the name
+	files := "files"
+	if len(mod.Files) == 1 {
+		files = "file"
+	}
+	size := ansi.Truncate(fmt.Sprintf("%d lines · %d %s", mod.Lines, len(mod.Files), files), inner, "...")
+	padding = max(0, (inner-lipgloss.Width(size))/2)
+	sb.WriteString(dimStyle.Render(row(strings.Repeat(" ", padding)+size)) + "\n")
 
 	// Separator
-	sb.WriteString(lipgloss.NewStyle().Foreground(blue).Render("    ╠" + strings.Repeat("═", width-2) + "╣\n"))
+	sb.WriteString(border.Render("    ╠"+strings.Repeat("═", inner)+"╣") + "\n")
 
 	// Classes/Structs section
 	if len(mod.Structs) > 0 {
-		sb.WriteString(lipgloss.NewStyle().Foreground(blue).Render("    ║"))
-		sb.WriteString(lipgloss.NewStyle().Foreground(purple).Bold(true).Render("  ◆ Classes/Structs"))
-		sb.WriteString(lipgloss.NewStyle().Foreground(blue).Render(strings.Repeat(" ", width-22) + "║\n"))
+		section("  ◆ Classes/Structs", lipgloss.NewStyle().Foreground(purple))
 
 		for _, s := range mod.Structs {
-			if len(s) > width-12 {
-				s = s[:width-15] + "..."
-			}
-			line := fmt.Sprintf("    ║    └── %-*s║", width-11, s)
-			sb.WriteString(lipgloss.NewStyle().Foreground(purple).Render(line + "\n"))
+			item(ansi.Truncate(s, limit, "..."), lipgloss.NewStyle().Foreground(purple))
 		}
 	}
 
 	// Functions section
 	if len(mod.Funcs) > 0 {
-		sb.WriteString(lipgloss.NewStyle().Foreground(blue).Render("    ║"))
-		sb.WriteString(lipgloss.NewStyle().Foreground(green).Bold(true).Render("  ƒ Functions"))
-		sb.WriteString(lipgloss.NewStyle().Foreground(blue).Render(strings.Repeat(" ", width-16) + "║\n"))
+		section("  ƒ Functions", lipgloss.NewStyle().Foreground(green))
 
 		displayed := 0
 		for _, f := range mod.Funcs {
 			if displayed >= 5 {
-				remaining := len(mod.Funcs) - displayed
-				line := fmt.Sprintf("    ║    └── ... and %d more%-*s║", remaining, width-25-len(fmt.Sprintf("%d", remaining)), "")
-				sb.WriteString(lipgloss.NewStyle().Foreground(gray).Render(line + "\n"))
+				item(fmt.Sprintf("... and %d more", len(mod.Funcs)-displayed), lipgloss.NewStyle().Foreground(gray))
 				break
 			}
-			if len(f) > width-12 {
-				f = f[:width-15] + "..."
-			}
-			line := fmt.Sprintf("    ║    └── %-*s║", width-11, f)
-			sb.WriteString(lipgloss.NewStyle().Foreground(green).Render(line + "\n"))
+			item(ansi.Truncate(f, limit, "..."), lipgloss.NewStyle().Foreground(green))
 			displayed++
 		}
 	}
 
 	// Files section
 	if len(mod.Files) > 0 {
-		sb.WriteString(lipgloss.NewStyle().Foreground(blue).Render("    ║"))
-		sb.WriteString(lipgloss.NewStyle().Foreground(orange).Bold(true).Render("  ◈ Files"))
-		sb.WriteString(lipgloss.NewStyle().Foreground(blue).Render(strings.Repeat(" ", width-12) + "║\n"))
+		section("  ◈ Files", lipgloss.NewStyle().Foreground(orange))
 
 		displayed := 0
 		for _, file := range mod.Files {
 			if displayed >= 4 {
-				remaining := len(mod.Files) - displayed
-				line := fmt.Sprintf("    ║    └── ... and %d more%-*s║", remaining, width-25-len(fmt.Sprintf("%d", remaining)), "")
-				sb.WriteString(lipgloss.NewStyle().Foreground(gray).Render(line + "\n"))
+				item(fmt.Sprintf("... and %d more", len(mod.Files)-displayed), lipgloss.NewStyle().Foreground(gray))
 				break
 			}
+			// The icon and its space take three columns
 			icon := getFileIconSimple(file)
-			if len(file) > width-14 {
-				file = file[:width-17] + "..."
-			}
-			line := fmt.Sprintf("    ║    └── %s %-*s║", icon, width-14, file)
-			sb.WriteString(lipgloss.NewStyle().Foreground(orange).Render(line + "\n"))
+			item(icon+" "+ansi.Truncate(file, limit-3, "..."), lipgloss.NewStyle().Foreground(orange))
 			displayed++
 		}
 	}
 
 	// Bottom border
-	sb.WriteString(lipgloss.NewStyle().Foreground(blue).Render("    ╚" + strings.Repeat("═", width-2) + "╝\n"))
+	sb.WriteString(border.Render("    ╚"+strings.Repeat("═", inner)+"╝") + "\n")
 
 	return sb.String()
 }
@@ -564,14 +1198,19 @@ func renderModuleBox(mod parser.ModuleInfo) string {
 // RenderDeps renders dependency graph
 func RenderDeps(deps []parser.Dependency) string {
 	var sb strings.Builder
+	RenderDepsTo(&sb, deps)
+	return sb.String()
+}
 
+// RenderDepsTo writes the dependency graph to w
+func RenderDepsTo(w io.Writer, deps []parser.Dependency) {
 	header := headerStyle.Render("🔗 DEPENDENCY GRAPH")
-	sb.WriteString(header)
-	sb.WriteString("\n\n")
+	io.WriteString(w, header)
+	io.WriteString(w, "\n\n")
 
 	if len(deps) == 0 {
-		sb.WriteString(dimStyle.Render("  No dependencies found.\n"))
-		return sb.String()
+		io.WriteString(w, dimStyle.Render("  No dependencies found.\n"))
+		return
 	}
 
 	// Group by package
@@ -588,7 +1227,7 @@ func RenderDeps(deps []parser.Dependency) string {
 			Background(purple).
 			Padding(0, 1).
 			Render(pkg)
-		sb.WriteString("  " + pkgBox + "\n")
+		io.WriteString(w, "  "+pkgBox+"\n")
 
 		// Dedupe imports
 		seen := make(map[string]bool)
@@ -617,44 +1256,196 @@ func RenderDeps(deps []parser.Dependency) string {
 				impStyled = lipgloss.NewStyle().Foreground(cyan).Render(imp)
 			}
 
-			sb.WriteString(fmt.Sprintf("  %s %s\n", dimStyle.Render(connector), impStyled))
+			fmt.Fprintf(w, "  %s %s\n", dimStyle.Render(connector), impStyled)
 		}
-		sb.WriteString("\n")
+		io.WriteString(w, "\n")
 	}
 
 	// Legend
-	sb.WriteString("\n")
-	sb.WriteString(dimStyle.Render("  Legend: "))
-	sb.WriteString(lipgloss.NewStyle().Foreground(green).Render("internal"))
-	sb.WriteString(dimStyle.Render(" │ "))
-	sb.WriteString(lipgloss.NewStyle().Foreground(orange).Render("external"))
-	sb.WriteString(dimStyle.Render(" │ "))
-	sb.WriteString(lipgloss.NewStyle().Foreground(cyan).Render("stdlib"))
-	sb.WriteString("\n")
-
-	return sb.String()
+	io.WriteString(w, "\n")
+	io.WriteString(w, dimStyle.Render("  Legend: "))
+	io.WriteString(w, lipgloss.NewStyle().Foreground(green).Render("internal"))
+	io.WriteString(w, dimStyle.Render(" │ "))
+	io.WriteString(w, lipgloss.NewStyle().Foreground(orange).Render("external"))
+	io.WriteString(w, dimStyle.Render(" │ "))
+	io.WriteString(w, lipgloss.NewStyle().Foreground(cyan).Render("stdlib"))
+	io.WriteString(w, "\n")
 }
 
-// RenderChanges renders recent changes
-func RenderChanges(changes []parser.RecentChange) string {
+// RenderDependents renders what imports target, grouped by importing
+// package, for judging the blast radius of a change to it
+func RenderDependents(target string, dependents []parser.Dependency) string {
 	var sb strings.Builder
 
-	header := headerStyle.Render("🕐 RECENT CHANGES")
+	header := headerStyle.Render("🔗 DEPENDENTS OF " + target)
 	sb.WriteString(header)
 	sb.WriteString("\n\n")
 
-	if len(changes) == 0 {
-		sb.WriteString(dimStyle.Render("  No recent changes found.\n"))
+	if len(dependents) == 0 {
+		sb.WriteString(dimStyle.Render("  Nothing in the project imports " + target + "."))
+		sb.WriteString("\n\n")
+		sb.WriteString(dimStyle.Render("  Give the import path as /deps shows it, e.g. a full Go package path."))
+		sb.WriteString("\n")
 		return sb.String()
 	}
 
-	now := time.Now()
-
-	for _, change := range changes {
-		ago := now.Sub(change.ModTime)
-		agoStr := formatDuration(ago)
+	var packages []string
+	files := make(map[string][]string)
+	for _, dep := range dependents {
+		if _, ok := files[dep.Package]; !ok {
+			packages = append(packages, dep.Package)
+		}
+		files[dep.Package] = append(files[dep.Package], dep.From)
+	}
+	sort.Strings(packages)
 
-		// Time indicator
+	for _, pkg := range packages {
+		pkgBox := lipgloss.NewStyle().
+			Foreground(white).
+			Background(purple).
+			Padding(0, 1).
+			Render(pkg)
+		sb.WriteString("  " + pkgBox + "\n")
+
+		for i, file := range files[pkg] {
+			connector := "├──"
+			if i == len(files[pkg])-1 {
+				connector = "└──"
+			}
+			sb.WriteString("  " + dimStyle.Render(connector) + " " + fileStyle.Render(file) + "\n")
+		}
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString(dimStyle.Render(fmt.Sprintf("  %d file(s) in %d package(s) import %s", len(dependents), len(packages), target)))
+	sb.WriteString("\n")
+	return sb.String()
+}
+
+// RenderDepsDOT renders the dependency graph as a Graphviz digraph, one
+// node per package, ready for `dot -Tsvg`. Edges use the legend colors
+// of RenderDeps: internal green, external orange, stdlib cyan. Imports of
+// the project's own packages point at those packages' nodes.
+func RenderDepsDOT(deps []parser.Dependency) string {
+	var sb strings.Builder
+
+	local := make(map[string]bool)
+	for _, dep := range deps {
+		local[dep.Package] = true
+	}
+
+	type edge struct{ from, to string }
+	seen := make(map[edge]bool)
+	var edges []edge
+	kinds := make(map[string]string) // Node -> internal, external or stdlib
+	for _, dep := range deps {
+		to, kind := dep.To, "stdlib"
+		switch {
+		case strings.HasPrefix(to, "."):
+			kind = "internal"
+		case strings.Contains(to, "/") && local[path.Base(to)] && strings.Contains(strings.Split(to, "/")[0], "."):
+			to, kind = path.Base(to), "internal"
+		case strings.Contains(strings.Split(to, "/")[0], "."):
+			kind = "external"
+		}
+
+		e := edge{dep.Package, to}
+		if e.from == e.to || seen[e] {
+			continue
+		}
+		seen[e] = true
+		edges = append(edges, e)
+		kinds[dep.Package] = "internal"
+		if _, ok := kinds[to]; !ok {
+			kinds[to] = kind
+		}
+	}
+	sort.Slice(edges, func(i, j int) bool {
+		if edges[i].from != edges[j].from {
+			return edges[i].from < edges[j].from
+		}
+		return edges[i].to < edges[j].to
+	})
+
+	// The graph brings its own dark node fill, so it keeps the dark
+	// theme's colors whatever the terminal uses
+	colors := map[string]string{
+		"internal": "#10B981",
+		"external": "#F97316",
+		"stdlib":   "#4ECDC4",
+	}
+
+	sb.WriteString("digraph dependencies {\n")
+	sb.WriteString("  rankdir=LR;\n")
+	sb.WriteString("  node [shape=box, style=\"rounded,filled\", fillcolor=\"#1F2937\", fontcolor=\"#FFFFFF\", fontname=\"Helvetica\"];\n")
+	sb.WriteString("  edge [arrowsize=0.7];\n\n")
+
+	nodes := make([]string, 0, len(kinds))
+	for node := range kinds {
+		nodes = append(nodes, node)
+	}
+	sort.Strings(nodes)
+	for _, node := range nodes {
+		fmt.Fprintf(&sb, "  %s [color=%q];\n", strconv.Quote(node), colors[kinds[node]])
+	}
+	if len(edges) > 0 {
+		sb.WriteString("\n")
+	}
+	for _, e := range edges {
+		fmt.Fprintf(&sb, "  %s -> %s [color=%q];\n", strconv.Quote(e.from), strconv.Quote(e.to), colors[kinds[e.to]])
+	}
+	sb.WriteString("}\n")
+
+	return sb.String()
+}
+
+// RenderChanges renders recent changes: sparklines of how many files
+// changed per hour and per day, bucketed from all, then the list
+func RenderChanges(changes, all []parser.RecentChange) string {
+	var sb strings.Builder
+	RenderChangesTo(&sb, changes, all)
+	return sb.String()
+}
+
+// RenderChangesTo writes the recent changes list to w
+func RenderChangesTo(w io.Writer, changes, all []parser.RecentChange) {
+	header := headerStyle.Render("🕐 RECENT CHANGES")
+	io.WriteString(w, header)
+	io.WriteString(w, "\n\n")
+
+	if len(changes) == 0 {
+		io.WriteString(w, dimStyle.Render("  No recent changes found.\n"))
+		return
+	}
+
+	now := time.Now()
+
+	// Oldest bucket on the left, the current hour or day on the right
+	for _, period := range []struct {
+		label, span string
+		bucket      time.Duration
+		n           int
+	}{
+		{"hourly", "24h", time.Hour, 24},
+		{"daily", "14d", 24 * time.Hour, 14},
+	} {
+		counts := bucketChanges(all, now, period.bucket, period.n)
+		total := 0
+		for _, n := range counts {
+			total += n
+		}
+		fmt.Fprintf(w, "  %s  %s  %s\n",
+			labelStyle.Render(fmt.Sprintf("%-6s", period.label)),
+			sparkline(counts),
+			dimStyle.Render(fmt.Sprintf("%d file(s) in the last %s", total, period.span)))
+	}
+	io.WriteString(w, "\n")
+
+	for _, change := range changes {
+		ago := now.Sub(change.ModTime)
+		agoStr := formatDuration(ago)
+
+		// Time indicator
 		var timeStyle lipgloss.Style
 		if ago < time.Hour {
 			timeStyle = lipgloss.NewStyle().Foreground(green)
@@ -672,9 +1463,299 @@ func RenderChanges(changes []parser.RecentChange) string {
 		// Size
 		size := dimStyle.Render(fmt.Sprintf("(%s)", formatSize(change.Size)))
 
-		sb.WriteString(fmt.Sprintf("  %s  %s %s\n", timeBadge, filePath, size))
+		fmt.Fprintf(w, "  %s  %s %s\n", timeBadge, filePath, size)
+	}
+}
+
+// sparkTicks are the sparkline levels, lowest first
+var sparkTicks = []rune("▁▂▃▄▅▆▇█")
+
+// bucketChanges counts the changes in each of the n periods of length
+// bucket before now, oldest first. Changes stamped in the future count
+// toward the latest period.
+func bucketChanges(changes []parser.RecentChange, now time.Time, bucket time.Duration, n int) []int {
+	counts := make([]int, n)
+	for _, change := range changes {
+		i := int(max(now.Sub(change.ModTime), 0) / bucket)
+		if i < n {
+			counts[n-1-i]++
+		}
+	}
+	return counts
+}
+
+// sparkline draws counts as block characters scaled to the largest.
+// Empty buckets get the lowest block, dimmed; any change shows at least
+// one level above it.
+func sparkline(counts []int) string {
+	most := slices.Max(counts)
+	style := lipgloss.NewStyle().Foreground(green)
+
+	var sb strings.Builder
+	for _, n := range counts {
+		if n == 0 {
+			sb.WriteString(dimStyle.Render(string(sparkTicks[0])))
+			continue
+		}
+		level := max(1, (n*(len(sparkTicks)-1)+most-1)/most)
+		sb.WriteString(style.Render(string(sparkTicks[level])))
+	}
+	return sb.String()
+}
+
+// RenderDiffStat renders uncommitted changes like git diff --stat, with
+// staged and unstaged files listed separately
+func RenderDiffStat(stats []parser.DiffStat) string {
+	var sb strings.Builder
+
+	sb.WriteString(headerStyle.Render("📝 UNCOMMITTED CHANGES"))
+	sb.WriteString("\n\n")
+
+	if len(stats) == 0 {
+		sb.WriteString(dimStyle.Render("  Working tree clean - nothing to commit."))
+		sb.WriteString("\n")
+		return sb.String()
+	}
+
+	writeDiffStatFiles(&sb, stats, false, func(_ parser.DiffStat, path string) string {
+		return fileStyle.Render("    " + path)
+	})
+
+	added, deleted := 0, 0
+	files := make(map[string]bool)
+	for _, st := range stats {
+		added += st.Added
+		deleted += st.Deleted
+		files[st.Path] = true
+	}
+
+	sb.WriteString("\n")
+	sb.WriteString(fmt.Sprintf("  %d files changed, ", len(files)))
+	sb.WriteString(lipgloss.NewStyle().Foreground(green).Render(fmt.Sprintf("%d insertions(+)", added)))
+	sb.WriteString(", ")
+	sb.WriteString(lipgloss.NewStyle().Foreground(pink).Render(fmt.Sprintf("%d deletions(-)", deleted)))
+	sb.WriteString("\n")
+
+	return sb.String()
+}
+
+// writeDiffStatFiles lists stats as git diff --stat does, grouped into
+// staged and unstaged files. label renders a file's path, which unless
+// nameOnly is clamped and padded to a shared width and followed by the
+// file's line count and +/- bar.
+func writeDiffStatFiles(sb *strings.Builder, stats []parser.DiffStat, nameOnly bool, label func(st parser.DiffStat, path string) string) {
+	pathWidth, maxChanged := 0, 1
+	for _, st := range stats {
+		pathWidth = max(pathWidth, len(st.Path))
+		maxChanged = max(maxChanged, st.Added+st.Deleted)
+	}
+	pathWidth = min(pathWidth, 60)
+
+	// Bars are scaled so the largest change fills barWidth columns
+	const barWidth = 40
+	scale := func(n int) int {
+		if n == 0 {
+			return 0
+		}
+		return max(1, n*min(barWidth, maxChanged)/maxChanged)
+	}
+
+	for i, st := range stats {
+		if i == 0 || st.Staged != stats[i-1].Staged {
+			if i > 0 {
+				sb.WriteString("\n")
+			}
+			if st.Staged {
+				sb.WriteString(labelStyle.Render("  Staged:"))
+			} else {
+				sb.WriteString(labelStyle.Render("  Not staged:"))
+			}
+			sb.WriteString("\n")
+		}
+
+		if nameOnly {
+			sb.WriteString(label(st, st.Path))
+			sb.WriteString("\n")
+			continue
+		}
+
+		path := st.Path
+		if len(path) > pathWidth {
+			path = "..." + path[len(path)-pathWidth+3:]
+		}
+		sb.WriteString(label(st, fmt.Sprintf("%-*s", pathWidth, path)))
+		if st.Binary {
+			sb.WriteString(dimStyle.Render(" |   Bin"))
+		} else {
+			sb.WriteString(fmt.Sprintf(" | %5d ", st.Added+st.Deleted))
+			sb.WriteString(lipgloss.NewStyle().Foreground(green).Render(strings.Repeat("+", scale(st.Added))))
+			sb.WriteString(lipgloss.NewStyle().Foreground(pink).Render(strings.Repeat("-", scale(st.Deleted))))
+		}
+		sb.WriteString("\n")
+	}
+}
+
+// gitStatusStyle gives a git status the icon and color the live view
+// uses for the matching file event. Unknown statuses look modified.
+func gitStatusStyle(status string) (string, lipgloss.TerminalColor) {
+	switch status {
+	case parser.StatusAdded:
+		return "✚", green
+	case parser.StatusDeleted:
+		return "✖", red
+	}
+	return "✎", amber
+}
+
+// RenderGitStatus renders uncommitted changes with each file colored by
+// whether it was added, modified or deleted. Unless nameOnly is set, each
+// file also gets its git diff --stat line count and bar.
+func RenderGitStatus(stats []parser.DiffStat, nameOnly bool) string {
+	var sb strings.Builder
+
+	sb.WriteString(headerStyle.Render("📝 GIT DIFF"))
+	sb.WriteString("\n\n")
+
+	if len(stats) == 0 {
+		sb.WriteString(dimStyle.Render("  Working tree clean - nothing to commit."))
+		sb.WriteString("\n")
+		return sb.String()
+	}
+
+	writeDiffStatFiles(&sb, stats, nameOnly, func(st parser.DiffStat, path string) string {
+		icon, color := gitStatusStyle(st.Status)
+		style := lipgloss.NewStyle().Foreground(color)
+		return "    " + style.Render(icon+" ") + style.Render(path)
+	})
+
+	counts := make(map[string]int)
+	added, deleted := 0, 0
+	for _, st := range stats {
+		counts[st.Status]++
+		added += st.Added
+		deleted += st.Deleted
+	}
+
+	sb.WriteString("\n  ")
+	var parts []string
+	for _, status := range []string{parser.StatusAdded, parser.StatusModified, parser.StatusDeleted} {
+		if counts[status] > 0 {
+			_, color := gitStatusStyle(status)
+			parts = append(parts, lipgloss.NewStyle().Foreground(color).Render(fmt.Sprintf("%d %s", counts[status], status)))
+		}
+	}
+	sb.WriteString(strings.Join(parts, dimStyle.Render(" · ")))
+	if !nameOnly {
+		sb.WriteString(dimStyle.Render(" · "))
+		sb.WriteString(lipgloss.NewStyle().Foreground(green).Render(fmt.Sprintf("%d insertions(+)", added)))
+		sb.WriteString(", ")
+		sb.WriteString(lipgloss.NewStyle().Foreground(pink).Render(fmt.Sprintf("%d deletions(-)", deleted)))
+	}
+	sb.WriteString("\n")
+
+	return sb.String()
+}
+
+// RenderGitLog renders commits one per line with short SHA, author,
+// relative time and subject
+func RenderGitLog(commits []parser.Commit) string {
+	var sb strings.Builder
+
+	sb.WriteString(headerStyle.Render("📜 GIT LOG"))
+	sb.WriteString("\n\n")
+
+	if len(commits) == 0 {
+		sb.WriteString(dimStyle.Render("  No commits yet."))
+		sb.WriteString("\n")
+		return sb.String()
+	}
+
+	shaWidth, authorWidth := 0, 0
+	for _, c := range commits {
+		shaWidth = max(shaWidth, len(c.SHA))
+		authorWidth = max(authorWidth, utf8.RuneCountInString(c.Author))
+	}
+	authorWidth = min(authorWidth, 20)
+
+	shaStyle := lipgloss.NewStyle().Foreground(yellow)
+	authorStyle := lipgloss.NewStyle().Foreground(purple)
+	subjectStyle := lipgloss.NewStyle().Bold(true)
+
+	now := time.Now()
+	for _, c := range commits {
+		author := c.Author
+		if utf8.RuneCountInString(author) > authorWidth {
+			author = string([]rune(author)[:authorWidth-1]) + "…"
+		}
+		author += strings.Repeat(" ", authorWidth-utf8.RuneCountInString(author))
+
+		fmt.Fprintf(&sb, "  %s  %s  %s  %s\n",
+			shaStyle.Render(fmt.Sprintf("%-*s", shaWidth, c.SHA)),
+			authorStyle.Render(author),
+			dimStyle.Render(fmt.Sprintf("%-9s", formatDuration(now.Sub(c.Time)))),
+			subjectStyle.Render(c.Subject))
+	}
+
+	sb.WriteString("\n")
+	sb.WriteString(dimStyle.Render(fmt.Sprintf("  %d commits", len(commits))))
+	sb.WriteString("\n")
+
+	return sb.String()
+}
+
+// RenderAuthors renders commit counts per author as a bar chart, the
+// most active author's bar full. window describes the period counted,
+// e.g. "last 30d"; "" is the whole history.
+func RenderAuthors(authors []parser.AuthorActivity, window string) string {
+	var sb strings.Builder
+
+	title := "👥 GIT ACTIVITY"
+	if window != "" {
+		title += " (" + window + ")"
+	}
+	sb.WriteString(headerStyle.Render(title))
+	sb.WriteString("\n\n")
+
+	if len(authors) == 0 {
+		if window != "" {
+			sb.WriteString(dimStyle.Render("  No commits in this window (" + window + ")."))
+		} else {
+			sb.WriteString(dimStyle.Render("  No commits yet."))
+		}
+		sb.WriteString("\n")
+		return sb.String()
+	}
+
+	const barWidth = 30
+	nameWidth, total := 0, 0
+	for _, a := range authors {
+		nameWidth = max(nameWidth, utf8.RuneCountInString(a.Name))
+		total += a.Commits
 	}
+	nameWidth = min(nameWidth, 24)
+	most := authors[0].Commits
 
+	authorStyle := lipgloss.NewStyle().Foreground(purple)
+	barStyle := lipgloss.NewStyle().Foreground(cyan)
+
+	for _, a := range authors {
+		name := a.Name
+		if utf8.RuneCountInString(name) > nameWidth {
+			name = string([]rune(name)[:nameWidth-1]) + "…"
+		}
+		name += strings.Repeat(" ", nameWidth-utf8.RuneCountInString(name))
+
+		filled := max(1, a.Commits*barWidth/most)
+		fmt.Fprintf(&sb, "  %s  %s%s %s\n",
+			authorStyle.Render(name),
+			barStyle.Render(strings.Repeat("█", filled)),
+			dimStyle.Render(strings.Repeat("░", barWidth-filled)),
+			fmt.Sprintf("%4d", a.Commits)+dimStyle.Render(fmt.Sprintf("  %3.0f%%", float64(a.Commits)*100/float64(total))))
+	}
+
+	sb.WriteString("\n")
+	sb.WriteString(dimStyle.Render(fmt.Sprintf("  %d commits by %d author(s)", total, len(authors))))
+	sb.WriteString("\n")
 	return sb.String()
 }
 
@@ -728,11 +1809,23 @@ func RenderStats(stats parser.ProjectStats) string {
 	sb.WriteString(boxStyle.Render(statsContent))
 	sb.WriteString("\n\n")
 
+	if stats.FallbackFiles > 0 {
+		sb.WriteString(lipgloss.NewStyle().Foreground(orange).Render(
+			fmt.Sprintf("  ⚠ %d Go file(s) used the regex fallback (syntax newer than arcsii's parser)", stats.FallbackFiles)))
+		sb.WriteString("\n\n")
+	}
+
 	// Languages breakdown
 	if len(stats.Languages) > 0 {
 		sb.WriteString(labelStyle.Render("  Languages:"))
 		sb.WriteString("\n")
-		for ext, count := range stats.Languages {
+		// Most files first, so the list doesn't reshuffle between runs
+		exts := sortedKeys(stats.Languages)
+		sort.SliceStable(exts, func(i, j int) bool {
+			return stats.Languages[exts[i]] > stats.Languages[exts[j]]
+		})
+		for _, ext := range exts {
+			count := stats.Languages[ext]
 			bar := strings.Repeat("█", min(count, 30))
 			barStyled := lipgloss.NewStyle().Foreground(cyan).Render(bar)
 			sb.WriteString(fmt.Sprintf("    %-8s %s %d\n", ext, barStyled, count))
@@ -740,6 +1833,24 @@ func RenderStats(stats parser.ProjectStats) string {
 		sb.WriteString("\n")
 	}
 
+	// Lines per source language, scaled so the largest fills the bar
+	if len(stats.LanguageLines) > 0 {
+		sb.WriteString(labelStyle.Render("  Lines by language:"))
+		sb.WriteString("\n")
+		langs := sortedKeys(stats.LanguageLines)
+		sort.SliceStable(langs, func(i, j int) bool {
+			return stats.LanguageLines[langs[i]] > stats.LanguageLines[langs[j]]
+		})
+		maxLines := max(stats.LanguageLines[langs[0]], 1)
+		for _, lang := range langs {
+			lines := stats.LanguageLines[lang]
+			bar := strings.Repeat("█", max(1, lines*30/maxLines))
+			barStyled := lipgloss.NewStyle().Foreground(green).Render(bar)
+			sb.WriteString(fmt.Sprintf("    %-10s %s %d\n", lang, barStyled, lines))
+		}
+		sb.WriteString("\n")
+	}
+
 	// Largest files
 	if len(stats.LargestFiles) > 0 {
 		sb.WriteString(labelStyle.Render("  Largest Files:"))
@@ -751,6 +1862,19 @@ func RenderStats(stats parser.ProjectStats) string {
 		}
 	}
 
+	// Largest functions
+	if len(stats.LargestFuncs) > 0 {
+		sb.WriteString("\n")
+		sb.WriteString(labelStyle.Render("  Largest Functions:"))
+		sb.WriteString("\n")
+		for _, fn := range stats.LargestFuncs {
+			sb.WriteString(fmt.Sprintf("    %s %s %s\n",
+				dimStyle.Render(fmt.Sprintf("%5d lines", fn.Lines)),
+				methodStyle.Render(fn.Name),
+				dimStyle.Render(fmt.Sprintf("%s:%d", fn.File, fn.Line))))
+		}
+	}
+
 	return sb.String()
 }
 
@@ -761,17 +1885,25 @@ func min(a, b int) int {
 	return b
 }
 
+// maxDocWidth caps the doc comment line shown under a function
+const maxDocWidth = 100
+
 // RenderFunctions renders a list of all functions
 func RenderFunctions(funcs []parser.FunctionInfo) string {
 	var sb strings.Builder
+	RenderFunctionsTo(&sb, funcs)
+	return sb.String()
+}
 
+// RenderFunctionsTo writes the function list to w
+func RenderFunctionsTo(w io.Writer, funcs []parser.FunctionInfo) {
 	header := headerStyle.Render("⚡ FUNCTIONS")
-	sb.WriteString(header)
-	sb.WriteString("\n\n")
+	io.WriteString(w, header)
+	io.WriteString(w, "\n\n")
 
 	if len(funcs) == 0 {
-		sb.WriteString(dimStyle.Render("  No functions found.\n"))
-		return sb.String()
+		io.WriteString(w, dimStyle.Render("  No functions found.\n"))
+		return
 	}
 
 	// Group by package
@@ -787,7 +1919,7 @@ func RenderFunctions(funcs []parser.FunctionInfo) string {
 			Background(blue).
 			Padding(0, 1).
 			Render(pkg)
-		sb.WriteString("  " + pkgBox + "\n\n")
+		io.WriteString(w, "  "+pkgBox+"\n\n")
 
 		for _, fn := range fns {
 			params := strings.Join(fn.Parameters, ", ")
@@ -795,7 +1927,7 @@ func RenderFunctions(funcs []parser.FunctionInfo) string {
 
 			// Function signature
 			sig := fmt.Sprintf("    %s(%s)",
-				methodStyle.Render(fn.Name),
+				methodStyle.Render(declName(fn.Name, fn.TypeParams)),
 				dimStyle.Render(params))
 
 			if returns != "" {
@@ -805,7 +1937,1354 @@ func RenderFunctions(funcs []parser.FunctionInfo) string {
 			// Location
 			loc := dimStyle.Render(fmt.Sprintf(" :%d", fn.Line))
 
-			sb.WriteString(sig + loc + "\n")
+			io.WriteString(w, sig+loc+"\n")
+
+			// First line of the doc comment, if any
+			if doc, _, _ := strings.Cut(fn.Doc, "\n"); doc != "" {
+				io.WriteString(w, dimStyle.Italic(true).Render("      "+ansi.Truncate(doc, maxDocWidth, "…"))+"\n")
+			}
+		}
+		io.WriteString(w, "\n")
+	}
+}
+
+// RenderInterfaces renders an interface catalog: each interface by
+// package with its doc, embedded interfaces and method signatures
+func RenderInterfaces(ifaces []parser.InterfaceInfo) string {
+	var sb strings.Builder
+
+	header := headerStyle.Render("🔌 INTERFACES")
+	sb.WriteString(header)
+	sb.WriteString("\n\n")
+
+	if len(ifaces) == 0 {
+		sb.WriteString(dimStyle.Render("  No Go interfaces found.\n"))
+		return sb.String()
+	}
+
+	methods := 0
+	var dir string
+	for i, iface := range ifaces {
+		if d := filepath.Dir(iface.File); i == 0 || d != dir {
+			dir = d
+			if i > 0 {
+				sb.WriteString("\n")
+			}
+			pkgBox := lipgloss.NewStyle().
+				Foreground(white).
+				Background(blue).
+				Padding(0, 1).
+				Render(iface.Package)
+			sb.WriteString("  " + pkgBox + "\n\n")
+		}
+
+		sb.WriteString("    " + labelStyle.Render(declName(iface.Name, iface.TypeParams)))
+		sb.WriteString(dimStyle.Render(fmt.Sprintf("  %s:%d", filepath.Base(iface.File), iface.Line)))
+		sb.WriteString("\n")
+		if doc, _, _ := strings.Cut(iface.Doc, "\n"); doc != "" {
+			sb.WriteString(dimStyle.Italic(true).Render("      "+ansi.Truncate(doc, maxDocWidth, "…")) + "\n")
+		}
+
+		for _, embedded := range iface.Embedded {
+			sb.WriteString(dimStyle.Render("      ↳ ") + fieldStyle.Render(embedded) + "\n")
+		}
+		for _, method := range iface.Methods {
+			sig := "      " + methodStyle.Render(method.Name) + dimStyle.Render("("+strings.Join(method.Parameters, ", ")+")")
+			switch len(method.Returns) {
+			case 0:
+			case 1:
+				sig += " " + fieldStyle.Render(method.Returns[0])
+			default:
+				sig += " " + fieldStyle.Render("("+strings.Join(method.Returns, ", ")+")")
+			}
+			sb.WriteString(sig + "\n")
+		}
+		if len(iface.Methods) == 0 && len(iface.Embedded) == 0 {
+			sb.WriteString(dimStyle.Render("      (no methods)") + "\n")
+		}
+		methods += len(iface.Methods)
+	}
+
+	sb.WriteString("\n")
+	sb.WriteString(dimStyle.Render(fmt.Sprintf("  %d interface(s), %d method(s)", len(ifaces), methods)))
+	sb.WriteString("\n")
+	return sb.String()
+}
+
+// RenderSizeof renders struct memory layouts with per-field offsets
+func RenderSizeof(layouts []parser.StructLayout, arch string) string {
+	var sb strings.Builder
+
+	header := headerStyle.Render(fmt.Sprintf("📏 STRUCT LAYOUT (%s)", arch))
+	sb.WriteString(header)
+	sb.WriteString("\n\n")
+
+	if len(layouts) == 0 {
+		sb.WriteString(dimStyle.Render("  No Go structs found in this project.\n"))
+		return sb.String()
+	}
+
+	var totalWaste int64
+	unresolved := 0
+	for _, layout := range layouts {
+		totalWaste += layout.Size - layout.OptimalSize
+
+		name := lipgloss.NewStyle().
+			Bold(true).
+			Foreground(white).
+			Background(blue).
+			Padding(0, 1).
+			Render(layout.Name)
+		sb.WriteString("  " + name + "  ")
+
+		if len(layout.Unresolved) > 0 {
+			unresolved++
+			sb.WriteString(dimStyle.Render(fmt.Sprintf("pkg: %s  size: unresolved", layout.Package)))
+			sb.WriteString("\n")
+			for _, field := range layout.Unresolved {
+				sb.WriteString("    " + dimStyle.Render("?") + " " + fieldStyle.Render(field) + "\n")
+			}
+			sb.WriteString(lipgloss.NewStyle().Foreground(pink).Render(
+				"    ⚠ Types not resolved, e.g. from a dependency that isn't downloaded") + "\n\n")
+			continue
+		}
+
+		sb.WriteString(dimStyle.Render(fmt.Sprintf("pkg: %s  size: %d  align: %d", layout.Package, layout.Size, layout.Align)))
+		sb.WriteString("\n")
+
+		for _, field := range layout.Fields {
+			sb.WriteString(fmt.Sprintf("    %s %s %s\n",
+				dimStyle.Render(fmt.Sprintf("@%-4d %3dB", field.Offset, field.Size)),
+				fieldStyle.Render(field.Name),
+				dimStyle.Render(field.Type)))
+			if field.Padding > 0 {
+				sb.WriteString(lipgloss.NewStyle().Foreground(pink).Render(
+					fmt.Sprintf("    ⚠ %d bytes padding", field.Padding)) + "\n")
+			}
+		}
+
+		if len(layout.Suggested) > 0 {
+			sb.WriteString(lipgloss.NewStyle().Foreground(orange).Render(
+				fmt.Sprintf("    💡 Reorder to save %d bytes (%d → %d): %s",
+					layout.Size-layout.OptimalSize, layout.Size, layout.OptimalSize,
+					strings.Join(layout.Suggested, ", "))) + "\n")
+		}
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString(labelStyle.Render(fmt.Sprintf("  Structs: %d", len(layouts))))
+	sb.WriteString(dimStyle.Render(fmt.Sprintf("  │  Reclaimable: %d bytes", totalWaste)))
+	if unresolved > 0 {
+		sb.WriteString(dimStyle.Render(fmt.Sprintf("  │  Unresolved: %d", unresolved)))
+	}
+	sb.WriteString("\n")
+
+	return sb.String()
+}
+
+// RenderCalls renders the callers and callees of a single function
+func RenderCalls(name string, callers, callees []parser.CallEdge) string {
+	var sb strings.Builder
+
+	header := headerStyle.Render("📞 CALLS: " + name)
+	sb.WriteString(header)
+	sb.WriteString("\n\n")
+
+	if len(callers) == 0 && len(callees) == 0 {
+		sb.WriteString(dimStyle.Render(fmt.Sprintf("  No calls found for %s.\n", name)))
+		return sb.String()
+	}
+
+	renderCallEdges(&sb, "⬅ CALLERS", callers, true)
+	renderCallEdges(&sb, "➡ CALLEES", callees, false)
+
+	return sb.String()
+}
+
+// RenderCallers renders the call sites of the function name, by caller
+func RenderCallers(name string, edges []parser.CallEdge) string {
+	return renderCallList("⬅ CALLERS OF "+name, name, "callers", edges, true)
+}
+
+// RenderCallees renders the calls made by the function name
+func RenderCallees(name string, edges []parser.CallEdge) string {
+	return renderCallList("➡ CALLEES OF "+name, name, "callees", edges, false)
+}
+
+func renderCallList(title, name, noun string, edges []parser.CallEdge, showCaller bool) string {
+	var sb strings.Builder
+
+	header := headerStyle.Render(title)
+	sb.WriteString(header)
+	sb.WriteString("\n\n")
+
+	if len(edges) == 0 {
+		sb.WriteString(dimStyle.Render(fmt.Sprintf("  No %s found for %s.", noun, name)))
+		sb.WriteString("\n")
+		return sb.String()
+	}
+
+	// Group call sites under the function at the other end
+	var order []string
+	sites := make(map[string][]parser.CallEdge)
+	for _, edge := range edges {
+		fn := edge.Callee
+		if showCaller {
+			fn = edge.Caller
+		}
+		if _, ok := sites[fn]; !ok {
+			order = append(order, fn)
+		}
+		sites[fn] = append(sites[fn], edge)
+	}
+
+	color := green
+	if showCaller {
+		color = blue
+	}
+	sb.WriteString(labelStyle.Render(fmt.Sprintf("  %s", name)))
+	sb.WriteString(dimStyle.Render(fmt.Sprintf("  %d %s, %d call sites", len(order), noun, len(edges))))
+	sb.WriteString("\n")
+	for i, fn := range order {
+		connector, indent := "├──", "│   "
+		if i == len(order)-1 {
+			connector, indent = "└──", "    "
+		}
+		sb.WriteString(fmt.Sprintf("  %s %s\n", dimStyle.Render(connector), lipgloss.NewStyle().Foreground(color).Render(fn)))
+
+		for j, edge := range sites[fn] {
+			siteConnector := "├──"
+			if j == len(sites[fn])-1 {
+				siteConnector = "└──"
+			}
+			sb.WriteString(dimStyle.Render(fmt.Sprintf("  %s%s %s:%d", indent, siteConnector, edge.File, edge.Line)))
+			sb.WriteString("\n")
+		}
+	}
+
+	return sb.String()
+}
+
+func renderCallEdges(sb *strings.Builder, title string, edges []parser.CallEdge, showCaller bool) {
+	sb.WriteString(labelStyle.Render(fmt.Sprintf("  %s (%d)", title, len(edges))))
+	sb.WriteString("\n")
+	if len(edges) == 0 {
+		sb.WriteString(dimStyle.Render("  └── none"))
+		sb.WriteString("\n")
+	}
+
+	for i, edge := range edges {
+		connector := "├──"
+		if i == len(edges)-1 {
+			connector = "└──"
+		}
+
+		name := lipgloss.NewStyle().Foreground(green).Render(edge.Callee)
+		if showCaller {
+			name = lipgloss.NewStyle().Foreground(blue).Render(edge.Caller)
+		}
+
+		sb.WriteString(fmt.Sprintf("  %s %s %s\n",
+			dimStyle.Render(connector),
+			name,
+			dimStyle.Render(fmt.Sprintf("%s:%d", edge.File, edge.Line))))
+	}
+	sb.WriteString("\n")
+}
+
+// RenderSequenceMermaid renders the calls made from the function named
+// root as a Mermaid sequence diagram, with packages as participants. Calls
+// are followed depth levels deep; calls outside the project's packages
+// are left out. The output is plain text, ready to paste into Markdown.
+func RenderSequenceMermaid(edges []parser.CallEdge, root string, depth int) string {
+	var sb strings.Builder
+
+	// Resolve the query to one qualified function
+	var entries []string
+	seen := make(map[string]bool)
+	projectPkgs := make(map[string]bool)
+	for _, edge := range parser.CalleesOf(edges, root) {
+		if !seen[edge.Caller] {
+			seen[edge.Caller] = true
+			entries = append(entries, edge.Caller)
+		}
+	}
+	for _, edge := range edges {
+		projectPkgs[callPackage(edge.Caller)] = true
+	}
+	if len(entries) == 0 {
+		sb.WriteString(fmt.Sprintf("No calls found from %s.\n", root))
+		return sb.String()
+	}
+	sort.Strings(entries)
+	entry := entries[0]
+
+	var participants []string
+	declared := make(map[string]bool)
+	declare := func(pkg string) {
+		if !declared[pkg] {
+			declared[pkg] = true
+			participants = append(participants, pkg)
+		}
+	}
+
+	var body strings.Builder
+	var walk func(fn string, stack []string)
+	walk = func(fn string, stack []string) {
+		from := callPackage(fn)
+		called := make(map[string]bool)
+		for _, edge := range parser.CalleesOf(edges, fn) {
+			if edge.Caller != fn || called[edge.Callee] || !projectPkgs[callPackage(edge.Callee)] {
+				continue
+			}
+			called[edge.Callee] = true
+
+			to := callPackage(edge.Callee)
+			declare(to)
+			fmt.Fprintf(&body, "    %s->>%s: %s\n", from, to, strings.TrimPrefix(edge.Callee, to+"."))
+
+			switch {
+			case slices.Contains(stack, edge.Callee):
+				fmt.Fprintf(&body, "    Note over %s: recursive call, not expanded\n", to)
+			case len(stack) >= depth:
+				for _, next := range parser.CalleesOf(edges, edge.Callee) {
+					if next.Caller == edge.Callee && projectPkgs[callPackage(next.Callee)] {
+						fmt.Fprintf(&body, "    Note over %s: depth limit reached\n", to)
+						break
+					}
+				}
+			default:
+				walk(edge.Callee, append(stack, edge.Callee))
+			}
+		}
+	}
+	declare(callPackage(entry))
+	walk(entry, []string{entry})
+
+	sb.WriteString("```mermaid\n")
+	sb.WriteString("sequenceDiagram\n")
+	fmt.Fprintf(&sb, "    %%%% %s, depth %d\n", entry, depth)
+	if len(entries) > 1 {
+		fmt.Fprintf(&sb, "    %%%% %q also matches: %s\n", root, strings.Join(entries[1:], ", "))
+	}
+	for _, pkg := range participants {
+		fmt.Fprintf(&sb, "    participant %s\n", pkg)
+	}
+	if body.Len() == 0 {
+		fmt.Fprintf(&sb, "    Note over %s: no calls into project packages\n", callPackage(entry))
+	}
+	sb.WriteString(body.String())
+	sb.WriteString("```\n")
+
+	return sb.String()
+}
+
+// callPackage returns the package of a "pkg.Func" or "pkg.Type.Method" name
+func callPackage(qualified string) string {
+	pkg, _, _ := strings.Cut(qualified, ".")
+	return pkg
+}
+
+// RenderRoutes renders HTTP routes grouped by method
+func RenderRoutes(routes []parser.Route) string {
+	var sb strings.Builder
+
+	header := headerStyle.Render("🌐 HTTP ROUTES")
+	sb.WriteString(header)
+	sb.WriteString("\n\n")
+
+	if len(routes) == 0 {
+		sb.WriteString(dimStyle.Render("  No HTTP routes found.\n"))
+		return sb.String()
+	}
+
+	// Group by method, common verbs first
+	byMethod := make(map[string][]parser.Route)
+	for _, route := range routes {
+		byMethod[route.Method] = append(byMethod[route.Method], route)
+	}
+
+	order := []string{"GET", "POST", "PUT", "PATCH", "DELETE", "HEAD", "OPTIONS"}
+	var extra []string
+	for method := range byMethod {
+		known := false
+		for _, o := range order {
+			if o == method {
+				known = true
+				break
+			}
+		}
+		if !known {
+			extra = append(extra, method)
+		}
+	}
+	sort.Strings(extra)
+	order = append(order, extra...)
+
+	methodColors := map[string]lipgloss.TerminalColor{
+		"GET":    green,
+		"POST":   blue,
+		"PUT":    orange,
+		"PATCH":  yellow,
+		"DELETE": pink,
+	}
+
+	for _, method := range order {
+		group := byMethod[method]
+		if len(group) == 0 {
+			continue
+		}
+
+		color, ok := methodColors[method]
+		if !ok {
+			color = purple
+		}
+		badge := lipgloss.NewStyle().
+			Bold(true).
+			Foreground(white).
+			Background(color).
+			Padding(0, 1).
+			Render(method)
+		sb.WriteString(fmt.Sprintf("  %s %s\n", badge, dimStyle.Render(fmt.Sprintf("(%d)", len(group)))))
+
+		sort.SliceStable(group, func(i, j int) bool {
+			return group[i].Path < group[j].Path
+		})
+
+		for i, route := range group {
+			connector := "├──"
+			if i == len(group)-1 {
+				connector = "└──"
+			}
+
+			handler := route.Handler
+			if handler == "" {
+				handler = "(inline)"
+			}
+
+			sb.WriteString(fmt.Sprintf("  %s %s %s %s\n",
+				dimStyle.Render(connector),
+				fileStyle.Render(route.Path),
+				methodStyle.Render("→ "+handler),
+				dimStyle.Render(fmt.Sprintf("%s:%d", route.File, route.Line))))
+		}
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString(labelStyle.Render(fmt.Sprintf("  Total routes: %d", len(routes))))
+	sb.WriteString("\n")
+
+	return sb.String()
+}
+
+// RenderLayers renders packages as stacked architecture tiers and flags
+// dependencies that point up the stack
+func RenderLayers(view parser.LayerView) string {
+	var sb strings.Builder
+
+	header := headerStyle.Render("🏛  LAYERED ARCHITECTURE")
+	sb.WriteString(header)
+	sb.WriteString("\n\n")
+
+	assigned := 0
+	for _, layer := range view.Layers {
+		assigned += len(layer.Packages)
+	}
+	if assigned == 0 {
+		sb.WriteString(dimStyle.Render("  No packages matched any layer rule."))
+		sb.WriteString("\n")
+		sb.WriteString(dimStyle.Render(fmt.Sprintf("  Define layers in %s, e.g. \"services: service*, usecase*\"", parser.LayerRulesFile)))
+		sb.WriteString("\n")
+		return sb.String()
+	}
+
+	width := 60
+	for i, layer := range view.Layers {
+		top := "    ┌─ " + strings.ToUpper(layer.Name) + " " + strings.Repeat("─", max(0, width-len(layer.Name)-2)) + "┐"
+		sb.WriteString(lipgloss.NewStyle().Foreground(blue).Bold(true).Render(top))
+		sb.WriteString("\n")
+
+		if len(layer.Packages) == 0 {
+			sb.WriteString(dimStyle.Render(fmt.Sprintf("    │  %-*s│", width-1, "(empty)")))
+			sb.WriteString("\n")
+		}
+		for _, pkg := range layer.Packages {
+			if len(pkg) > width-5 {
+				pkg = "..." + pkg[len(pkg)-(width-8):]
+			}
+			sb.WriteString(lipgloss.NewStyle().Foreground(blue).Render("    │  "))
+			sb.WriteString(fileStyle.Render(fmt.Sprintf("◈ %-*s", width-3, pkg)))
+			sb.WriteString(lipgloss.NewStyle().Foreground(blue).Render("│"))
+			sb.WriteString("\n")
+		}
+
+		sb.WriteString(lipgloss.NewStyle().Foreground(blue).Render("    └" + strings.Repeat("─", width+1) + "┘"))
+		sb.WriteString("\n")
+
+		if i < len(view.Layers)-1 {
+			sb.WriteString(dimStyle.Render("                    │"))
+			sb.WriteString("\n")
+			sb.WriteString(dimStyle.Render("                    ▼"))
+			sb.WriteString("\n")
+		}
+	}
+	sb.WriteString("\n")
+
+	// Inter-layer dependencies
+	if len(view.Edges) > 0 {
+		sb.WriteString(labelStyle.Render("  LAYER DEPENDENCIES"))
+		sb.WriteString("\n")
+		sb.WriteString(dimStyle.Render("  ──────────────────"))
+		sb.WriteString("\n\n")
+
+		violations := 0
+		for _, edge := range view.Edges {
+			arrow := fmt.Sprintf("    %s ──────▶ %s", edge.From, edge.To)
+			count := dimStyle.Render(fmt.Sprintf(" (%d imports)", edge.Count))
+			if !edge.Upward {
+				sb.WriteString(lipgloss.NewStyle().Foreground(green).Render(arrow) + count + "\n")
+				continue
+			}
+
+			violations++
+			sb.WriteString(lipgloss.NewStyle().Foreground(pink).Bold(true).Render("  ⚠ "+strings.TrimSpace(arrow)) + count + "\n")
+			for _, v := range edge.Violations {
+				sb.WriteString(lipgloss.NewStyle().Foreground(pink).Render("      └── " + v))
+				sb.WriteString("\n")
+			}
+		}
+		sb.WriteString("\n")
+
+		if violations == 0 {
+			sb.WriteString(lipgloss.NewStyle().Foreground(green).Render("  ✓ All dependencies point down the stack"))
+		} else {
+			sb.WriteString(lipgloss.NewStyle().Foreground(pink).Render(fmt.Sprintf("  ✗ %d upward layer dependency(s) violate the layering", violations)))
+		}
+		sb.WriteString("\n\n")
+	}
+
+	if len(view.Unassigned) > 0 {
+		sb.WriteString(dimStyle.Render(fmt.Sprintf("  Unassigned: %s", strings.Join(view.Unassigned, ", "))))
+		sb.WriteString("\n")
+	}
+
+	return sb.String()
+}
+
+// RenderSmells renders god objects flagged by parser.DetectGodObjects
+func RenderSmells(objects []parser.GodObject) string {
+	var sb strings.Builder
+
+	header := headerStyle.Render("👃 CODE SMELLS")
+	sb.WriteString(header)
+	sb.WriteString("\n\n")
+
+	if len(objects) == 0 {
+		sb.WriteString(lipgloss.NewStyle().Foreground(green).Render("  ✓ No god objects - sizes are in line with the project average"))
+		sb.WriteString("\n")
+		return sb.String()
+	}
+
+	for _, kind := range []string{"struct", "package"} {
+		var group []parser.GodObject
+		for _, obj := range objects {
+			if obj.Kind == kind {
+				group = append(group, obj)
+			}
+		}
+		if len(group) == 0 {
+			continue
+		}
+
+		title := "  GOD STRUCTS"
+		if kind == "package" {
+			title = "  OVERSIZED PACKAGES"
+		}
+		sb.WriteString(labelStyle.Render(title))
+		sb.WriteString("\n")
+		sb.WriteString(dimStyle.Render("  " + strings.Repeat("─", len(title)-2)))
+		sb.WriteString("\n\n")
+
+		for _, obj := range group {
+			name := obj.Name
+			if kind == "struct" && obj.Package != "" {
+				name = obj.Package + "." + obj.Name
+			}
+			sb.WriteString(lipgloss.NewStyle().Foreground(pink).Bold(true).Render("  ⚠ " + name))
+			sb.WriteString(dimStyle.Render("  " + filepath.Base(obj.File)))
+			sb.WriteString("\n")
+
+			for i, m := range obj.Metrics {
+				connector := "├──"
+				if i == len(obj.Metrics)-1 {
+					connector = "└──"
+				}
+				sb.WriteString(dimStyle.Render("      " + connector + " "))
+				sb.WriteString(fieldStyle.Render(fmt.Sprintf("%-8s %3d", m.Name, m.Value)))
+				sb.WriteString(dimStyle.Render(fmt.Sprintf("  threshold %.1f  ", m.Threshold)))
+				sb.WriteString(lipgloss.NewStyle().Foreground(orange).Render(fmt.Sprintf("+%.1f (%.0f%% over)", m.Excess(), m.Excess()/m.Threshold*100)))
+				sb.WriteString("\n")
+			}
+		}
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString(dimStyle.Render(fmt.Sprintf("  Threshold: project mean + %.0f·stddev", parser.GodObjectSigma)))
+	sb.WriteString("\n")
+
+	return sb.String()
+}
+
+// RenderImportIssues renders Go files whose imports break the grouping
+// convention, with the blocks as written next to the expected ones
+func RenderImportIssues(issues []parser.ImportIssue) string {
+	var sb strings.Builder
+
+	header := headerStyle.Render("📦 IMPORT GROUPING")
+	sb.WriteString(header)
+	sb.WriteString("\n\n")
+
+	if len(issues) == 0 {
+		sb.WriteString(lipgloss.NewStyle().Foreground(green).Render("  ✓ All Go imports are grouped std → external → internal"))
+		sb.WriteString("\n")
+		return sb.String()
+	}
+
+	for _, issue := range issues {
+		sb.WriteString(lipgloss.NewStyle().Foreground(pink).Bold(true).Render("  ⚠ " + issue.File))
+		sb.WriteString("\n")
+		for _, problem := range issue.Problems {
+			sb.WriteString(dimStyle.Render("    • " + problem))
+			sb.WriteString("\n")
+		}
+		sb.WriteString("\n")
+
+		actual := importBlockLines(issue.Actual)
+		expected := importBlockLines(issue.Expected)
+
+		width := len("actual")
+		for _, line := range actual {
+			width = max(width, len(line))
+		}
+
+		sb.WriteString(labelStyle.Render(fmt.Sprintf("      %-*s", width+4, "actual")))
+		sb.WriteString(lipgloss.NewStyle().Foreground(green).Bold(true).Render("expected"))
+		sb.WriteString("\n")
+		for i := 0; i < max(len(actual), len(expected)); i++ {
+			var left, right string
+			if i < len(actual) {
+				left = actual[i]
+			}
+			if i < len(expected) {
+				right = expected[i]
+			}
+			if right == "" {
+				sb.WriteString(fieldStyle.Render("      " + left))
+			} else {
+				sb.WriteString(fieldStyle.Render(fmt.Sprintf("      %-*s", width+4, left)))
+				sb.WriteString(methodStyle.Render(right))
+			}
+			sb.WriteString("\n")
+		}
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString(dimStyle.Render(fmt.Sprintf("  %d file(s) need regrouping (goimports -local <module> fixes most)", len(issues))))
+	sb.WriteString("\n")
+
+	return sb.String()
+}
+
+// importBlockLines flattens import blocks into lines with a blank line
+// between blocks, as they appear in source
+func importBlockLines(blocks [][]string) []string {
+	var lines []string
+	for i, block := range blocks {
+		if i > 0 {
+			lines = append(lines, "")
+		}
+		lines = append(lines, block...)
+	}
+	return lines
+}
+
+// RenderOrphans lists Go files that nothing else seems to use, grouped
+// by directory
+func RenderOrphans(orphans []string) string {
+	var sb strings.Builder
+
+	header := headerStyle.Render("🪦 ORPHAN FILES")
+	sb.WriteString(header)
+	sb.WriteString("\n")
+	sb.WriteString(dimStyle.Render("  Heuristic: matched by import paths and identifier names, not types"))
+	sb.WriteString("\n\n")
+
+	if len(orphans) == 0 {
+		sb.WriteString(lipgloss.NewStyle().Foreground(green).Render("  ✓ Every Go file is used by another file"))
+		sb.WriteString("\n")
+		return sb.String()
+	}
+
+	warn := lipgloss.NewStyle().Foreground(orange)
+	var dir string
+	for i, file := range orphans {
+		if d := path.Dir(file); i == 0 || d != dir {
+			dir = d
+			sb.WriteString(labelStyle.Render("  " + dir + "/"))
+			sb.WriteString("\n")
+		}
+		sb.WriteString(warn.Render("    ⚠ " + path.Base(file)))
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString("\n")
+	sb.WriteString(dimStyle.Render("  Check for reflection, go:linkname or build-tag uses before deleting."))
+	sb.WriteString("\n")
+	return sb.String()
+}
+
+// RenderUnusedModules renders go.mod requirements no source file imports
+func RenderUnusedModules(unused []string, mod *parser.GoModule) string {
+	var sb strings.Builder
+
+	header := headerStyle.Render("🧹 UNUSED MODULES")
+	sb.WriteString(header)
+	sb.WriteString("\n\n")
+
+	if len(unused) == 0 {
+		sb.WriteString(lipgloss.NewStyle().Foreground(green).Render("  ✓ Every required module is imported"))
+		sb.WriteString("\n")
+		return sb.String()
+	}
+
+	requires := make(map[string]parser.ModuleRequire)
+	if mod != nil {
+		for _, req := range mod.Requires {
+			requires[req.Path] = req
+		}
+	}
+
+	var direct, indirect []string
+	for _, path := range unused {
+		if requires[path].Indirect {
+			indirect = append(indirect, path)
+		} else {
+			direct = append(direct, path)
+		}
+	}
+
+	groups := []struct {
+		title string
+		paths []string
+		style lipgloss.Style
+	}{
+		{"  DIRECT", direct, lipgloss.NewStyle().Foreground(pink)},
+		{"  INDIRECT", indirect, dimStyle},
+	}
+
+	for _, group := range groups {
+		if len(group.paths) == 0 {
+			continue
+		}
+		sb.WriteString(labelStyle.Render(fmt.Sprintf("%s (%d)", group.title, len(group.paths))))
+		sb.WriteString("\n")
+		for i, path := range group.paths {
+			connector := "├──"
+			if i == len(group.paths)-1 {
+				connector = "└──"
+			}
+			sb.WriteString(dimStyle.Render("    " + connector + " "))
+			sb.WriteString(group.style.Render(path))
+			sb.WriteString(dimStyle.Render(" " + requires[path].Version))
+			sb.WriteString("\n")
+		}
+		sb.WriteString("\n")
+	}
+
+	if len(indirect) > 0 {
+		sb.WriteString(dimStyle.Render("  Indirect modules may still be needed by other dependencies."))
+		sb.WriteString("\n")
+	}
+	sb.WriteString(dimStyle.Render("  Run `go mod tidy` to drop requirements that are truly unused."))
+	sb.WriteString("\n")
+
+	return sb.String()
+}
+
+// RenderWorkspaceStats renders a per-module breakdown for a workspace
+func RenderWorkspaceStats(file string, members []string, stats []parser.ProjectStats) string {
+	var sb strings.Builder
+
+	sb.WriteString("\n\n")
+	sb.WriteString(labelStyle.Render(fmt.Sprintf("  🧩 Workspace Modules (%s):", file)))
+	sb.WriteString("\n")
+
+	nameWidth := len("module")
+	maxLines := 1
+	for i, member := range members {
+		nameWidth = max(nameWidth, len(member))
+		maxLines = max(maxLines, stats[i].TotalLines)
+	}
+
+	sb.WriteString(dimStyle.Render(fmt.Sprintf("    %-*s %7s %8s %7s", nameWidth, "module", "files", "lines", "funcs")))
+	sb.WriteString("\n")
+	for i, member := range members {
+		st := stats[i]
+		bar := strings.Repeat("█", max(1, st.TotalLines*20/maxLines))
+		sb.WriteString(fileStyle.Render(fmt.Sprintf("    %-*s", nameWidth, member)))
+		sb.WriteString(fmt.Sprintf(" %7d %8d %7d  ", st.TotalFiles, st.TotalLines, st.TotalFuncs))
+		sb.WriteString(lipgloss.NewStyle().Foreground(cyan).Render(bar))
+		sb.WriteString("\n")
+	}
+
+	return sb.String()
+}
+
+// RenderJSON renders any parser result as indented JSON for scripts and
+// CI. Struct fields keep their declaration order and map keys are sorted,
+// so output is stable between runs.
+func RenderJSON(v any) string {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Sprintf(`{"error": %q}`, err.Error()) + "\n"
+	}
+	return string(data) + "\n"
+}
+
+// RenderStatsPrometheus renders project stats in the Prometheus text
+// exposition format, for scraping or pushing to a gateway from CI
+func RenderStatsPrometheus(stats parser.ProjectStats) string {
+	var sb strings.Builder
+
+	gauge := func(name, help string) {
+		sb.WriteString(fmt.Sprintf("# HELP %s %s\n", name, help))
+		sb.WriteString(fmt.Sprintf("# TYPE %s gauge\n", name))
+	}
+
+	gauge("arcsii_files", "Number of files in the project.")
+	sb.WriteString(fmt.Sprintf("arcsii_files %d\n", stats.TotalFiles))
+
+	gauge("arcsii_packages", "Number of Go packages.")
+	sb.WriteString(fmt.Sprintf("arcsii_packages %d\n", stats.TotalPackages))
+
+	gauge("arcsii_functions", "Number of Go functions and methods.")
+	sb.WriteString(fmt.Sprintf("arcsii_functions %d\n", stats.TotalFuncs))
+
+	gauge("arcsii_structs", "Number of Go structs.")
+	sb.WriteString(fmt.Sprintf("arcsii_structs %d\n", stats.TotalStructs))
+
+	gauge("arcsii_todos", "Number of TODO markers in source files.")
+	sb.WriteString(fmt.Sprintf("arcsii_todos %d\n", stats.TodoCount))
+
+	gauge("arcsii_lines", "Lines of source code by language.")
+	for _, lang := range sortedKeys(stats.LanguageLines) {
+		sb.WriteString(fmt.Sprintf("arcsii_lines{language=\"%s\"} %d\n", promLabel(lang), stats.LanguageLines[lang]))
+	}
+
+	gauge("arcsii_extension_files", "Number of files by extension.")
+	for _, ext := range sortedKeys(stats.Languages) {
+		sb.WriteString(fmt.Sprintf("arcsii_extension_files{extension=\"%s\"} %d\n", promLabel(ext), stats.Languages[ext]))
+	}
+
+	return sb.String()
+}
+
+// promLabel escapes a Prometheus label value
+func promLabel(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+}
+
+func sortedKeys(m map[string]int) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// RenderImportCycles renders file-to-file import cycles
+func RenderImportCycles(cycles []parser.ImportCycle) string {
+	var sb strings.Builder
+
+	header := headerStyle.Render("🔁 CIRCULAR FILE IMPORTS")
+	sb.WriteString(header)
+	sb.WriteString("\n\n")
+
+	if len(cycles) == 0 {
+		sb.WriteString(lipgloss.NewStyle().Foreground(green).Render("  ✓ No circular imports between JS/TS files"))
+		sb.WriteString("\n")
+		return sb.String()
+	}
+
+	for i, cycle := range cycles {
+		title := fmt.Sprintf("  ⚠ Cycle %d", i+1)
+		if cycle.Members > len(cycle.Files) {
+			title += fmt.Sprintf(" (%d files tangled)", cycle.Members)
+		}
+		sb.WriteString(lipgloss.NewStyle().Foreground(pink).Bold(true).Render(title))
+		sb.WriteString("\n")
+
+		for j, file := range cycle.Files {
+			connector := "├──▶"
+			if j == 0 {
+				connector = "┌──▶"
+			}
+			sb.WriteString(dimStyle.Render("    " + connector + " "))
+			sb.WriteString(fileStyle.Render(file))
+			sb.WriteString("\n")
+		}
+		sb.WriteString(dimStyle.Render("    └─── back to "))
+		sb.WriteString(fileStyle.Render(cycle.Files[0]))
+		sb.WriteString("\n\n")
+	}
+
+	sb.WriteString(dimStyle.Render(fmt.Sprintf("  %d cycle(s) - modules in a cycle may see each other half-initialized at load time", len(cycles))))
+	sb.WriteString("\n")
+
+	return sb.String()
+}
+
+// RenderBloat renders a file's functions by size, each with a bar for
+// maxComplexityRows caps the complexity list; the rest are summarized
+const maxComplexityRows = 30
+
+// RenderComplexity renders functions by cyclomatic complexity, most
+// complex first: green up to 5, yellow up to 10, red above
+func RenderComplexity(funcs []parser.FunctionComplexity) string {
+	var sb strings.Builder
+
+	header := headerStyle.Render("🌀 CYCLOMATIC COMPLEXITY")
+	sb.WriteString(header)
+	sb.WriteString("\n\n")
+
+	if len(funcs) == 0 {
+		sb.WriteString(dimStyle.Render("  No Go functions found."))
+		sb.WriteString("\n")
+		return sb.String()
+	}
+
+	sorted := make([]parser.FunctionComplexity, len(funcs))
+	copy(sorted, funcs)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Score > sorted[j].Score
+	})
+
+	shown := sorted[:min(len(sorted), maxComplexityRows)]
+	nameWidth := 0
+	for _, fn := range shown {
+		nameWidth = max(nameWidth, len(fn.Package)+1+len(fn.Name))
+	}
+	nameWidth = min(nameWidth, 50)
+
+	for _, fn := range shown {
+		color := green
+		switch {
+		case fn.Score > 10:
+			color = pink
+		case fn.Score > 5:
+			color = yellow
+		}
+
+		name := fn.Package + "." + fn.Name
+		if len(name) > nameWidth {
+			name = name[:nameWidth-1] + "…"
+		}
+
+		sb.WriteString(lipgloss.NewStyle().Foreground(color).Bold(true).Render(fmt.Sprintf("  %4d ", fn.Score)))
+		sb.WriteString(methodStyle.Render(fmt.Sprintf(" %-*s", nameWidth, name)))
+		sb.WriteString(dimStyle.Render(fmt.Sprintf("  %s:%d", fn.File, fn.Line)))
+		sb.WriteString("\n")
+	}
+
+	if hidden := len(sorted) - len(shown); hidden > 0 {
+		sb.WriteString("\n")
+		sb.WriteString(dimStyle.Render(fmt.Sprintf("  … %d more functions with lower scores not shown", hidden)))
+		sb.WriteString("\n")
+	}
+
+	return sb.String()
+}
+
+// the share of the file it occupies
+func RenderBloat(file string, funcs []parser.FunctionInfo, totalLines int) string {
+	var sb strings.Builder
+
+	header := headerStyle.Render("🎈 FUNCTION SIZES")
+	sb.WriteString(header)
+	sb.WriteString("\n\n")
+
+	sb.WriteString(fileStyle.Render("  " + file))
+	sb.WriteString(dimStyle.Render(fmt.Sprintf("  %d lines, %d functions", totalLines, len(funcs))))
+	sb.WriteString("\n\n")
+
+	if len(funcs) == 0 {
+		sb.WriteString(dimStyle.Render("  No functions found."))
+		sb.WriteString("\n")
+		return sb.String()
+	}
+
+	nameWidth := 0
+	for _, fn := range funcs {
+		nameWidth = max(nameWidth, len(fn.Name))
+	}
+	nameWidth = min(nameWidth, 40)
+
+	const barWidth = 30
+	inFuncs := 0
+	for _, fn := range funcs {
+		lines := fn.Lines()
+		inFuncs += lines
+
+		share := 0.0
+		if totalLines > 0 {
+			share = float64(lines) / float64(totalLines)
+		}
+		filled := int(share*barWidth + 0.5)
+		if lines > 0 && filled == 0 {
+			filled = 1
+		}
+
+		color := green
+		switch {
+		case share >= 0.25:
+			color = pink
+		case share >= 0.10:
+			color = orange
+		case share >= 0.05:
+			color = yellow
+		}
+
+		name := fn.Name
+		if len(name) > nameWidth {
+			name = name[:nameWidth-1] + "…"
+		}
+
+		sb.WriteString(methodStyle.Render(fmt.Sprintf("  %-*s ", nameWidth, name)))
+		sb.WriteString(lipgloss.NewStyle().Foreground(color).Render(strings.Repeat("█", filled)))
+		sb.WriteString(dimStyle.Render(strings.Repeat("░", max(0, barWidth-filled))))
+		sb.WriteString(fmt.Sprintf(" %4d lines %5.1f%%", lines, share*100))
+		sb.WriteString(dimStyle.Render(fmt.Sprintf("  :%d", fn.Line)))
+		sb.WriteString("\n")
+	}
+
+	if totalLines > 0 {
+		sb.WriteString("\n")
+		sb.WriteString(dimStyle.Render(fmt.Sprintf("  Functions cover %d%% of the file", min(inFuncs, totalLines)*100/totalLines)))
+		sb.WriteString("\n")
+	}
+
+	return sb.String()
+}
+
+// RenderMetrics renders a table of per-package metrics in the order given,
+// with a total row at the bottom
+func RenderMetrics(metrics []parser.PackageMetric) string {
+	var sb strings.Builder
+
+	header := headerStyle.Render("📦 PACKAGE METRICS")
+	sb.WriteString(header)
+	sb.WriteString("\n\n")
+
+	if len(metrics) == 0 {
+		sb.WriteString(dimStyle.Render("  No Go packages found."))
+		sb.WriteString("\n")
+		return sb.String()
+	}
+
+	total := parser.PackageMetric{Dir: "total"}
+	nameWidth := len("package")
+	for _, m := range metrics {
+		nameWidth = max(nameWidth, len(m.Dir))
+		total.Files += m.Files
+		total.Funcs += m.Funcs
+		total.Structs += m.Structs
+		total.Lines += m.Lines
+		total.FuncLines += m.FuncLines
+	}
+	nameWidth = min(nameWidth, 50)
+
+	row := func(m parser.PackageMetric) string {
+		return fmt.Sprintf(" %6d %6d %7d %8d %8.1f", m.Files, m.Funcs, m.Structs, m.Lines, m.AvgFuncLines())
+	}
+
+	sb.WriteString(dimStyle.Render(fmt.Sprintf("  %-*s %6s %6s %7s %8s %8s", nameWidth, "package", "files", "funcs", "structs", "lines", "avg fn")))
+	sb.WriteString("\n")
+	for _, m := range metrics {
+		name := m.Dir
+		if len(name) > nameWidth {
+			name = name[:nameWidth-1] + "…"
+		}
+		sb.WriteString(fileStyle.Render(fmt.Sprintf("  %-*s", nameWidth, name)))
+		sb.WriteString(row(m))
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString(dimStyle.Render("  " + strings.Repeat("─", nameWidth+40)))
+	sb.WriteString("\n")
+	sb.WriteString(labelStyle.Render(fmt.Sprintf("  %-*s", nameWidth, total.Dir)))
+	sb.WriteString(lipgloss.NewStyle().Bold(true).Render(row(total)))
+	sb.WriteString("\n")
+
+	return sb.String()
+}
+
+// RenderLOC renders code, comment and blank lines per language as a
+// table, with each language's comment share of its non-blank lines
+func RenderLOC(report parser.LOCReport) string {
+	var sb strings.Builder
+
+	header := headerStyle.Render("🧮 LINES OF CODE")
+	sb.WriteString(header)
+	sb.WriteString("\n\n")
+
+	if len(report.Languages) == 0 {
+		sb.WriteString(dimStyle.Render("  No source files in a recognized language."))
+		sb.WriteString("\n")
+		return sb.String()
+	}
+
+	nameWidth := len("language")
+	for _, l := range report.Languages {
+		nameWidth = max(nameWidth, len(l.Language))
+	}
+
+	row := func(l parser.LanguageLOC) string {
+		share := 0.0
+		if l.Code+l.Comments > 0 {
+			share = float64(l.Comments) * 100 / float64(l.Code+l.Comments)
+		}
+		return fmt.Sprintf(" %6d %8d %8d %7d %8.0f%%", l.Files, l.Code, l.Comments, l.Blanks, share)
+	}
+
+	sb.WriteString(dimStyle.Render(fmt.Sprintf("  %-*s %6s %8s %8s %7s %9s", nameWidth, "language", "files", "code", "comment", "blank", "comment%")))
+	sb.WriteString("\n")
+	for _, l := range report.Languages {
+		sb.WriteString(fileStyle.Render(fmt.Sprintf("  %-*s", nameWidth, l.Language)))
+		sb.WriteString(row(l))
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString(dimStyle.Render("  " + strings.Repeat("─", nameWidth+44)))
+	sb.WriteString("\n")
+	sb.WriteString(labelStyle.Render(fmt.Sprintf("  %-*s", nameWidth, "total")))
+	sb.WriteString(lipgloss.NewStyle().Bold(true).Render(row(report.Total)))
+	sb.WriteString("\n")
+
+	return sb.String()
+}
+
+// RenderFileAnalysis renders one file's size, imports, types and
+// functions as a single panel, under the name the user gave it
+func RenderFileAnalysis(name string, fa parser.FileAnalysis) string {
+	var sb strings.Builder
+
+	header := headerStyle.Render("📄 FILE: " + name)
+	sb.WriteString(header)
+	sb.WriteString("\n\n")
+
+	language := fa.Language
+	if language == "" {
+		language = "unrecognized language"
+	}
+	sb.WriteString(boxStyle.Render(fmt.Sprintf("%s %s\n%s %d\n%s %s",
+		labelStyle.Render("Language:"), language,
+		labelStyle.Render("Lines:   "), fa.Lines,
+		labelStyle.Render("Size:    "), formatSize(fa.Size))))
+	sb.WriteString("\n\n")
+
+	list := func(title string, items []string, style lipgloss.Style) {
+		if len(items) == 0 {
+			return
+		}
+		sb.WriteString(labelStyle.Render(fmt.Sprintf("  %s (%d)", title, len(items))))
+		sb.WriteString("\n")
+		for i, item := range items {
+			connector := "├──"
+			if i == len(items)-1 {
+				connector = "└──"
+			}
+			sb.WriteString("  " + dimStyle.Render(connector) + " " + style.Render(item))
+			sb.WriteString("\n")
+		}
+		sb.WriteString("\n")
+	}
+	list("📦 Imports", fa.Imports, fileStyle)
+	list("◆ Structs & classes", fa.Structs, fieldStyle)
+	list("◈ Interfaces", fa.Interfaces, lipgloss.NewStyle().Foreground(purple))
+
+	if len(fa.Functions) > 0 {
+		sb.WriteString(labelStyle.Render(fmt.Sprintf("  ƒ Functions (%d)", len(fa.Functions))))
+		sb.WriteString("\n")
+		for i, fn := range fa.Functions {
+			connector := "├──"
+			if i == len(fa.Functions)-1 {
+				connector = "└──"
+			}
+			sb.WriteString("  " + dimStyle.Render(connector) + " " + methodStyle.Render(fn.Name))
+			sb.WriteString(dimStyle.Render(fmt.Sprintf("  %d lines  :%d", fn.Lines(), fn.Line)))
+			sb.WriteString("\n")
+		}
+	}
+
+	if len(fa.Imports)+len(fa.Structs)+len(fa.Interfaces)+len(fa.Functions) == 0 {
+		sb.WriteString(dimStyle.Render("  No functions, types or imports found."))
+		sb.WriteString("\n")
+	}
+
+	return sb.String()
+}
+
+// searchGroup is a heading of /search results
+type searchGroup struct {
+	kind  string
+	title string
+	style lipgloss.Style
+}
+
+// searchGroups are the headings of /search results, in order
+func searchGroups() []searchGroup {
+	return []searchGroup{
+		{parser.SymbolFunction, "ƒ Functions", methodStyle},
+		{parser.SymbolMethod, "◇ Methods", methodStyle},
+		{parser.SymbolStruct, "◆ Structs & classes", fieldStyle},
+		{parser.SymbolInterface, "◈ Interfaces", lipgloss.NewStyle().Foreground(purple)},
+	}
+}
+
+// RenderSearchResults lists symbols matching query grouped by kind, with
+// the matched part of each name highlighted
+func RenderSearchResults(query string, hits []parser.SymbolHit) string {
+	var sb strings.Builder
+
+	header := headerStyle.Render("🔍 SEARCH: " + query)
+	sb.WriteString(header)
+	sb.WriteString("\n\n")
+
+	if len(hits) == 0 {
+		sb.WriteString(dimStyle.Render(fmt.Sprintf("  No symbols matching %q.", query)))
+		sb.WriteString("\n")
+		return sb.String()
+	}
+
+	matchStyle := lipgloss.NewStyle().Foreground(pink).Bold(true).Underline(true)
+	lowerQuery := strings.ToLower(query)
+
+	for _, group := range searchGroups() {
+		var inGroup []parser.SymbolHit
+		nameWidth := 0
+		for _, hit := range hits {
+			if hit.Kind == group.kind {
+				inGroup = append(inGroup, hit)
+				nameWidth = max(nameWidth, len(searchName(hit)))
+			}
+		}
+		if len(inGroup) == 0 {
+			continue
+		}
+		nameWidth = min(nameWidth, 50)
+
+		sb.WriteString(labelStyle.Render(fmt.Sprintf("  %s (%d)", group.title, len(inGroup))))
+		sb.WriteString("\n")
+
+		for _, hit := range inGroup {
+			name := searchName(hit)
+			if len(name) > nameWidth {
+				name = name[:nameWidth-1] + "…"
+			}
+			padding := strings.Repeat(" ", nameWidth-utf8.RuneCountInString(name))
+
+			// Highlight the match within the symbol's own name, not
+			// its receiver
+			styled := group.style.Render(name)
+			offset := len(name) - len(hit.Name)
+			if offset >= 0 {
+				if i := strings.Index(strings.ToLower(hit.Name), lowerQuery); i >= 0 && offset+i+len(query) <= len(name) {
+					start, end := offset+i, offset+i+len(query)
+					styled = group.style.Render(name[:start]) + matchStyle.Render(name[start:end]) + group.style.Render(name[end:])
+				}
+			}
+
+			sb.WriteString("    " + styled + padding)
+			sb.WriteString(dimStyle.Render(fmt.Sprintf("  %s:%d", hit.File, hit.Line)))
+			sb.WriteString("\n")
+		}
+		sb.WriteString("\n")
+	}
+
+	return sb.String()
+}
+
+// searchName is how a hit is listed: methods with their type
+func searchName(hit parser.SymbolHit) string {
+	if hit.Receiver != "" {
+		return hit.Receiver + "." + hit.Name
+	}
+	return hit.Name
+}
+
+// todoTag is a /todo group and its color
+type todoTag struct {
+	tag   string
+	color lipgloss.TerminalColor
+}
+
+// todoTags orders /todo groups from most to least urgent
+func todoTags() []todoTag {
+	return []todoTag{
+		{"FIXME", pink},
+		{"XXX", purple},
+		{"HACK", orange},
+		{"TODO", yellow},
+	}
+}
+
+// RenderTodos lists TODO-style comments grouped by tag, each with the
+// file and line to jump to
+func RenderTodos(todos []parser.TodoItem) string {
+	var sb strings.Builder
+
+	header := headerStyle.Render("📌 TODO / FIXME")
+	sb.WriteString(header)
+	sb.WriteString("\n\n")
+
+	if len(todos) == 0 {
+		sb.WriteString(dimStyle.Render("  No TODO, FIXME, HACK or XXX comments found."))
+		sb.WriteString("\n")
+		return sb.String()
+	}
+
+	for _, group := range todoTags() {
+		var inGroup []parser.TodoItem
+		locWidth := 0
+		for _, todo := range todos {
+			if todo.Tag == group.tag {
+				inGroup = append(inGroup, todo)
+				locWidth = max(locWidth, len(fmt.Sprintf("%s:%d", todo.Path, todo.Line)))
+			}
+		}
+		if len(inGroup) == 0 {
+			continue
+		}
+		locWidth = min(locWidth, 50)
+
+		tagStyle := lipgloss.NewStyle().Foreground(group.color).Bold(true)
+		sb.WriteString(tagStyle.Render(fmt.Sprintf("  %s (%d)", group.tag, len(inGroup))))
+		sb.WriteString("\n")
+
+		for _, todo := range inGroup {
+			loc := fmt.Sprintf("%s:%d", todo.Path, todo.Line)
+			if len(loc) > locWidth {
+				loc = "…" + loc[len(loc)-locWidth+1:]
+			}
+			text := todo.Text
+			if text == "" {
+				text = "(no description)"
+			}
+			sb.WriteString(fileStyle.Render(fmt.Sprintf("    %-*s", locWidth, loc)))
+			sb.WriteString("  ")
+			sb.WriteString(lipgloss.NewStyle().Foreground(group.color).Render(text))
+			sb.WriteString("\n")
 		}
 		sb.WriteString("\n")
 	}
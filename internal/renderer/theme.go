@@ -0,0 +1,193 @@
+package renderer
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Theme is the palette views are drawn with. Colors are named for their
+// hue in the dark theme; other themes substitute shades that read on
+// their background, or no color at all.
+type Theme struct {
+	Name string
+
+	Cyan   lipgloss.TerminalColor
+	Pink   lipgloss.TerminalColor
+	Yellow lipgloss.TerminalColor
+	Purple lipgloss.TerminalColor
+	Green  lipgloss.TerminalColor
+	Blue   lipgloss.TerminalColor
+	Orange lipgloss.TerminalColor
+	Amber  lipgloss.TerminalColor
+	Red    lipgloss.TerminalColor
+	Violet lipgloss.TerminalColor
+	Gray   lipgloss.TerminalColor // Secondary text
+	Faint  lipgloss.TerminalColor // Hints, fainter than Gray
+	White  lipgloss.TerminalColor // Text on colored backgrounds
+
+	Bar       lipgloss.TerminalColor // Title and status bar background
+	BarText   lipgloss.TerminalColor // Status bar text
+	Selection lipgloss.TerminalColor // Background of just-arrived events
+
+	// Shaded animations pulse through lighter tints of their color,
+	// which only reads on a dark background
+	Shaded bool
+}
+
+var (
+	// DarkTheme is the default, for dark terminal backgrounds
+	DarkTheme = Theme{
+		Name:      "dark",
+		Cyan:      lipgloss.Color("#4ECDC4"),
+		Pink:      lipgloss.Color("#FF6B6B"),
+		Yellow:    lipgloss.Color("#FFE66D"),
+		Purple:    lipgloss.Color("#A855F7"),
+		Green:     lipgloss.Color("#10B981"),
+		Blue:      lipgloss.Color("#3B82F6"),
+		Orange:    lipgloss.Color("#F97316"),
+		Amber:     lipgloss.Color("#F59E0B"),
+		Red:       lipgloss.Color("#EF4444"),
+		Violet:    lipgloss.Color("#8B5CF6"),
+		Gray:      lipgloss.Color("#6B7280"),
+		Faint:     lipgloss.Color("#666666"),
+		White:     lipgloss.Color("#FFFFFF"),
+		Bar:       lipgloss.Color("#1A1A2E"),
+		BarText:   lipgloss.Color("#98D8C8"),
+		Selection: lipgloss.Color("#1F2937"),
+		Shaded:    true,
+	}
+
+	// LightTheme uses darker shades of the same hues for light
+	// backgrounds
+	LightTheme = Theme{
+		Name:      "light",
+		Cyan:      lipgloss.Color("#0E7490"),
+		Pink:      lipgloss.Color("#BE123C"),
+		Yellow:    lipgloss.Color("#A16207"),
+		Purple:    lipgloss.Color("#7E22CE"),
+		Green:     lipgloss.Color("#047857"),
+		Blue:      lipgloss.Color("#1D4ED8"),
+		Orange:    lipgloss.Color("#C2410C"),
+		Amber:     lipgloss.Color("#B45309"),
+		Red:       lipgloss.Color("#B91C1C"),
+		Violet:    lipgloss.Color("#6D28D9"),
+		Gray:      lipgloss.Color("#4B5563"),
+		Faint:     lipgloss.Color("#6B7280"),
+		White:     lipgloss.Color("#FFFFFF"),
+		Bar:       lipgloss.Color("#E5E7EB"),
+		BarText:   lipgloss.Color("#1F2937"),
+		Selection: lipgloss.Color("#F3F4F6"),
+	}
+
+	// MonoTheme draws without color, in the terminal's own foreground and
+	// background, for low-vision and color-blind users and for captures.
+	// Bold, italic and borders still mark structure.
+	MonoTheme = Theme{
+		Name:      "mono",
+		Cyan:      lipgloss.NoColor{},
+		Pink:      lipgloss.NoColor{},
+		Yellow:    lipgloss.NoColor{},
+		Purple:    lipgloss.NoColor{},
+		Green:     lipgloss.NoColor{},
+		Blue:      lipgloss.NoColor{},
+		Orange:    lipgloss.NoColor{},
+		Amber:     lipgloss.NoColor{},
+		Red:       lipgloss.NoColor{},
+		Violet:    lipgloss.NoColor{},
+		Gray:      lipgloss.NoColor{},
+		Faint:     lipgloss.NoColor{},
+		White:     lipgloss.NoColor{},
+		Bar:       lipgloss.NoColor{},
+		BarText:   lipgloss.NoColor{},
+		Selection: lipgloss.NoColor{},
+	}
+)
+
+// themes lists the built-in themes in the order /theme shows them
+var themes = []Theme{DarkTheme, LightTheme, MonoTheme}
+
+// activeTheme is the theme set by SetTheme
+var activeTheme Theme
+
+func init() {
+	SetTheme(DarkTheme)
+}
+
+// LookupTheme finds a built-in theme by name, ignoring case
+func LookupTheme(name string) (Theme, bool) {
+	for _, t := range themes {
+		if strings.EqualFold(t.Name, name) {
+			return t, true
+		}
+	}
+	return Theme{}, false
+}
+
+// ThemeNames lists the built-in themes
+func ThemeNames() []string {
+	names := make([]string, len(themes))
+	for i, t := range themes {
+		names[i] = t.Name
+	}
+	return names
+}
+
+// ActiveTheme returns the theme views are currently drawn with
+func ActiveTheme() Theme {
+	return activeTheme
+}
+
+// SetTheme switches the palette and rebuilds the shared styles. Views
+// rendered afterwards use the new colors; output already rendered keeps
+// the old ones.
+func SetTheme(t Theme) {
+	activeTheme = t
+
+	cyan, pink, yellow, purple = t.Cyan, t.Pink, t.Yellow, t.Purple
+	green, blue, orange = t.Green, t.Blue, t.Orange
+	amber, red = t.Amber, t.Red
+	gray, white = t.Gray, t.White
+
+	headerStyle = lipgloss.NewStyle().
+		Bold(true).
+		Foreground(cyan).
+		BorderStyle(lipgloss.DoubleBorder()).
+		BorderForeground(cyan).
+		Padding(0, 2)
+
+	boxStyle = lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(purple).
+		Padding(0, 1)
+
+	classBoxStyle = lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(blue).
+		Padding(0, 1)
+
+	methodStyle = lipgloss.NewStyle().
+		Foreground(green)
+
+	fieldStyle = lipgloss.NewStyle().
+		Foreground(yellow)
+
+	fileStyle = lipgloss.NewStyle().
+		Foreground(cyan)
+
+	dirStyle = lipgloss.NewStyle().
+		Foreground(purple).
+		Bold(true)
+
+	labelStyle = lipgloss.NewStyle().
+		Foreground(pink).
+		Bold(true)
+
+	dimStyle = lipgloss.NewStyle().
+		Foreground(gray)
+
+	highlightStyle = lipgloss.NewStyle().
+		Foreground(white).
+		Background(purple).
+		Padding(0, 1)
+}
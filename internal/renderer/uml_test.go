@@ -0,0 +1,62 @@
+package renderer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/barisercan/arcsii/internal/parser"
+)
+
+func TestUMLRelationsMatchExactTypeNames(t *testing.T) {
+	classes := []parser.ClassInfo{
+		{Name: "User", Fields: []parser.FieldInfo{{Name: "Name", Type: "string"}}},
+		{Name: "Users", Fields: []parser.FieldInfo{{Name: "list", Type: "[]User"}}},
+		{Name: "Team", Fields: []parser.FieldInfo{
+			{Name: "members", Type: "Users"},
+			{Name: "lead", Type: "*store.User"},
+		}},
+	}
+
+	mermaid := RenderUMLMermaid(classes, nil)
+	for _, want := range []string{"Team --> Users : members", "Team --> User : lead", "Users --> User : list"} {
+		if !strings.Contains(mermaid, want) {
+			t.Errorf("missing %q in:\n%s", want, mermaid)
+		}
+	}
+	if strings.Contains(mermaid, "Team --> User : members") {
+		t.Errorf("a Users field links to User:\n%s", mermaid)
+	}
+
+	known := classNames(classes)
+	for typ, want := range map[string]string{
+		"Users":           "Users",
+		"UserID":          "",
+		"map[string]User": "User",
+		"[]*User":         "User",
+	} {
+		got := strings.Join(fieldClasses(parser.FieldInfo{Type: typ}, known), ",")
+		if got != want {
+			t.Errorf("fieldClasses(%q) = %q, want %q", typ, got, want)
+		}
+	}
+}
+
+func TestUMLMermaidEmbedsOnlyProjectStructs(t *testing.T) {
+	classes := []parser.ClassInfo{
+		{Name: "Base", Package: "store"},
+		{Name: "User", Package: "api", Fields: []parser.FieldInfo{
+			{Name: "sync.Mutex", Type: "(embedded)"},
+			{Name: "*store.Base", Type: "(embedded)"},
+		}},
+	}
+
+	mermaid := RenderUMLMermaid(classes, nil)
+	if !strings.Contains(mermaid, "User *-- Base : embeds") {
+		t.Errorf("missing User *-- Base in:\n%s", mermaid)
+	}
+	for _, phantom := range []string{"sync_Mutex", "store_Base"} {
+		if strings.Contains(mermaid, phantom) {
+			t.Errorf("embeds draw a phantom %s class:\n%s", phantom, mermaid)
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/barisercan/arcsii/internal/parser"
+	"github.com/barisercan/arcsii/internal/watcher"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Bell modes
+const (
+	bellOff   = "off"
+	bellRing  = "on"
+	bellFlash = "flash"
+)
+
+// gitBellOps are the git operations /bell accepts by name; "git" stands
+// for all of them
+var gitBellOps = map[string]bool{
+	"commit": true, "push": true, "pull": true, "fetch": true, "merge": true,
+	"rebase": true, "tag": true, "stash": true, "checkout": true,
+}
+
+// bell alerts on chosen events while arcsii sits on another screen.
+// Unlike sound cues, which follow every file event, it fires only for
+// its triggers: git operations, file operations, or paths matching a
+// glob.
+type bell struct {
+	mode     string
+	triggers []string        // As typed, for the status line
+	ops      map[string]bool // Git and file operations, "git" for every git one
+	files    []*parser.Glob
+	last     time.Time
+}
+
+// defaultBell is off and, once turned on, fires for git operations only
+func defaultBell() bell {
+	return bell{mode: bellOff, triggers: []string{"git"}, ops: map[string]bool{"git": true}}
+}
+
+// fires reports whether an event is one of the bell's triggers
+func (b bell) fires(event watcher.FileEvent) bool {
+	if event.IsGitOp {
+		return event.GitOp != "" && (b.ops["git"] || b.ops[event.GitOp])
+	}
+	if b.ops[event.Operation] {
+		return true
+	}
+	for _, glob := range b.files {
+		if glob.Match(event.Path) {
+			return true
+		}
+	}
+	return false
+}
+
+// bellCommand shows the bell's state, or sets its mode (on, flash or
+// off) and, after the mode, its triggers. Args keep their case so globs
+// match.
+func (m Model) bellCommand(args []string) Model {
+	if len(args) == 0 {
+		m.status = fmt.Sprintf("Bell %s · triggers: %s (usage: /bell on|flash|off [triggers...])",
+			m.bell.mode, strings.Join(m.bell.triggers, ", "))
+		return m
+	}
+
+	mode := strings.ToLower(args[0])
+	if mode != bellRing && mode != bellFlash && mode != bellOff {
+		m.status = fmt.Sprintf("Unknown bell mode %q (use on, flash or off)", args[0])
+		return m
+	}
+
+	b := m.bell
+	if triggers := args[1:]; len(triggers) > 0 {
+		b.triggers, b.ops, b.files = nil, make(map[string]bool), nil
+		for _, trigger := range triggers {
+			name := strings.ToLower(trigger)
+			if op, ok := filterOps[name]; ok {
+				b.ops[op] = true
+			} else if name == "git" || gitBellOps[name] {
+				b.ops[name] = true
+			} else {
+				glob, err := parser.CompileGlob(trigger)
+				if err != nil {
+					m.status = fmt.Sprintf("Error: %v", err)
+					return m
+				}
+				b.files = append(b.files, glob)
+			}
+			b.triggers = append(b.triggers, trigger)
+		}
+	}
+	b.mode = mode
+	m.bell = b
+
+	if mode == bellOff {
+		m.status = "Bell off"
+	} else {
+		m.status = fmt.Sprintf("Bell %s for %s", mode, strings.Join(b.triggers, ", "))
+	}
+	return m
+}
+
+// ringBell returns the alert for an event that triggers the bell,
+// rate-limited like the sound cues
+func (m Model) ringBell(event watcher.FileEvent) (Model, tea.Cmd) {
+	if m.bell.mode == bellOff || !m.bell.fires(event) || time.Since(m.bell.last) < soundCooldown {
+		return m, nil
+	}
+	m.bell.last = time.Now()
+	if m.bell.mode == bellFlash {
+		return m, flashScreen
+	}
+	return m, ringBells(1)
+}
+
+// flashScreen briefly switches the terminal to reverse video, the
+// visual bell
+func flashScreen() tea.Msg {
+	os.Stderr.WriteString("\x1b[?5h")
+	time.Sleep(150 * time.Millisecond)
+	os.Stderr.WriteString("\x1b[?5l")
+	return nil
+}
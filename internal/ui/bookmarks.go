@@ -0,0 +1,219 @@
+package ui
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// maxBookmarks is the number of bookmarks reachable with alt+1..alt+9
+const maxBookmarks = 9
+
+// Bookmark is a saved view: the command that produced it and how far
+// it was scrolled
+type Bookmark struct {
+	TargetDir string    `json:"target_dir"`
+	Command   string    `json:"command"`
+	Offset    int       `json:"offset"`
+	Created   time.Time `json:"created"`
+}
+
+// dataDir returns the directory arcsii persists user data in
+func dataDir() (string, error) {
+	if dir := os.Getenv("XDG_DATA_HOME"); dir != "" {
+		return filepath.Join(dir, "arcsii"), nil
+	}
+	if runtime.GOOS == "linux" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		return filepath.Join(home, ".local", "share", "arcsii"), nil
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "arcsii"), nil
+}
+
+func bookmarksPath() (string, error) {
+	dir, err := dataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "bookmarks.json"), nil
+}
+
+// readAllBookmarks loads the bookmarks of every project
+func readAllBookmarks() []Bookmark {
+	path, err := bookmarksPath()
+	if err != nil {
+		return nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+
+	var all []Bookmark
+	if err := json.Unmarshal(data, &all); err != nil {
+		return nil
+	}
+	return all
+}
+
+// loadBookmarks returns the saved bookmarks for one project
+func loadBookmarks(targetDir string) []Bookmark {
+	var bookmarks []Bookmark
+	for _, b := range readAllBookmarks() {
+		if b.TargetDir == targetDir {
+			bookmarks = append(bookmarks, b)
+		}
+	}
+	return bookmarks
+}
+
+// saveBookmarks replaces one project's bookmarks on disk, keeping the
+// bookmarks of other projects
+func saveBookmarks(targetDir string, bookmarks []Bookmark) error {
+	path, err := bookmarksPath()
+	if err != nil {
+		return err
+	}
+
+	var all []Bookmark
+	for _, b := range readAllBookmarks() {
+		if b.TargetDir != targetDir {
+			all = append(all, b)
+		}
+	}
+	all = append(all, bookmarks...)
+
+	data, err := json.MarshalIndent(all, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// addBookmark saves the current command and scroll position
+func (m Model) addBookmark() Model {
+	if len(m.bookmarks) >= maxBookmarks {
+		m.status = fmt.Sprintf("Bookmark limit reached (%d) - /bookmarks delete <n> to free a slot", maxBookmarks)
+		return m
+	}
+
+	bookmark := Bookmark{
+		TargetDir: m.targetDir,
+		Command:   m.currentCmd,
+		Offset:    m.viewport.YOffset,
+		Created:   time.Now(),
+	}
+	m.bookmarks = append(m.bookmarks, bookmark)
+
+	if err := saveBookmarks(m.targetDir, m.bookmarks); err != nil {
+		m.status = fmt.Sprintf("Bookmark %d added (not saved: %v)", len(m.bookmarks), err)
+	} else {
+		m.status = fmt.Sprintf("Bookmark %d: %s (alt+%d to jump)", len(m.bookmarks), bookmark.Command, len(m.bookmarks))
+	}
+	return m
+}
+
+// jumpToBookmark re-runs a bookmarked command and restores its offset
+func (m Model) jumpToBookmark(index int) Model {
+	if index < 0 || index >= len(m.bookmarks) {
+		m.status = fmt.Sprintf("No bookmark %d", index+1)
+		return m
+	}
+
+	bookmark := m.bookmarks[index]
+	m, _ = m.runCommand(bookmark.Command)
+	m.viewport.SetYOffset(bookmark.Offset)
+	m.status = fmt.Sprintf("Bookmark %d: %s", index+1, bookmark.Command)
+	return m
+}
+
+// bookmarksCommand handles /bookmarks [n | delete <n> | clear]
+func (m Model) bookmarksCommand(args []string) Model {
+	if len(args) > 0 {
+		switch args[0] {
+		case "clear":
+			m.bookmarks = nil
+			saveBookmarks(m.targetDir, nil)
+			m.status = "Bookmarks cleared"
+		case "delete", "rm":
+			n := 0
+			if len(args) > 1 {
+				n, _ = strconv.Atoi(args[1])
+			}
+			if n < 1 || n > len(m.bookmarks) {
+				m.status = "Usage: /bookmarks delete <n>"
+				return m
+			}
+			m.bookmarks = append(m.bookmarks[:n-1:n-1], m.bookmarks[n:]...)
+			saveBookmarks(m.targetDir, m.bookmarks)
+			m.status = fmt.Sprintf("Bookmark %d deleted", n)
+		default:
+			n, err := strconv.Atoi(args[0])
+			if err != nil {
+				m.status = "Usage: /bookmarks [n | delete <n> | clear]"
+				return m
+			}
+			return m.jumpToBookmark(n - 1)
+		}
+	}
+
+	m.watchMode = false
+	m.currentCmd = "/bookmarks"
+	m.tree, m.treeLines = nil, nil
+	m.content = m.renderBookmarks()
+	if len(args) == 0 {
+		m.status = "Bookmarks"
+	}
+	m.viewport.SetContent(m.content)
+	m.viewport.GotoTop()
+	return m
+}
+
+func (m Model) renderBookmarks() string {
+	var sb strings.Builder
+
+	header := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(theme.Cyan).
+		BorderStyle(lipgloss.DoubleBorder()).
+		BorderForeground(theme.Cyan).
+		Padding(0, 2).
+		Render("🔖 BOOKMARKS")
+	sb.WriteString(header)
+	sb.WriteString("\n\n")
+
+	if len(m.bookmarks) == 0 {
+		sb.WriteString(timeStyle.Render("    No bookmarks yet. Press ctrl+b on any view to save it."))
+		sb.WriteString("\n")
+		return sb.String()
+	}
+
+	for i, b := range m.bookmarks {
+		sb.WriteString(fmt.Sprintf("    %s  %s  %s\n",
+			modifyStyle.Render(fmt.Sprintf("alt+%d", i+1)),
+			filePathStyle.Render(b.Command),
+			timeStyle.Render(fmt.Sprintf("line %d · saved %s", b.Offset+1, b.Created.Format("Jan 2 15:04")))))
+	}
+
+	sb.WriteString("\n")
+	sb.WriteString(helpStyle.Render("    ctrl+b bookmark current view · /bookmarks <n> jump · /bookmarks delete <n> · /bookmarks clear"))
+	sb.WriteString("\n")
+	return sb.String()
+}
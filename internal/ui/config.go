@@ -0,0 +1,54 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+)
+
+// tickInterval is how often the live view advances its animations
+const tickInterval = 100 * time.Millisecond
+
+// liveConfig holds the live view's limits. Durations are in ticks.
+type liveConfig struct {
+	maxEvents      int // Events kept, and held back while paused
+	shownEvents    int // Events drawn in the list
+	animTicks      int // How long a git animation runs
+	highlightTicks int // How long a new event stays highlighted
+}
+
+// defaultLiveConfig keeps 50 events, draws 20, animates git operations
+// for 5 seconds and highlights new events for 3
+var defaultLiveConfig = liveConfig{
+	maxEvents:      50,
+	shownEvents:    20,
+	animTicks:      50,
+	highlightTicks: 30,
+}
+
+// liveConfigFromEnv applies ARCSII_MAX_EVENTS, the events kept and
+// drawn, and ARCSII_ANIM_SECONDS to the defaults. An invalid value keeps
+// its default and is described in the returned warning.
+func liveConfigFromEnv() (liveConfig, string) {
+	cfg := defaultLiveConfig
+	var warning string
+
+	if v := os.Getenv("ARCSII_MAX_EVENTS"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			cfg.maxEvents, cfg.shownEvents = n, n
+		} else {
+			warning = fmt.Sprintf("ignoring ARCSII_MAX_EVENTS=%q, want a positive number", v)
+		}
+	}
+
+	if v := os.Getenv("ARCSII_ANIM_SECONDS"); v != "" {
+		if s, err := strconv.ParseFloat(v, 64); err == nil && s >= 0 {
+			cfg.animTicks = int(time.Duration(s*float64(time.Second)) / tickInterval)
+		} else {
+			warning = fmt.Sprintf("ignoring ARCSII_ANIM_SECONDS=%q, want seconds such as 2.5", v)
+		}
+	}
+
+	return cfg, warning
+}
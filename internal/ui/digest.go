@@ -0,0 +1,123 @@
+package ui
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/barisercan/arcsii/internal/watcher"
+)
+
+// maxDigests is how many closed digest windows are kept
+const maxDigests = 48
+
+// digestOps orders operations in a digest line
+var digestOps = []struct {
+	op   string
+	verb string
+}{
+	{"modified", "modifies"},
+	{"created", "creates"},
+	{"deleted", "deletes"},
+	{"renamed", "renames"},
+}
+
+// digestLine is one closed window rolled up into a summary
+type digestLine struct {
+	End  time.Time
+	Text string
+}
+
+// digest rolls file events up into one line per interval, so a long
+// watch session stays scannable instead of an endless raw stream
+type digest struct {
+	interval time.Duration // Zero disables the digest
+	start    time.Time
+	counts   map[string]int // Operation -> events in the current window
+	dirs     map[string]int // Directory -> events in the current window
+	lines    []digestLine   // Newest first
+}
+
+// parseDigestInterval accepts a Go duration ("5m", "90s") or a bare
+// number of minutes; "off" and "0" disable the digest
+func parseDigestInterval(s string) (time.Duration, error) {
+	if s == "off" || s == "0" {
+		return 0, nil
+	}
+	if minutes, err := strconv.Atoi(s); err == nil && minutes > 0 {
+		return time.Duration(minutes) * time.Minute, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d < time.Second {
+		return 0, fmt.Errorf("invalid summary interval %q (e.g. 5m, 30s, off)", s)
+	}
+	return d, nil
+}
+
+// setInterval starts a fresh window with the given length
+func (d *digest) setInterval(interval time.Duration, now time.Time) {
+	d.interval = interval
+	d.start = now
+	d.counts = make(map[string]int)
+	d.dirs = make(map[string]int)
+}
+
+// add counts a file event towards the current window
+func (d *digest) add(event watcher.FileEvent) {
+	if d.interval == 0 || event.IsGitOp {
+		return
+	}
+	d.counts[event.Operation]++
+	d.dirs[filepath.ToSlash(filepath.Dir(event.Path))]++
+}
+
+// roll closes the current window once the interval has passed. Empty
+// windows don't produce a line.
+func (d *digest) roll(now time.Time) {
+	if d.interval == 0 || now.Sub(d.start) < d.interval {
+		return
+	}
+
+	if text := d.summary(now); text != "" {
+		d.lines = append([]digestLine{{End: now, Text: text}}, d.lines...)
+		if len(d.lines) > maxDigests {
+			d.lines = d.lines[:maxDigests]
+		}
+	}
+	d.setInterval(d.interval, now)
+}
+
+// summary formats the current window, e.g.
+// "14:00–14:05: 23 modifies, 2 creates in internal/parser"
+func (d *digest) summary(end time.Time) string {
+	var parts []string
+	for _, o := range digestOps {
+		if n := d.counts[o.op]; n > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", n, o.verb))
+		}
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+
+	top, topCount := "", 0
+	for dir, n := range d.dirs {
+		if n > topCount || (n == topCount && dir < top) {
+			top, topCount = dir, n
+		}
+	}
+
+	where := " in " + top
+	if len(d.dirs) > 1 {
+		where = fmt.Sprintf(" across %d dirs, mostly %s", len(d.dirs), top)
+	}
+
+	return fmt.Sprintf("%s–%s: %s%s",
+		d.start.Format("15:04"), end.Format("15:04"), strings.Join(parts, ", "), where)
+}
+
+func renderDigestLine(d digestLine) string {
+	return timeStyle.Render("    ── "+d.Text+" ──") + "\n"
+}
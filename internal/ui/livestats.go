@@ -0,0 +1,93 @@
+package ui
+
+import (
+	"path/filepath"
+	"time"
+
+	"github.com/barisercan/arcsii/internal/parser"
+	"github.com/barisercan/arcsii/internal/watcher"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// statsRescanInterval is how often the running counters are corrected
+// with a full walk of the project
+const statsRescanInterval = time.Minute
+
+// statsScanMsg carries the result of a full file stats walk. Root lets
+// scans of a previous target directory be discarded.
+type statsScanMsg struct {
+	root  string
+	files map[string]parser.FileInfo
+}
+
+// liveStats keeps running file and line counters for the watch view,
+// adjusted per event instead of re-walking the project on every change
+type liveStats struct {
+	files     map[string]parser.FileInfo
+	fileCount int
+	lineCount int
+	baseline  int // Line count at the first full scan
+	ready     bool
+}
+
+func scanStatsCmd(root string) tea.Cmd {
+	return func() tea.Msg {
+		return statsScanMsg{root: root, files: parser.ParseFileStats(root)}
+	}
+}
+
+// reset replaces the counters with the result of a full scan
+func (s *liveStats) reset(files map[string]parser.FileInfo) {
+	s.files = files
+	s.fileCount = len(files)
+	s.lineCount = 0
+	for _, fi := range files {
+		s.lineCount += fi.Lines
+	}
+
+	if !s.ready {
+		s.baseline = s.lineCount
+		s.ready = true
+	}
+}
+
+// apply adjusts the counters for a single file event
+func (s *liveStats) apply(root string, event watcher.FileEvent) {
+	if !s.ready || event.IsGitOp {
+		return
+	}
+
+	// A paired rename moves the file: drop the old path, count the new one
+	op := event.Operation
+	if op == "renamed" && event.OldPath != "" {
+		s.remove(event.OldPath)
+		op = "created"
+	}
+
+	switch op {
+	case "created", "modified":
+		fi := parser.QuickFileStats(filepath.Join(root, event.Path))
+		if fi.Path == "" {
+			return // Directory or unreadable file
+		}
+		s.remove(event.Path)
+		s.files[event.Path] = fi
+		s.fileCount++
+		s.lineCount += fi.Lines
+	case "deleted", "renamed":
+		s.remove(event.Path)
+	}
+}
+
+func (s *liveStats) remove(path string) {
+	if old, ok := s.files[path]; ok {
+		delete(s.files, path)
+		s.fileCount--
+		s.lineCount -= old.Lines
+	}
+}
+
+// delta returns the lines added (or removed) since arcsii started
+func (s *liveStats) delta() int {
+	return s.lineCount - s.baseline
+}
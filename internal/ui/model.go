@@ -1,70 +1,55 @@
 package ui
 
 import (
+	"errors"
 	"fmt"
+	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/barisercan/arcsii/internal/commands"
+	"github.com/barisercan/arcsii/internal/parser"
+	"github.com/barisercan/arcsii/internal/renderer"
 	"github.com/barisercan/arcsii/internal/watcher"
 	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
 )
 
-// Styles
+// Styles, built from the active theme by applyTheme
 var (
-	titleStyle = lipgloss.NewStyle().
-			Bold(true).
-			Foreground(lipgloss.Color("#FF6B6B")).
-			Background(lipgloss.Color("#1A1A2E")).
-			Padding(0, 1)
-
-	inputStyle = lipgloss.NewStyle().
-			Border(lipgloss.RoundedBorder()).
-			BorderForeground(lipgloss.Color("#4ECDC4")).
-			Padding(0, 1)
-
-	helpStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#666666")).
-			Italic(true)
-
-	statusStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#98D8C8")).
-			Background(lipgloss.Color("#1A1A2E")).
-			Padding(0, 1)
+	titleStyle  lipgloss.Style
+	inputStyle  lipgloss.Style
+	helpStyle   lipgloss.Style
+	statusStyle lipgloss.Style
 
 	// Live event styles
-	createStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#10B981")).
-			Bold(true)
-
-	modifyStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#F59E0B")).
-			Bold(true)
-
-	deleteStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#EF4444")).
-			Bold(true)
-
-	renameStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#8B5CF6")).
-			Bold(true)
-
-	filePathStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#4ECDC4"))
-
-	timeStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#6B7280"))
+	createStyle   lipgloss.Style
+	modifyStyle   lipgloss.Style
+	deleteStyle   lipgloss.Style
+	renameStyle   lipgloss.Style
+	filePathStyle lipgloss.Style
+	timeStyle     lipgloss.Style
 
 	pulseColors = []string{"#FF6B6B", "#FF8E8E", "#FFB0B0", "#FF8E8E", "#FF6B6B"}
 
 	// Default commands to cycle through
-	defaultCommands = []string{"/watch", "/tree", "/uml", "/ascii", "/deps", "/changes", "/stats", "/funcs", "/help"}
+	defaultCommands = []string{"/watch", "/tree", "/uml", "/ascii", "/deps", "/changes", "/stats", "/funcs", "/sizeof", "/routes", "/arch", "/smells", "/complexity", "/bookmarks", "/help"}
+
+	// Function keys that open a view from an empty prompt
+	viewKeys = map[string]string{
+		"f1": "help", "f2": "watch", "f3": "tree", "f4": "uml",
+		"f5": "deps", "f6": "stats", "f7": "changes", "f8": "loc",
+	}
 )
 
+// commandPlaceholder is shown in the empty command prompt
+const commandPlaceholder = "Type a command (e.g., /help, /tree, /uml) or watch live changes..."
+
 // EventDisplay wraps a file event with display state
 type EventDisplay struct {
 	Event     watcher.FileEvent
@@ -72,6 +57,10 @@ type EventDisplay struct {
 	Highlight bool
 }
 
+// Model is owned by the Bubble Tea update goroutine: Update and View are
+// the only code that reads or writes it. Background work (listening for
+// watcher events, stats scans, bells) gets its inputs up front and
+// reports back through messages, so fields like events need no locking.
 type Model struct {
 	targetDir    string
 	input        textinput.Model
@@ -85,29 +74,91 @@ type Model struct {
 	history      []string
 	historyIndex int
 
+	// Tab completion: the candidates for the typed prefix and the one
+	// shown, kept while tab is pressed repeatedly
+	completions     []string
+	completionIndex int
+
 	// Live watch mode
-	watcher       *watcher.Watcher
-	events        []EventDisplay
-	watchMode     bool
-	tick          int
-	pulseIndex    int
-	gitAnimation  string // Current git animation type
-	gitAnimTick   int    // Animation frame counter
+	watcher      *watcher.Watcher
+	events       []EventDisplay
+	watchMode    bool
+	tick         int
+	pulseIndex   int
+	gitAnimation string // Current git animation type
+	gitAnimTick  int    // Animation frame counter
+	gitDetail    string // Event detail for the animation, e.g. the branch checked out
+	gitDeleted   bool   // The animated ref was removed, e.g. a deleted tag
+	lastRewrite  string // Summary of the most recent history rewrite
+	config       liveConfig
+
+	// While paused, events are held back so the list stays still
+	paused       bool
+	pending      []watcher.FileEvent // Newest last, at most config.maxEvents
+	pendingCount int                 // Events received while paused
+
+	// Operations the live list shows; nil shows all
+	filter map[string]bool
+
+	// Paths the live feed is scoped to by /watch <glob>; nil is everything
+	scope *parser.Glob
+
+	// Search of command output: searching while the prompt takes the
+	// term, then the content lines holding it and the one scrolled to
+	searching  bool
+	searchTerm string
+	matches    []int
+	matchIndex int
+
+	// A plain /tree view, drawn with folding: collapsed directory paths
+	// and the node on each content line, for mapping clicks
+	tree      *parser.FileNode
+	collapsed map[string]bool
+	treeLines []*parser.FileNode
+
+	// Ambient sound cues
+	soundEnabled bool
+	lastSound    time.Time
+
+	// Alerts for chosen events, set by /bell
+	bell bell
+
+	// Running file/line counters for the live view
+	stats *liveStats
+
+	// Periodic one-line summaries of the event stream
+	digest *digest
+
+	// Rendered event lines reused across ticks
+	renderCache *renderCache
+
+	// Saved views
+	currentCmd string
+	bookmarks  []Bookmark
+
+	// Workspace (go.work, pnpm or npm workspaces) at the target, if any
+	workspaceFile string
+	workspace     []string
 }
 
 // Messages
-type fileEventMsg watcher.FileEvent
+// fileEventMsg carries a watcher event. Source identifies the watcher it
+// came from, so events from a watcher replaced by /cd are dropped.
+type fileEventMsg struct {
+	source *watcher.Watcher
+	event  watcher.FileEvent
+}
 type tickMsg time.Time
 
 func NewModel(targetDir string) Model {
+	applyTheme(renderer.ActiveTheme())
+
 	ti := textinput.New()
-	ti.Placeholder = "Type a command (e.g., /help, /tree, /uml) or watch live changes..."
+	ti.Placeholder = commandPlaceholder
 	ti.Focus()
 	ti.CharLimit = 256
 	ti.Width = 60
-	ti.PromptStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#FF6B6B"))
-	ti.TextStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#4ECDC4")).Bold(true)
-	ti.PlaceholderStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#666666"))
+	styleInput(&ti)
 
 	// Start file watcher - resolve absolute path first
 	absDir, err := filepath.Abs(targetDir)
@@ -117,11 +168,33 @@ func NewModel(targetDir string) Model {
 
 	w, err := watcher.New(absDir)
 	watchStatus := "Watching"
-	if err != nil {
+	switch {
+	case errors.Is(err, watcher.ErrWatchLimitExceeded):
+		watchStatus = fmt.Sprintf("Watching %d dirs (OS watch limit reached)", w.WatchCount())
+	case err != nil:
 		watchStatus = fmt.Sprintf("Watch error: %v", err)
 		w = nil
-	} else {
-		watchStatus = fmt.Sprintf("Watching %d dirs", w.WatchCount)
+	default:
+		watchStatus = fmt.Sprintf("Watching %d dirs", w.WatchCount())
+	}
+
+	workspaceFile, workspace := watchWorkspace(w, absDir)
+
+	config, warning := liveConfigFromEnv()
+	if warning != "" {
+		watchStatus += " · " + warning
+	}
+
+	registry := commands.NewRegistry(targetDir)
+	registry.Describe("watch", "Live file monitor")
+	registry.Describe("bookmarks", "Saved views (ctrl+b to add)")
+	registry.Describe("cd", "Switch project directory")
+	registry.Describe("sound", "Toggle sound cues")
+	registry.Describe("bell", "Ring or flash on git operations or chosen files")
+	registry.Describe("theme", "Switch color theme (dark, light, mono)")
+	registry.Describe("filter", "Show only some live events, e.g. created,deleted")
+	for key, command := range viewKeys {
+		registry.Shortcut(command, strings.ToUpper(key))
 	}
 
 	return Model{
@@ -129,7 +202,7 @@ func NewModel(targetDir string) Model {
 		input:        ti,
 		content:      "", // Will be set in Init
 		status:       watchStatus,
-		cmdRegistry:  commands.NewRegistry(targetDir),
+		cmdRegistry:  registry,
 		history:      []string{},
 		historyIndex: -1,
 		watcher:      w,
@@ -137,6 +210,17 @@ func NewModel(targetDir string) Model {
 		watchMode:    true,
 		tick:         0,
 		pulseIndex:   0,
+		config:       config,
+		soundEnabled: soundEnabledFromEnv(),
+		bell:         defaultBell(),
+		stats:        &liveStats{},
+		digest:       &digest{},
+		renderCache:  newRenderCache(),
+		currentCmd:   "/watch",
+		bookmarks:    loadBookmarks(absDir),
+
+		workspaceFile: workspaceFile,
+		workspace:     workspace,
 	}
 }
 
@@ -149,11 +233,12 @@ func (m Model) Init() tea.Cmd {
 		textinput.Blink,
 		listenForEvents(m.watcher),
 		tickCmd(),
+		scanStatsCmd(m.targetDir),
 	)
 }
 
 func tickCmd() tea.Cmd {
-	return tea.Tick(time.Millisecond*100, func(t time.Time) tea.Msg {
+	return tea.Tick(tickInterval, func(t time.Time) tea.Msg {
 		return tickMsg(t)
 	})
 }
@@ -163,28 +248,31 @@ func listenForEvents(w *watcher.Watcher) tea.Cmd {
 		return nil
 	}
 	return func() tea.Msg {
-		select {
-		case event := <-w.Events:
-			return fileEventMsg(event)
+		event, ok := <-w.Events
+		if !ok {
+			return nil // Watcher stopped
 		}
+		return fileEventMsg{source: w, event: event}
 	}
 }
 
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var (
-		tiCmd tea.Cmd
-		vpCmd tea.Cmd
+		tiCmd  tea.Cmd
+		vpCmd  tea.Cmd
+		runCmd tea.Cmd
 	)
 
 	switch msg := msg.(type) {
 	case tickMsg:
 		m.tick++
 		m.pulseIndex = (m.pulseIndex + 1) % len(pulseColors)
+		m.digest.roll(time.Time(msg))
 
 		// Handle git animation
 		if m.gitAnimation != "" {
 			m.gitAnimTick++
-			if m.gitAnimTick > 50 { // 5 seconds
+			if m.gitAnimTick > m.config.animTicks {
 				m.gitAnimation = ""
 				m.gitAnimTick = 0
 			}
@@ -193,50 +281,87 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		// Age events and remove old highlights
 		for i := range m.events {
 			m.events[i].Age++
-			if m.events[i].Age > 30 { // 3 seconds
+			if m.events[i].Age > m.config.highlightTicks {
 				m.events[i].Highlight = false
 			}
 		}
 
-		// Update viewport content if in watch mode
-		if m.watchMode {
-			m.content = m.renderLiveView()
-			m.viewport.SetContent(m.content)
+		// Update viewport content if in watch mode; a paused view stays
+		// frozen, spinner included
+		if m.watchMode && !m.paused {
+			m = m.refreshLiveView()
 		}
 
-		return m, tea.Batch(tickCmd(), listenForEvents(m.watcher))
+		// Periodically correct drift in the running stats
+		var rescan tea.Cmd
+		if m.tick%int(statsRescanInterval/tickInterval) == 0 {
+			rescan = scanStatsCmd(m.targetDir)
+		}
 
-	case fileEventMsg:
-		event := watcher.FileEvent(msg)
+		return m, tea.Batch(tickCmd(), rescan)
 
-		// Check for git operations and trigger animation
-		if event.IsGitOp && event.GitOp != "" {
-			m.gitAnimation = event.GitOp
-			m.gitAnimTick = 0
+	case statsScanMsg:
+		if msg.root == m.targetDir {
+			m.stats.reset(msg.files)
 		}
+		return m, nil
 
-		// Add new event at the beginning
-		m.events = append([]EventDisplay{{
-			Event:     event,
-			Age:       0,
-			Highlight: true,
-		}}, m.events...)
+	case fileEventMsg:
+		// Exactly one listener is outstanding per watcher; it's renewed
+		// here rather than on every tick
+		if msg.source != m.watcher {
+			return m, nil
+		}
+		event := msg.event
+		m.stats.apply(m.targetDir, event)
+
+		// The bell rings for its triggers even outside the scope or
+		// while paused
+		var bellCmd tea.Cmd
+		m, bellCmd = m.ringBell(event)
 
-		// Keep only last 50 events
-		if len(m.events) > 50 {
-			m.events = m.events[:50]
+		// Out-of-scope events still count toward the project stats
+		if !m.inScope(event) {
+			return m, tea.Batch(listenForEvents(m.watcher), bellCmd)
 		}
+		m.digest.add(event)
 
-		if event.IsGitOp {
-			m.status = fmt.Sprintf("Git %s detected!", event.GitOp)
-		} else {
-			m.status = fmt.Sprintf("File %s: %s", event.Operation, event.Name)
+		if m.paused {
+			m.pending = append(m.pending, event)
+			if len(m.pending) > m.config.maxEvents {
+				m.pending = m.pending[1:]
+			}
+			m.pendingCount++
+			m.status = fmt.Sprintf("PAUSED (%d pending)", m.pendingCount)
+			return m, tea.Batch(listenForEvents(m.watcher), bellCmd)
 		}
 
-		return m, listenForEvents(m.watcher)
+		var soundCmd tea.Cmd
+		m, soundCmd = m.showEvent(event)
+		return m, tea.Batch(listenForEvents(m.watcher), soundCmd, bellCmd)
 
 	case tea.KeyMsg:
+		if msg.String() != "tab" {
+			m.completions = nil
+		}
+		if m.searching && msg.String() != "ctrl+c" {
+			return m.updateSearch(msg)
+		}
+		// Function keys never type anything, but a half-typed command
+		// isn't replaced either
+		if command, ok := viewKeys[msg.String()]; ok {
+			if m.input.Value() != "" {
+				return m, nil
+			}
+			// Flipping back to the live view keeps its scope
+			if command == "watch" && m.scope != nil {
+				command += " " + m.scope.String()
+			}
+			return m.runCommand("/" + command)
+		}
 		switch msg.String() {
+		case "tab":
+			return m.complete(), nil
 		case "ctrl+c", "esc":
 			if m.watcher != nil {
 				m.watcher.Stop()
@@ -249,21 +374,37 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.history = append(m.history, cmd)
 				m.historyIndex = len(m.history)
 
-				// Check for special commands
-				cmdLower := strings.ToLower(strings.TrimPrefix(cmd, "/"))
-				if cmdLower == "watch" || cmdLower == "live" || cmdLower == "w" {
-					m.watchMode = true
-					m.content = m.renderLiveView()
-					m.status = "Watching"
-				} else {
-					m.watchMode = false
-					m.content, m.status = m.cmdRegistry.Execute(cmd)
-				}
-
+				m, runCmd = m.runCommand(cmd)
 				m.input.Reset()
-				m.viewport.SetContent(m.content)
-				m.viewport.GotoTop()
 			}
+		case "ctrl+b":
+			m = m.addBookmark()
+			return m, nil
+		case "ctrl+p", " ":
+			// Space pauses only from an empty prompt, so it can still be typed
+			if msg.String() == " " && m.input.Value() != "" {
+				break
+			}
+			if m.watchMode || m.paused {
+				return m.togglePause()
+			}
+		case "ctrl+l":
+			if m.watchMode {
+				return m.clearEvents(), nil
+			}
+		case "ctrl+f":
+			return m.startSearch(), nil
+		case "n", "N":
+			// Typed as usual unless a search is showing and the prompt is empty
+			if len(m.matches) > 0 && m.input.Value() == "" {
+				if msg.String() == "n" {
+					return m.nextMatch(1), nil
+				}
+				return m.nextMatch(-1), nil
+			}
+		case "alt+1", "alt+2", "alt+3", "alt+4", "alt+5", "alt+6", "alt+7", "alt+8", "alt+9":
+			m = m.jumpToBookmark(int(msg.String()[len("alt+")] - '1'))
+			return m, nil
 		case "up":
 			// Combine history with default commands for cycling
 			allCommands := append(m.history, defaultCommands...)
@@ -291,6 +432,11 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 
+	case tea.MouseMsg:
+		if msg.Action == tea.MouseActionPress && msg.Button == tea.MouseButtonLeft {
+			return m.clickTree(msg.Y)
+		}
+
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
@@ -310,12 +456,431 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 
 		m.input.Width = m.width - 10
+		m.cmdRegistry.SetWidth(m.viewport.Width)
+
+		// Views laid out for the old width are redrawn for the new one
+		if m.ready && !m.watchMode && m.cmdRegistry.WidthAware(m.currentCmd) {
+			offset := m.viewport.YOffset
+			m.content, m.status = m.cmdRegistry.Execute(m.currentCmd)
+			m.viewport.SetContent(m.content)
+			if m.searchTerm != "" {
+				m = m.highlightMatches()
+			}
+			m.viewport.SetYOffset(offset)
+		}
 	}
 
 	m.input, tiCmd = m.input.Update(msg)
 	m.viewport, vpCmd = m.viewport.Update(msg)
 
-	return m, tea.Batch(tiCmd, vpCmd)
+	return m, tea.Batch(tiCmd, vpCmd, runCmd)
+}
+
+// runCommand executes a typed command and shows its output
+func (m Model) runCommand(cmd string) (Model, tea.Cmd) {
+	m = m.clearSearch()
+
+	// Check for special commands
+	cmdLower := strings.ToLower(strings.TrimPrefix(cmd, "/"))
+	fields := strings.Fields(cmdLower)
+
+	switch {
+	case len(fields) > 0 && (fields[0] == "watch" || fields[0] == "live" || fields[0] == "w"):
+		m.watchMode = true
+		m.currentCmd = "/watch"
+		m.tree, m.treeLines = nil, nil
+		m.status = "Watching"
+
+		// Globs keep their case, which cmdLower loses
+		original := strings.Fields(strings.TrimPrefix(cmd, "/"))
+		if len(fields) == 1 {
+			m.scope = nil
+		}
+		for i := 1; i < len(fields); i++ {
+			value := ""
+			if fields[i] == "--summary-interval" && i+1 < len(fields) {
+				value = fields[i+1]
+				i++
+			} else if strings.HasPrefix(fields[i], "--summary-interval=") {
+				value = strings.TrimPrefix(fields[i], "--summary-interval=")
+			} else {
+				m = m.setScope(original[i])
+				continue
+			}
+
+			interval, err := parseDigestInterval(value)
+			if err != nil {
+				m.status = err.Error()
+				break
+			}
+			m.digest.setInterval(interval, time.Now())
+			if interval > 0 {
+				m.status = fmt.Sprintf("Watching · digest every %s", interval)
+			} else {
+				m.status = "Watching · digest off"
+			}
+		}
+		m.content = m.renderLiveView()
+	case len(fields) > 0 && fields[0] == "sound":
+		switch {
+		case len(fields) > 1 && fields[1] == "on":
+			m.soundEnabled = true
+		case len(fields) > 1 && fields[1] == "off":
+			m.soundEnabled = false
+		default:
+			m.soundEnabled = !m.soundEnabled
+		}
+		if m.soundEnabled {
+			m.status = "Sound cues on"
+		} else {
+			m.status = "Sound cues off"
+		}
+		return m, nil
+	case len(fields) > 0 && fields[0] == "bell":
+		// Globs are case-sensitive, so take the arguments from the raw input
+		return m.bellCommand(strings.Fields(strings.TrimPrefix(cmd, "/"))[1:]), nil
+	case len(fields) > 0 && fields[0] == "theme":
+		return m.themeCommand(fields[1:]), nil
+	case len(fields) > 0 && fields[0] == "filter":
+		return m.setFilter(strings.Join(fields[1:], ",")), nil
+	case len(fields) > 0 && (fields[0] == "bookmarks" || fields[0] == "bm"):
+		return m.bookmarksCommand(fields[1:]), nil
+	case len(fields) > 0 && fields[0] == "cd":
+		// Paths are case-sensitive, so take the argument from the raw input
+		_, path, _ := strings.Cut(strings.TrimSpace(strings.TrimPrefix(cmd, "/")), " ")
+		return m.changeDir(strings.TrimSpace(path))
+	default:
+		m.watchMode = false
+		m.currentCmd = cmd
+		m.tree, m.treeLines = nil, nil
+		if tree, depth := m.cmdRegistry.FileTree(cmd); tree != nil {
+			m.tree, m.collapsed = tree, make(map[string]bool)
+			foldBelow(tree, depth, m.collapsed)
+			m.content, m.treeLines = renderer.RenderTreeInteractive(m.tree, m.collapsed)
+			m.status = "File tree"
+			break
+		}
+		m.content, m.status = m.cmdRegistry.Execute(cmd)
+	}
+
+	m.viewport.SetContent(m.content)
+	m.viewport.GotoTop()
+	return m, nil
+}
+
+// complete fills in the command being typed. When several commands
+// match, repeated tabs cycle through them and the status line lists them.
+func (m Model) complete() Model {
+	if len(m.completions) > 0 {
+		m.completionIndex = (m.completionIndex + 1) % len(m.completions)
+	} else {
+		value := m.input.Value()
+		if strings.ContainsRune(strings.TrimSpace(value), ' ') {
+			return m // Only the command name is completed
+		}
+		m.completions = m.cmdRegistry.CompletionCandidates(strings.TrimSpace(value))
+		m.completionIndex = 0
+		if len(m.completions) == 0 {
+			m.status = fmt.Sprintf("No command matches %q", value)
+			return m
+		}
+	}
+
+	m.input.SetValue("/" + m.completions[m.completionIndex])
+	m.input.CursorEnd()
+	if len(m.completions) > 1 {
+		candidates := make([]string, len(m.completions))
+		for i, name := range m.completions {
+			candidates[i] = "/" + name
+			if i == m.completionIndex {
+				candidates[i] = "[" + candidates[i] + "]"
+			}
+		}
+		m.status = "Tab: " + strings.Join(candidates, " ")
+	}
+	return m
+}
+
+// showEvent puts an event at the top of the live list, starting any git
+// animation it calls for. The returned command plays its sound cue.
+func (m Model) showEvent(event watcher.FileEvent) (Model, tea.Cmd) {
+	// Check for git operations and trigger animation. A history
+	// rewrite warning isn't replaced by the ref updates that follow it.
+	if event.Destructive {
+		m.gitAnimation = "destructive"
+		m.gitAnimTick = 0
+		m.lastRewrite = fmt.Sprintf("%s at %s", event.GitDetail, event.Time.Format("15:04:05"))
+	} else if event.IsGitOp && event.GitOp != "" && m.gitAnimation != "destructive" {
+		m.gitAnimation = event.GitOp
+		m.gitAnimTick = 0
+		m.gitDetail = event.GitDetail
+		m.gitDeleted = event.Operation == "deleted"
+	}
+
+	// Filtered-out events are dropped rather than kept hidden, so they
+	// can't push the ones being watched for out of the list
+	if !m.shows(event) {
+		return m, nil
+	}
+
+	// Add new event at the beginning
+	m.events = append([]EventDisplay{{
+		Event:     event,
+		Age:       0,
+		Highlight: true,
+	}}, m.events...)
+
+	// Newest events go on top; when scrolled down to older ones, move
+	// the offset along so the lines being read stay put
+	if m.watchMode && m.viewport.YOffset > 0 {
+		m.viewport.YOffset += lipgloss.Height(m.renderEvent(m.events[0]))
+	}
+
+	// Keep only the most recent events
+	if len(m.events) > m.config.maxEvents {
+		m.events = m.events[:m.config.maxEvents]
+	}
+
+	if event.Destructive {
+		m.status = "⚠ History rewritten: " + event.GitDetail
+	} else if event.IsGitOp {
+		m.status = fmt.Sprintf("Git %s detected!", event.GitOp)
+	} else {
+		m.status = fmt.Sprintf("File %s: %s", event.Operation, event.Name)
+	}
+
+	// Ambient sound cue, rate-limited
+	var soundCmd tea.Cmd
+	if m.soundEnabled && !event.IsGitOp && time.Since(m.lastSound) >= soundCooldown {
+		if soundCmd = ringBells(bellPatterns[event.Operation]); soundCmd != nil {
+			m.lastSound = time.Now()
+		}
+	}
+	return m, soundCmd
+}
+
+// filterOps maps the names /filter accepts to event operations
+var filterOps = map[string]string{
+	"created": "created", "create": "created",
+	"modified": "modified", "modify": "modified",
+	"deleted": "deleted", "delete": "deleted",
+	"renamed": "renamed", "rename": "renamed",
+}
+
+// shows reports whether the live list shows an event under the filter
+// and scope
+func (m Model) shows(event watcher.FileEvent) bool {
+	return (m.filter == nil || m.filter[event.Operation]) && m.inScope(event)
+}
+
+// inScope reports whether an event falls under the /watch scope. Git
+// operations concern the whole repository, so they always do.
+func (m Model) inScope(event watcher.FileEvent) bool {
+	return m.scope == nil || event.IsGitOp || m.scope.Match(event.Path)
+}
+
+// setScope limits the live feed to paths matching a glob
+func (m Model) setScope(pattern string) Model {
+	scope, err := parser.CompileGlob(pattern)
+	if err != nil {
+		m.status = fmt.Sprintf("Error: %v", err)
+		return m
+	}
+	m.scope = scope
+	m.status = "Watching " + scope.String()
+	return m
+}
+
+// setFilter limits the live list to a comma-separated list of operations;
+// an empty list or "all" shows every event again
+func (m Model) setFilter(arg string) Model {
+	filter := make(map[string]bool)
+	for _, name := range strings.Split(arg, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" || name == "all" {
+			continue
+		}
+		op, ok := filterOps[name]
+		if !ok {
+			m.status = fmt.Sprintf("Unknown operation %q (created, modified, deleted, renamed or all)", name)
+			return m
+		}
+		filter[op] = true
+	}
+	if len(filter) == 0 || slices.Contains(strings.Split(arg, ","), "all") {
+		filter = nil
+	}
+
+	m.filter = filter
+	if filter == nil {
+		m.status = "Showing all events"
+	} else {
+		m.status = "Showing only " + m.filterLabel() + " events"
+	}
+	if m.watchMode {
+		m = m.refreshLiveView()
+	}
+	return m
+}
+
+// filterLabel lists the filtered operations in a fixed order
+func (m Model) filterLabel() string {
+	var ops []string
+	for _, op := range []string{"created", "modified", "deleted", "renamed"} {
+		if m.filter[op] {
+			ops = append(ops, op)
+		}
+	}
+	return strings.Join(ops, ",")
+}
+
+// togglePause pauses the live list, or resumes it and shows the events
+// that arrived meanwhile in the order they happened
+func (m Model) togglePause() (Model, tea.Cmd) {
+	if !m.paused {
+		m.paused = true
+		m.status = "PAUSED (0 pending)"
+		return m, nil
+	}
+
+	m.paused = false
+	m.status = "Watching"
+	var cmds []tea.Cmd
+	for _, event := range m.pending {
+		var soundCmd tea.Cmd
+		m, soundCmd = m.showEvent(event)
+		cmds = append(cmds, soundCmd)
+	}
+	if m.pendingCount > 0 {
+		m.status = fmt.Sprintf("Resumed · %d new events", m.pendingCount)
+	}
+	m.pending = nil
+	m.pendingCount = 0
+	if m.watchMode {
+		m = m.refreshLiveView()
+	}
+	return m, tea.Batch(cmds...)
+}
+
+// clearEvents empties the live list, along with its digest lines and any
+// events held back while paused, and resumes watching
+func (m Model) clearEvents() Model {
+	m.events = []EventDisplay{}
+	m.digest.lines = nil
+	m.paused = false
+	m.pending = nil
+	m.pendingCount = 0
+	m.status = "Watching"
+
+	m.content = m.renderLiveView()
+	m.viewport.SetContent(m.content)
+	m.viewport.GotoTop()
+	return m
+}
+
+// changeDir re-homes arcsii on another directory: the command registry,
+// watcher, events and running stats all switch to the new root
+func (m Model) changeDir(path string) (Model, tea.Cmd) {
+	if path == "" {
+		m.status = "Usage: /cd <path>"
+		return m, nil
+	}
+
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			path = filepath.Join(home, path[1:])
+		}
+	}
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(m.targetDir, path)
+	}
+	path = filepath.Clean(path)
+
+	info, err := os.Stat(path)
+	if err != nil {
+		m.status = fmt.Sprintf("cd: %v", err)
+		return m, nil
+	}
+	if !info.IsDir() {
+		m.status = fmt.Sprintf("cd: %s is not a directory", path)
+		return m, nil
+	}
+
+	if m.watcher != nil {
+		m.watcher.Stop()
+		m.watcher = nil
+	}
+
+	w, err := watcher.New(path)
+	switch {
+	case errors.Is(err, watcher.ErrWatchLimitExceeded):
+		w.Start()
+		m.watcher = w
+		m.status = fmt.Sprintf("Now in %s · watching %d dirs (OS watch limit reached)", path, w.WatchCount())
+	case err != nil:
+		m.status = fmt.Sprintf("Now in %s (watch error: %v)", path, err)
+	default:
+		w.Start()
+		m.watcher = w
+		m.status = fmt.Sprintf("Now in %s · watching %d dirs", path, w.WatchCount())
+	}
+
+	m.targetDir = path
+	m.cmdRegistry.SetTargetDir(path)
+	m.workspaceFile, m.workspace = watchWorkspace(m.watcher, path)
+	m.events = []EventDisplay{}
+	m.renderCache.reset()
+	m.gitAnimation = ""
+	m.gitAnimTick = 0
+	m.gitDetail = ""
+	m.gitDeleted = false
+	m.lastRewrite = ""
+	m.paused = false
+	m.pending = nil
+	m.pendingCount = 0
+	m.scope = nil
+	m.stats = &liveStats{}
+	m.digest = &digest{}
+	m.bookmarks = loadBookmarks(path)
+
+	m.watchMode = true
+	m.currentCmd = "/watch"
+	m.tree, m.treeLines = nil, nil
+	m.content = m.renderLiveView()
+	m.viewport.SetContent(m.content)
+	m.viewport.GotoTop()
+
+	return m, tea.Batch(listenForEvents(m.watcher), scanStatsCmd(path))
+}
+
+// watchWorkspace detects a workspace at root and makes the watcher cover
+// members that live outside it (e.g. "use ../shared" in go.work)
+func watchWorkspace(w *watcher.Watcher, root string) (string, []string) {
+	members, err := parser.ParseWorkspace(root)
+	if err != nil {
+		return "", nil
+	}
+
+	if w != nil {
+		for _, member := range members {
+			if rel := filepath.ToSlash(member); rel == ".." || strings.HasPrefix(rel, "../") {
+				w.AddRoot(filepath.Join(root, member))
+			}
+		}
+	}
+	return parser.WorkspaceFile(root), members
+}
+
+// refreshLiveView re-renders the live view, keeping the scroll position.
+// SetContent alone would jump to the bottom whenever the content shrinks
+// below the offset, e.g. when a git animation ends. At the top (offset 0)
+// the view follows new events.
+func (m Model) refreshLiveView() Model {
+	offset := m.viewport.YOffset
+	m.content = m.renderLiveView()
+	m.viewport.SetContent(m.content)
+	m.viewport.SetYOffset(offset)
+	return m
 }
 
 func (m Model) renderLiveView() string {
@@ -325,15 +890,19 @@ func (m Model) renderLiveView() string {
 	if m.gitAnimation != "" {
 		sb.WriteString(m.renderGitAnimation())
 		sb.WriteString("\n\n")
+	} else if m.lastRewrite != "" {
+		// Keep a summary of the last rewrite once the banner is gone
+		sb.WriteString(lipgloss.NewStyle().Foreground(theme.Red).Render("    ⚠ Last history rewrite: " + m.lastRewrite))
+		sb.WriteString("\n\n")
 	}
 
 	// Animated header
-	pulseColor := pulseColors[m.pulseIndex]
+	pulseColor := pulse(theme.Pink, pulseColors, m.pulseIndex)
 	headerStyle := lipgloss.NewStyle().
 		Bold(true).
-		Foreground(lipgloss.Color(pulseColor)).
+		Foreground(pulseColor).
 		BorderStyle(lipgloss.DoubleBorder()).
-		BorderForeground(lipgloss.Color(pulseColor)).
+		BorderForeground(pulseColor).
 		Padding(0, 2)
 
 	// Spinning animation
@@ -341,22 +910,55 @@ func (m Model) renderLiveView() string {
 	spinner := spinners[m.tick%len(spinners)]
 
 	sb.WriteString(headerStyle.Render(fmt.Sprintf("%s LIVE FILE MONITOR", spinner)))
-	sb.WriteString("\n\n")
+	sb.WriteString("\n")
+
+	if m.scope != nil {
+		sb.WriteString(filePathStyle.Render("    🎯 Scope: " + m.scope.String() + " (/watch to see everything)"))
+		sb.WriteString("\n")
+	}
+
+	if len(m.workspace) > 0 {
+		sb.WriteString(modifyStyle.Render(fmt.Sprintf("    🧩 %s workspace · %d modules: %s", m.workspaceFile, len(m.workspace), strings.Join(m.workspace, ", "))))
+		sb.WriteString("\n")
+	}
 
-	if len(m.events) == 0 && m.gitAnimation == "" {
+	// Running project counters
+	if m.stats.ready {
+		delta := m.stats.delta()
+		deltaStyle := timeStyle
+		if delta > 0 {
+			deltaStyle = createStyle
+		} else if delta < 0 {
+			deltaStyle = deleteStyle
+		}
+		sb.WriteString(timeStyle.Render(fmt.Sprintf("    📊 %d files · %d lines ", m.stats.fileCount, m.stats.lineCount)))
+		sb.WriteString(deltaStyle.Render(fmt.Sprintf("(%+d this session)", delta)))
+		sb.WriteString("\n")
+	}
+	sb.WriteString("\n")
+
+	visible := slices.ContainsFunc(m.events, func(ed EventDisplay) bool { return m.shows(ed.Event) })
+	if !visible && m.gitAnimation == "" {
 		// Waiting animation
 		dots := strings.Repeat(".", (m.tick/5)%4)
+		watchingFor := "changes"
+		if m.filter != nil {
+			watchingFor = m.filterLabel() + " events"
+		}
+		if m.scope != nil {
+			watchingFor += " in " + m.scope.String()
+		}
 		waiting := lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#6B7280")).
+			Foreground(theme.Gray).
 			Italic(true).
-			Render(fmt.Sprintf("    Watching for changes%s", dots))
+			Render(fmt.Sprintf("    Watching for %s%s", watchingFor, dots))
 
 		sb.WriteString(waiting)
 		sb.WriteString("\n\n")
 
 		// Show helpful tip
 		tip := lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#4ECDC4")).
+			Foreground(theme.Cyan).
 			Render("    💡 Make changes to any file and watch them appear here!")
 		sb.WriteString(tip)
 		sb.WriteString("\n\n")
@@ -365,27 +967,56 @@ func (m Model) renderLiveView() string {
 		art := m.renderWaitingAnimation()
 		sb.WriteString(art)
 	} else {
-		// Render events
-		for i, ed := range m.events {
-			if i >= 20 {
-				break // Show max 20 events
+		// Render events, with digest lines at the point their window closed
+		digests := m.digest.lines
+		shown := 0
+		for _, ed := range m.events {
+			if !m.shows(ed.Event) {
+				continue
+			}
+			if shown++; shown > m.config.shownEvents {
+				break
+			}
+			for len(digests) > 0 && !digests[0].End.Before(ed.Event.Time) {
+				sb.WriteString(renderDigestLine(digests[0]))
+				digests = digests[1:]
 			}
 			sb.WriteString(m.renderEvent(ed))
 			sb.WriteString("\n")
 		}
+		for _, d := range digests {
+			sb.WriteString(renderDigestLine(d))
+		}
 	}
+	m.renderCache.sweep()
 
 	// Footer with instructions
 	sb.WriteString("\n")
 	footer := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#666666")).
+		Foreground(theme.Faint).
 		Render("    Type /help for commands, /tree for file structure")
 	sb.WriteString(footer)
 
 	return sb.String()
 }
 
+// renderEvent returns the event's lines, reusing the cached rendering
+// while nothing visible about the event has changed
 func (m Model) renderEvent(ed EventDisplay) string {
+	key := eventKey{
+		path:      ed.Event.Path,
+		operation: ed.Event.Operation,
+		time:      ed.Event.Time.UnixNano(),
+		highlight: ed.Highlight,
+		preview:   len(ed.Event.Preview) > 0 && ed.Age < 50,
+		ago:       formatAgo(time.Since(ed.Event.Time)),
+	}
+	return m.renderCache.event(key, func() string {
+		return renderEventLines(ed, key.preview, key.ago)
+	})
+}
+
+func renderEventLines(ed EventDisplay, showPreview bool, timeStr string) string {
 	var sb strings.Builder
 	var opStyle lipgloss.Style
 	var icon string
@@ -410,42 +1041,45 @@ func (m Model) renderEvent(ed EventDisplay) string {
 
 	// Highlight effect for new events
 	if ed.Highlight {
-		opStyle = opStyle.Background(lipgloss.Color("#1F2937"))
-	}
-
-	// Format time
-	ago := time.Since(ed.Event.Time)
-	var timeStr string
-	if ago < time.Second {
-		timeStr = "just now"
-	} else if ago < time.Minute {
-		timeStr = fmt.Sprintf("%ds ago", int(ago.Seconds()))
-	} else {
-		timeStr = fmt.Sprintf("%dm ago", int(ago.Minutes()))
+		opStyle = opStyle.Background(theme.Selection)
 	}
 
 	// Get file extension for icon
 	fileIcon := getFileIcon(ed.Event.Name)
 
+	// Paired renames show where the file came from
+	path := filePathStyle.Render(ed.Event.Path)
+	if ed.Event.OldPath != "" {
+		path = timeStyle.Render(ed.Event.OldPath+" → ") + path
+	}
+
 	// Build the main line
 	line := fmt.Sprintf("    %s %s  %s  %s  %s",
 		opStyle.Render(icon),
 		opStyle.Render(fmt.Sprintf("%-10s", ed.Event.Operation)),
 		fileIcon,
-		filePathStyle.Render(ed.Event.Path),
+		path,
 		timeStyle.Render(timeStr),
 	)
 	sb.WriteString(line)
 
-	// Add preview lines if available (only for recent events)
-	if len(ed.Event.Preview) > 0 && ed.Age < 50 {
+	// Add the changed lines if available (only for recent events):
+	// additions in green, removals in red
+	if showPreview {
 		previewStyle := lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#6B7280")).
+			Foreground(theme.Gray).
 			PaddingLeft(8)
 
 		for _, pline := range ed.Event.Preview {
+			lineStyle := previewStyle
+			switch {
+			case strings.HasPrefix(pline, "+"):
+				lineStyle = lineStyle.Foreground(theme.Green)
+			case strings.HasPrefix(pline, "-"):
+				lineStyle = lineStyle.Foreground(theme.Red)
+			}
 			sb.WriteString("\n")
-			sb.WriteString(previewStyle.Render("│ " + pline))
+			sb.WriteString(lineStyle.Render("│ " + pline))
 		}
 	}
 
@@ -471,6 +1105,10 @@ func (m Model) renderGitAnimation() string {
 		art = m.renderRebaseAnimation(frame)
 	case "stash":
 		art = m.renderStashAnimation(frame)
+	case "tag":
+		art = m.renderTagAnimation(frame)
+	case "destructive":
+		art = m.renderForcePushWarning(frame)
 	default:
 		return ""
 	}
@@ -480,7 +1118,7 @@ func (m Model) renderGitAnimation() string {
 
 func (m Model) renderCommitAnimation(frame int) string {
 	colors := []string{"#10B981", "#34D399", "#6EE7B7", "#34D399", "#10B981"}
-	color := colors[frame%len(colors)]
+	color := pulse(theme.Green, colors, frame)
 
 	frames := []string{
 		`
@@ -509,12 +1147,12 @@ func (m Model) renderCommitAnimation(frame int) string {
     ╚═══════════════════════════════════════════════════════╝`,
 	}
 
-	return lipgloss.NewStyle().Foreground(lipgloss.Color(color)).Bold(true).Render(frames[frame/3%len(frames)])
+	return lipgloss.NewStyle().Foreground(color).Bold(true).Render(frames[frame/3%len(frames)])
 }
 
 func (m Model) renderPushAnimation(frame int) string {
 	colors := []string{"#3B82F6", "#60A5FA", "#93C5FD", "#60A5FA", "#3B82F6"}
-	color := colors[frame%len(colors)]
+	color := pulse(theme.Blue, colors, frame)
 
 	// Animated arrow going up
 	arrows := []string{
@@ -540,12 +1178,12 @@ func (m Model) renderPushAnimation(frame int) string {
     ║              Pushing to remote...                     ║
     ╚═══════════════════════════════════════════════════════╝`, arrowFrame)
 
-	return lipgloss.NewStyle().Foreground(lipgloss.Color(color)).Bold(true).Render(art)
+	return lipgloss.NewStyle().Foreground(color).Bold(true).Render(art)
 }
 
 func (m Model) renderPullAnimation(frame int) string {
 	colors := []string{"#8B5CF6", "#A78BFA", "#C4B5FD", "#A78BFA", "#8B5CF6"}
-	color := colors[frame%len(colors)]
+	color := pulse(theme.Violet, colors, frame)
 
 	// Animated arrow going down
 	arrows := []string{
@@ -571,12 +1209,12 @@ func (m Model) renderPullAnimation(frame int) string {
     ║              Pulling from remote...                   ║
     ╚═══════════════════════════════════════════════════════╝`, arrowFrame)
 
-	return lipgloss.NewStyle().Foreground(lipgloss.Color(color)).Bold(true).Render(art)
+	return lipgloss.NewStyle().Foreground(color).Bold(true).Render(art)
 }
 
 func (m Model) renderMergeAnimation(frame int) string {
 	colors := []string{"#F59E0B", "#FBBF24", "#FCD34D", "#FBBF24", "#F59E0B"}
-	color := colors[frame%len(colors)]
+	color := pulse(theme.Amber, colors, frame)
 
 	// Animated merge lines
 	mergeFrames := []string{
@@ -602,14 +1240,21 @@ func (m Model) renderMergeAnimation(frame int) string {
     ║              Merging branches...                      ║
     ╚═══════════════════════════════════════════════════════╝`, mergeFrame)
 
-	return lipgloss.NewStyle().Foreground(lipgloss.Color(color)).Bold(true).Render(art)
+	return lipgloss.NewStyle().Foreground(color).Bold(true).Render(art)
 }
 
 func (m Model) renderCheckoutAnimation(frame int) string {
 	colors := []string{"#EC4899", "#F472B6", "#F9A8D4", "#F472B6", "#EC4899"}
-	color := colors[frame%len(colors)]
+	color := pulse(theme.Pink, colors, frame)
 
-	art := `
+	// Name the branch when HEAD could be read, trimmed to fit the box
+	message := "Switching branches..."
+	if m.gitDetail != "" {
+		message = ansi.Truncate("Switched to "+m.gitDetail, 41, "…")
+	}
+	message += strings.Repeat(" ", max(41-lipgloss.Width(message), 0))
+
+	art := fmt.Sprintf(`
     ╔═══════════════════════════════════════════════════════╗
     ║                                                       ║
     ║      ██████╗██╗  ██╗███████╗ ██████╗██╗  ██╗ ██████╗  ║
@@ -620,15 +1265,15 @@ func (m Model) renderCheckoutAnimation(frame int) string {
     ║      ╚═════╝╚═╝  ╚═╝╚══════╝ ╚═════╝╚═╝  ╚═╝ ╚═════╝  ║
     ║                                                       ║
     ║              ◇────────────────────◆                   ║
-    ║              Switching branches...                    ║
-    ╚═══════════════════════════════════════════════════════╝`
+    ║              %s║
+    ╚═══════════════════════════════════════════════════════╝`, message)
 
-	return lipgloss.NewStyle().Foreground(lipgloss.Color(color)).Bold(true).Render(art)
+	return lipgloss.NewStyle().Foreground(color).Bold(true).Render(art)
 }
 
 func (m Model) renderRebaseAnimation(frame int) string {
 	colors := []string{"#EF4444", "#F87171", "#FCA5A5", "#F87171", "#EF4444"}
-	color := colors[frame%len(colors)]
+	color := pulse(theme.Red, colors, frame)
 
 	// Animated rebase blocks
 	blocks := []string{"▁", "▂", "▃", "▄", "▅", "▆", "▇", "█"}
@@ -648,12 +1293,12 @@ func (m Model) renderRebaseAnimation(frame int) string {
     ╚═══════════════════════════════════════════════════════╝`,
 		blockFrame, blockFrame, blockFrame, blockFrame, blockFrame, blockFrame, blockFrame, blockFrame)
 
-	return lipgloss.NewStyle().Foreground(lipgloss.Color(color)).Bold(true).Render(art)
+	return lipgloss.NewStyle().Foreground(color).Bold(true).Render(art)
 }
 
 func (m Model) renderStashAnimation(frame int) string {
 	colors := []string{"#14B8A6", "#2DD4BF", "#5EEAD4", "#2DD4BF", "#14B8A6"}
-	color := colors[frame%len(colors)]
+	color := pulse(theme.Cyan, colors, frame)
 
 	art := `
     ╔═══════════════════════════════════════════════════════╗
@@ -668,7 +1313,65 @@ func (m Model) renderStashAnimation(frame int) string {
     ║              📦 Changes stashed away!                 ║
     ╚═══════════════════════════════════════════════════════╝`
 
-	return lipgloss.NewStyle().Foreground(lipgloss.Color(color)).Bold(true).Render(art)
+	return lipgloss.NewStyle().Foreground(color).Bold(true).Render(art)
+}
+
+func (m Model) renderTagAnimation(frame int) string {
+	colors := []string{"#EAB308", "#FACC15", "#FDE047", "#FACC15", "#EAB308"}
+	color := pulse(theme.Yellow, colors, frame)
+
+	// A label swinging on its string
+	labels := []string{"◁━━◇", " ◁━◇", "◁━━◇", "◁━◇ "}
+	label := labels[frame/3%len(labels)]
+
+	message := "Tagged " + m.gitDetail
+	if m.gitDeleted {
+		message = "Deleted tag " + m.gitDetail
+	}
+	message = ansi.Truncate(message, 41, "…")
+	message += strings.Repeat(" ", max(41-lipgloss.Width(message), 0))
+
+	art := fmt.Sprintf(`
+    ╔═══════════════════════════════════════════════════════╗
+    ║                                                       ║
+    ║              ████████╗ █████╗  ██████╗                ║
+    ║              ╚══██╔══╝██╔══██╗██╔════╝                ║
+    ║                 ██║   ███████║██║  ███╗               ║
+    ║                 ██║   ██╔══██║██║   ██║               ║
+    ║                 ██║   ██║  ██║╚██████╔╝               ║
+    ║                 ╚═╝   ╚═╝  ╚═╝ ╚═════╝                ║
+    ║                                                       ║
+    ║              %s                                     ║
+    ║              %s║
+    ╚═══════════════════════════════════════════════════════╝`, label, message)
+
+	return lipgloss.NewStyle().Foreground(color).Bold(true).Render(art)
+}
+
+func (m Model) renderForcePushWarning(frame int) string {
+	// Alternate between red and amber so the banner can't be mistaken
+	// for a regular git animation
+	bg := []lipgloss.TerminalColor{theme.Red, theme.Amber}[frame/5%2]
+
+	banner := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(theme.White).
+		Background(bg).
+		Padding(1, 4).
+		MarginLeft(4).
+		Render("⚠  HISTORY REWRITTEN  ⚠")
+
+	detail := lipgloss.NewStyle().
+		Foreground(bg).
+		Bold(true).
+		Render("    " + m.lastRewrite)
+
+	hint := lipgloss.NewStyle().
+		Foreground(theme.Gray).
+		Italic(true).
+		Render("    A ref moved to a commit that does not contain its old tip (reset, amend, rebase or force push).\n    The old commit is still in the reflog: git reflog")
+
+	return banner + "\n\n" + detail + "\n" + hint
 }
 
 func (m Model) renderWaitingAnimation() string {
@@ -708,7 +1411,7 @@ func (m Model) renderWaitingAnimation() string {
 	}
 
 	frame := frames[(m.tick/3)%len(frames)]
-	return lipgloss.NewStyle().Foreground(lipgloss.Color(pulseColors[m.pulseIndex])).Render(frame)
+	return lipgloss.NewStyle().Foreground(pulse(theme.Pink, pulseColors, m.pulseIndex)).Render(frame)
 }
 
 func getFileIcon(name string) string {
@@ -741,6 +1444,16 @@ func getFileIcon(name string) string {
 	}
 }
 
+// scrollPosition tells where the viewport is in the content: "ALL" when
+// it fits on screen, else the percentage scrolled and the first line shown
+func (m Model) scrollPosition() string {
+	total := m.viewport.TotalLineCount()
+	if total <= m.viewport.Height {
+		return "ALL"
+	}
+	return fmt.Sprintf("%3.0f%% %d/%d", m.viewport.ScrollPercent()*100, m.viewport.YOffset+1, total)
+}
+
 func (m Model) View() string {
 	if !m.ready {
 		return "\n  Initializing..."
@@ -748,9 +1461,14 @@ func (m Model) View() string {
 
 	// Header with logo
 	var modeIndicator string
-	if m.watchMode {
+	if m.watchMode && m.paused {
 		modeIndicator = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#10B981")).
+			Foreground(theme.Amber).
+			Bold(true).
+			Render(" ❚❚ PAUSED")
+	} else if m.watchMode {
+		modeIndicator = lipgloss.NewStyle().
+			Foreground(theme.Green).
 			Bold(true).
 			Render(" ● LIVE")
 	} else {
@@ -766,7 +1484,20 @@ func (m Model) View() string {
 	input := inputStyle.Render(m.input.View())
 
 	// Status bar
-	status := statusStyle.Render("⚡ " + m.status + " │ " + m.targetDir + " │ ↑↓ scroll │ esc quit")
+	dir := m.targetDir
+	if focus := m.cmdRegistry.Focus(); focus != "" {
+		dir += " │ 🎯 " + focus
+	}
+	if m.filter != nil {
+		dir += " │ 🔍 " + m.filterLabel()
+	}
+	if m.bell.mode != bellOff {
+		dir += " │ 🔔"
+	}
+	if m.watcher != nil {
+		dir += fmt.Sprintf(" │ 👁 %d dirs", m.watcher.WatchCount())
+	}
+	status := statusStyle.Render("⚡ " + m.status + " │ " + dir + " │ " + m.scrollPosition() + " │ ↑↓ scroll │ esc quit")
 
 	return lipgloss.JoinVertical(
 		lipgloss.Left,
@@ -0,0 +1,90 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// runCmd runs a command, and each command of a batch, on its own
+// goroutine as Bubble Tea does, sending the messages they return to msgs
+func runCmd(cmd tea.Cmd, msgs chan<- tea.Msg) {
+	if cmd == nil {
+		return
+	}
+	go func() {
+		msg := cmd()
+		if batch, ok := msg.(tea.BatchMsg); ok {
+			for _, cmd := range batch {
+				runCmd(cmd, msgs)
+			}
+			return
+		}
+		if msg != nil {
+			msgs <- msg
+		}
+	}()
+}
+
+func TestEventListenersStayBounded(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	dir := t.TempDir()
+	m := NewModel(dir)
+	if m.watcher == nil {
+		t.Skip("no file watcher available")
+	}
+	defer m.watcher.Stop()
+
+	// Buffered so commands finishing after the loop don't block
+	msgs := make(chan tea.Msg, 1024)
+	update := func(msg tea.Msg) {
+		model, cmd := m.Update(msg)
+		m = model.(Model)
+		runCmd(cmd, msgs)
+	}
+	runCmd(m.Init(), msgs)
+
+	// Let the startup commands settle before taking the baseline
+	time.Sleep(200 * time.Millisecond)
+	baseline := runtime.NumGoroutine()
+
+	events := 0
+	for i := range 300 {
+		// Ticks are sent directly rather than waited for
+		update(tickMsg(time.Now()))
+		if i%10 == 0 {
+			path := filepath.Join(dir, fmt.Sprintf("file%d.txt", i))
+			if err := os.WriteFile(path, []byte("x"), 0o644); err != nil {
+				t.Fatal(err)
+			}
+		}
+		for drained := false; !drained; {
+			select {
+			case msg := <-msgs:
+				if _, ok := msg.(tickMsg); ok {
+					continue
+				}
+				if _, ok := msg.(fileEventMsg); ok {
+					events++
+				}
+				update(msg)
+			case <-time.After(time.Millisecond):
+				drained = true
+			}
+		}
+	}
+	if events == 0 {
+		t.Fatal("no file events arrived")
+	}
+
+	// Tick timers started by the loop end after one interval
+	time.Sleep(3 * tickInterval)
+	if got := runtime.NumGoroutine(); got > baseline+5 {
+		t.Errorf("%d goroutines after 300 ticks and %d events, started with %d", got, events, baseline)
+	}
+}
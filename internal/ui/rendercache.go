@@ -0,0 +1,70 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+)
+
+// eventKey identifies a rendered event line: the event itself plus every
+// piece of display state that changes its output
+type eventKey struct {
+	path      string
+	operation string
+	time      int64 // UnixNano of the event
+	highlight bool
+	preview   bool   // Preview lines are shown only for recent events
+	ago       string // Relative time label, e.g. "5s ago"
+}
+
+// renderCache memoizes rendered event lines between ticks. Most ticks only
+// move the spinner, so the styled event lines can be reused until their
+// highlight fades or their age label changes. Entries for events that are
+// no longer displayed are dropped on each render.
+type renderCache struct {
+	lines map[eventKey]string
+	used  map[eventKey]bool
+}
+
+func newRenderCache() *renderCache {
+	return &renderCache{
+		lines: make(map[eventKey]string),
+		used:  make(map[eventKey]bool),
+	}
+}
+
+// event returns the cached line for key, calling render on a miss
+func (c *renderCache) event(key eventKey, render func() string) string {
+	c.used[key] = true
+	if line, ok := c.lines[key]; ok {
+		return line
+	}
+	line := render()
+	c.lines[key] = line
+	return line
+}
+
+// sweep forgets lines not requested since the previous sweep
+func (c *renderCache) sweep() {
+	for key := range c.lines {
+		if !c.used[key] {
+			delete(c.lines, key)
+		}
+	}
+	clear(c.used)
+}
+
+// reset drops every cached line, e.g. when the watched root changes
+func (c *renderCache) reset() {
+	clear(c.lines)
+	clear(c.used)
+}
+
+// formatAgo is the relative time label shown next to an event
+func formatAgo(ago time.Duration) string {
+	if ago < time.Second {
+		return "just now"
+	} else if ago < time.Minute {
+		return fmt.Sprintf("%ds ago", int(ago.Seconds()))
+	}
+	return fmt.Sprintf("%dm ago", int(ago.Minutes()))
+}
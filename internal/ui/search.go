@@ -0,0 +1,141 @@
+package ui
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// searchPrompt replaces the command prompt while a search term is typed
+const searchPrompt = "search: "
+
+// startSearch turns the prompt into a search prompt for command output.
+// The live view is redrawn every tick, so it isn't searchable.
+func (m Model) startSearch() Model {
+	if m.watchMode {
+		m.status = "Search works on command output, not the live view"
+		return m
+	}
+	m.searching = true
+	m.input.Reset()
+	m.input.Prompt = searchPrompt
+	m.input.Placeholder = "Text to find in this view, enter to search, esc to cancel"
+	m.status = "Search"
+	return m
+}
+
+// updateSearch handles a key while the search prompt is open
+func (m Model) updateSearch(msg tea.KeyMsg) (Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m = m.endSearchPrompt()
+		m.status = "Search cancelled"
+		return m, nil
+	case "enter":
+		term := m.input.Value()
+		m = m.endSearchPrompt()
+		return m.search(term), nil
+	}
+
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	return m, cmd
+}
+
+// endSearchPrompt gives the prompt back to commands
+func (m Model) endSearchPrompt() Model {
+	m.searching = false
+	m.input.Reset()
+	m.input.Prompt = "> "
+	m.input.Placeholder = commandPlaceholder
+	return m
+}
+
+// search highlights every occurrence of term in the current view,
+// ignoring case, and scrolls to the first line holding one
+func (m Model) search(term string) Model {
+	m = m.clearSearch()
+	if strings.TrimSpace(term) == "" {
+		m.status = "Ready"
+		return m
+	}
+
+	m.searchTerm = term
+	m = m.highlightMatches()
+	if len(m.matches) == 0 {
+		m.status = fmt.Sprintf("No matches for %q", term)
+		return m
+	}
+	return m.gotoMatch(0)
+}
+
+// nextMatch moves by delta through the matching lines, wrapping around
+func (m Model) nextMatch(delta int) Model {
+	n := len(m.matches)
+	return m.gotoMatch(((m.matchIndex+delta)%n + n) % n)
+}
+
+// gotoMatch scrolls the i-th matching line to the middle of the view
+func (m Model) gotoMatch(i int) Model {
+	m.matchIndex = i
+	m = m.highlightMatches()
+	m.viewport.SetYOffset(max(0, m.matches[i]-m.viewport.Height/2))
+	m.status = fmt.Sprintf("Match %d/%d for %q (n/N for next/previous)", i+1, len(m.matches), m.searchTerm)
+	return m
+}
+
+// highlightMatches finds the lines of the content that hold the search
+// term and shows the content with every occurrence highlighted, the
+// current line's more strongly. Matches are found in the text as seen,
+// without escape codes, and styled over the original colors.
+func (m Model) highlightMatches() Model {
+	pattern := regexp.MustCompile("(?i)" + regexp.QuoteMeta(m.searchTerm))
+	matchStyle := lipgloss.NewStyle().Foreground(theme.Yellow).Reverse(true)
+	currentStyle := lipgloss.NewStyle().Foreground(theme.Pink).Reverse(true).Bold(true)
+
+	lines := strings.Split(m.content, "\n")
+	m.matches = nil
+	for i, line := range lines {
+		plain := ansi.Strip(line)
+		found := pattern.FindAllStringIndex(plain, -1)
+		if found == nil {
+			continue
+		}
+
+		style := matchStyle
+		if len(m.matches) == m.matchIndex {
+			style = currentStyle
+		}
+		ranges := make([]lipgloss.Range, len(found))
+		for j, loc := range found {
+			start := ansi.StringWidth(plain[:loc[0]])
+			ranges[j] = lipgloss.NewRange(start, start+ansi.StringWidth(plain[loc[0]:loc[1]]), style)
+		}
+		lines[i] = lipgloss.StyleRanges(line, ranges...)
+		m.matches = append(m.matches, i)
+	}
+
+	m.viewport.SetContent(strings.Join(lines, "\n"))
+	m.matchIndex = min(m.matchIndex, max(len(m.matches)-1, 0))
+	return m
+}
+
+// clearSearch drops the search and its highlights, keeping the scroll
+// position
+func (m Model) clearSearch() Model {
+	if m.searchTerm == "" {
+		return m
+	}
+	m.searchTerm = ""
+	m.matches = nil
+	m.matchIndex = 0
+
+	offset := m.viewport.YOffset
+	m.viewport.SetContent(m.content)
+	m.viewport.SetYOffset(offset)
+	return m
+}
@@ -0,0 +1,47 @@
+package ui
+
+import (
+	"os"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// soundCooldown is the minimum gap between two sound cues, so a build
+// touching hundreds of files doesn't turn into a continuous ring
+const soundCooldown = 2 * time.Second
+
+// bellPatterns maps a file operation to the number of bells rung for it
+var bellPatterns = map[string]int{
+	"modified": 1,
+	"created":  2,
+	"renamed":  2,
+	"deleted":  3,
+}
+
+// soundEnabledFromEnv reports whether ARCSII_SOUND turns sound cues on
+func soundEnabledFromEnv() bool {
+	switch os.Getenv("ARCSII_SOUND") {
+	case "1", "true", "on", "yes":
+		return true
+	}
+	return false
+}
+
+// ringBells rings the terminal bell count times with a short pause
+// between rings so patterns are distinguishable by ear
+func ringBells(count int) tea.Cmd {
+	if count <= 0 {
+		return nil
+	}
+	return func() tea.Msg {
+		for i := 0; i < count; i++ {
+			if i > 0 {
+				time.Sleep(150 * time.Millisecond)
+			}
+			// Stderr keeps the bell out of the renderer's output stream
+			os.Stderr.WriteString("\a")
+		}
+		return nil
+	}
+}
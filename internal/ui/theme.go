@@ -0,0 +1,114 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/barisercan/arcsii/internal/renderer"
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// theme is the palette the UI's own styles were last built from
+var theme renderer.Theme
+
+// applyTheme makes t the active theme for the renderer and rebuilds the
+// UI styles from it
+func applyTheme(t renderer.Theme) {
+	renderer.SetTheme(t)
+	theme = t
+
+	titleStyle = lipgloss.NewStyle().
+		Bold(true).
+		Foreground(t.Pink).
+		Background(t.Bar).
+		Padding(0, 1)
+
+	inputStyle = lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(t.Cyan).
+		Padding(0, 1)
+
+	helpStyle = lipgloss.NewStyle().
+		Foreground(t.Faint).
+		Italic(true)
+
+	statusStyle = lipgloss.NewStyle().
+		Foreground(t.BarText).
+		Background(t.Bar).
+		Padding(0, 1)
+
+	createStyle = lipgloss.NewStyle().
+		Foreground(t.Green).
+		Bold(true)
+
+	modifyStyle = lipgloss.NewStyle().
+		Foreground(t.Amber).
+		Bold(true)
+
+	deleteStyle = lipgloss.NewStyle().
+		Foreground(t.Red).
+		Bold(true)
+
+	renameStyle = lipgloss.NewStyle().
+		Foreground(t.Violet).
+		Bold(true)
+
+	filePathStyle = lipgloss.NewStyle().
+		Foreground(t.Cyan)
+
+	timeStyle = lipgloss.NewStyle().
+		Foreground(t.Gray)
+}
+
+// styleInput colors the command prompt with the active theme
+func styleInput(ti *textinput.Model) {
+	ti.PromptStyle = lipgloss.NewStyle().Foreground(theme.Pink)
+	ti.TextStyle = lipgloss.NewStyle().Foreground(theme.Cyan).Bold(true)
+	ti.PlaceholderStyle = lipgloss.NewStyle().Foreground(theme.Faint)
+}
+
+// pulse picks an animation frame's color. Shaded themes step through
+// tints, lightest in the middle; others hold the theme's base color,
+// since pale tints vanish on a light background.
+func pulse(base lipgloss.TerminalColor, tints []string, frame int) lipgloss.TerminalColor {
+	if !theme.Shaded {
+		return base
+	}
+	return lipgloss.Color(tints[frame%len(tints)])
+}
+
+// themeCommand lists the themes, or switches to one and redraws the
+// current view with it
+func (m Model) themeCommand(args []string) Model {
+	if len(args) == 0 {
+		m.status = fmt.Sprintf("Theme: %s (available: %s)", theme.Name, strings.Join(renderer.ThemeNames(), ", "))
+		return m
+	}
+
+	t, ok := renderer.LookupTheme(args[0])
+	if !ok {
+		m.status = fmt.Sprintf("Unknown theme %q (available: %s)", args[0], strings.Join(renderer.ThemeNames(), ", "))
+		return m
+	}
+	applyTheme(t)
+	styleInput(&m.input)
+	m.renderCache.reset()
+
+	offset := m.viewport.YOffset
+	switch {
+	case m.watchMode:
+		m.content = m.renderLiveView()
+	case m.currentCmd == "/bookmarks":
+		m.content = m.renderBookmarks()
+	case m.tree != nil:
+		m.content, m.treeLines = renderer.RenderTreeInteractive(m.tree, m.collapsed)
+	default:
+		m.content, _ = m.cmdRegistry.Execute(m.currentCmd)
+	}
+	m.viewport.SetContent(m.content)
+	m.viewport.SetYOffset(offset)
+
+	m.status = "Theme: " + t.Name
+	return m
+}
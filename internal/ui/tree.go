@@ -0,0 +1,77 @@
+package ui
+
+import (
+	"path/filepath"
+
+	"github.com/barisercan/arcsii/internal/parser"
+	"github.com/barisercan/arcsii/internal/renderer"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// viewportTop is the screen row of the viewport's first line, below the
+// title and the blank line View puts after it
+const viewportTop = 2
+
+// clickTree handles a click on screen row y of an interactive /tree: a
+// directory folds or unfolds, a file opens its function sizes
+func (m Model) clickTree(y int) (Model, tea.Cmd) {
+	if m.tree == nil || m.watchMode {
+		return m, nil
+	}
+	row := y - viewportTop
+	if row < 0 || row >= m.viewport.Height {
+		return m, nil
+	}
+	line := m.viewport.YOffset + row
+	if line >= len(m.treeLines) || m.treeLines[line] == nil {
+		return m, nil
+	}
+
+	node := m.treeLines[line]
+	if !node.IsDir {
+		path := node.Path
+		if rel, err := filepath.Rel(m.targetDir, path); err == nil {
+			path = rel
+		}
+		return m.runCommand("/bloat " + path)
+	}
+
+	m.collapsed[node.Path] = !m.collapsed[node.Path]
+	if m.collapsed[node.Path] {
+		m.status = "Folded " + node.Name
+	} else {
+		m.status = "Unfolded " + node.Name
+	}
+	return m.redrawTree(), nil
+}
+
+// foldBelow marks the directories depth levels below node, and any
+// deeper ones on the way, as collapsed, so a /tree --depth view opens
+// as a map that unfolds on click. A depth of 0 folds nothing.
+func foldBelow(node *parser.FileNode, depth int, collapsed map[string]bool) {
+	if depth <= 0 {
+		return
+	}
+	for _, child := range node.Children {
+		if !child.IsDir {
+			continue
+		}
+		if depth == 1 {
+			collapsed[child.Path] = true
+		} else {
+			foldBelow(child, depth-1, collapsed)
+		}
+	}
+}
+
+// redrawTree re-renders the interactive tree, keeping the scroll position
+func (m Model) redrawTree() Model {
+	offset := m.viewport.YOffset
+	m.content, m.treeLines = renderer.RenderTreeInteractive(m.tree, m.collapsed)
+	m.viewport.SetContent(m.content)
+	if m.searchTerm != "" {
+		m = m.highlightMatches()
+	}
+	m.viewport.SetYOffset(offset)
+	return m
+}
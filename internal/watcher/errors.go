@@ -0,0 +1,42 @@
+package watcher
+
+import (
+	"errors"
+	"fmt"
+	"syscall"
+)
+
+var (
+	// ErrRootNotFound is returned by New when the root doesn't exist
+	ErrRootNotFound = errors.New("watch root does not exist")
+
+	// ErrNotADirectory is returned by New when the root is a file
+	ErrNotADirectory = errors.New("watch root is not a directory")
+
+	// ErrWatchLimitExceeded means the OS refused further watches, e.g.
+	// fs.inotify.max_user_watches on Linux or the open file limit on BSD
+	ErrWatchLimitExceeded = errors.New("watch limit exceeded")
+)
+
+// WatchError wraps a failure of the underlying fsnotify watcher
+type WatchError struct {
+	Op   string // "init" or "add"
+	Path string
+	Err  error
+}
+
+func (e *WatchError) Error() string {
+	if e.Path == "" {
+		return fmt.Sprintf("watch %s: %v", e.Op, e.Err)
+	}
+	return fmt.Sprintf("watch %s %s: %v", e.Op, e.Path, e.Err)
+}
+
+func (e *WatchError) Unwrap() error {
+	return e.Err
+}
+
+// isLimitError reports whether err means no more watches can be added
+func isLimitError(err error) bool {
+	return errors.Is(err, syscall.ENOSPC) || errors.Is(err, syscall.EMFILE)
+}
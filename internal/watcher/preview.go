@@ -0,0 +1,202 @@
+package watcher
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// PreviewLines is the most changed lines kept in FileEvent.Preview
+const PreviewLines = 5
+
+// maxCachedSize is the largest file whose contents are kept for diffing;
+// bigger files get no preview
+const maxCachedSize = 256 << 10
+
+// maxCacheTotal bounds the contents kept across all files. Once it's
+// reached, files seen for the first time aren't read at startup and
+// their first change gets no preview.
+const maxCacheTotal = 16 << 20
+
+// maxDiffCells bounds the line diff's table; past it the changed region
+// is shown as removed then added
+const maxDiffCells = 1 << 18
+
+// cacheContent remembers a text file's contents so a later write can be
+// diffed against them
+func (w *Watcher) cacheContent(path string, size int64) {
+	if size > maxCachedSize {
+		return
+	}
+	// Checked before reading, so a large tree isn't read only to be
+	// thrown away
+	w.contentsMu.Lock()
+	full := w.cachedBytes+int(size) > maxCacheTotal
+	w.contentsMu.Unlock()
+	if full {
+		return
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil || isBinary(data) {
+		return
+	}
+	w.swapContent(path, string(data), false)
+}
+
+// swapContent stores the new contents of path, or drops them when forget
+// is set or they don't fit in the cache, and returns the previous ones
+func (w *Watcher) swapContent(path, data string, forget bool) (string, bool) {
+	w.contentsMu.Lock()
+	defer w.contentsMu.Unlock()
+	old, ok := w.contents[path]
+	if ok {
+		w.cachedBytes -= len(old)
+		delete(w.contents, path)
+	}
+	if !forget && w.cachedBytes+len(data) <= maxCacheTotal {
+		w.contents[path] = data
+		w.cachedBytes += len(data)
+	}
+	return old, ok
+}
+
+// attachPreview fills in the lines added and removed by a non-git event,
+// comparing the file with its cached contents. New files are all
+// additions; binary and oversized files get no preview.
+func (w *Watcher) attachPreview(event *FileEvent) {
+	if event.IsGitOp {
+		return
+	}
+	path := w.absPath(event.Path)
+
+	var old string
+	var known bool
+	switch event.Operation {
+	case "deleted":
+		w.swapContent(path, "", true)
+		return
+	case "renamed":
+		if event.OldPath == "" {
+			w.swapContent(path, "", true)
+			return
+		}
+		old, known = w.swapContent(w.absPath(event.OldPath), "", true)
+	case "created":
+		known = true
+	case "modified":
+	default:
+		return
+	}
+
+	info, err := os.Stat(path)
+	if err != nil || info.IsDir() {
+		return
+	}
+	data, err := os.ReadFile(path)
+	if err != nil || info.Size() > maxCachedSize || isBinary(data) {
+		w.swapContent(path, "", true)
+		return
+	}
+
+	previous, cached := w.swapContent(path, string(data), false)
+	if event.Operation == "modified" {
+		old, known = previous, cached
+	}
+	if known {
+		event.Preview = diffPreview(old, string(data), PreviewLines)
+	}
+}
+
+// absPath turns an event path relative to the root back into a file path
+func (w *Watcher) absPath(path string) string {
+	if filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(w.root, path)
+}
+
+// diffPreview lists up to limit non-blank lines added ("+ ") or removed
+// ("- ") between two versions of a file, in file order
+func diffPreview(old, new string, limit int) []string {
+	a, b := splitLines(old), splitLines(new)
+
+	// Only the region between the common prefix and suffix changed
+	for len(a) > 0 && len(b) > 0 && a[0] == b[0] {
+		a, b = a[1:], b[1:]
+	}
+	for len(a) > 0 && len(b) > 0 && a[len(a)-1] == b[len(b)-1] {
+		a, b = a[:len(a)-1], b[:len(b)-1]
+	}
+
+	var preview []string
+	add := func(prefix, line string) bool {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			return true
+		}
+		line = ansi.Truncate(line, 60, "...")
+		preview = append(preview, prefix+line)
+		return len(preview) < limit
+	}
+
+	if (len(a)+1)*(len(b)+1) > maxDiffCells {
+		for _, line := range a {
+			if !add("- ", line) {
+				return preview
+			}
+		}
+		for _, line := range b {
+			if !add("+ ", line) {
+				return preview
+			}
+		}
+		return preview
+	}
+
+	// lcs[i][j] is the longest common subsequence of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			i, j = i+1, j+1
+		case j == len(b) || (i < len(a) && lcs[i+1][j] >= lcs[i][j+1]):
+			if !add("- ", a[i]) {
+				return preview
+			}
+			i++
+		default:
+			if !add("+ ", b[j]) {
+				return preview
+			}
+			j++
+		}
+	}
+	return preview
+}
+
+// splitLines splits file contents into lines, ignoring a byte order mark
+// and a trailing newline
+func splitLines(s string) []string {
+	s = strings.TrimSuffix(strings.TrimPrefix(s, "\uFEFF"), "\n")
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.ReplaceAll(s, "\r\n", "\n"), "\n")
+}
@@ -0,0 +1,47 @@
+package watcher
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestDiffPreviewTruncatesByWidth(t *testing.T) {
+	line := strings.Repeat("ü", 56) + "日本語"
+	preview := diffPreview("", line+"\n", PreviewLines)
+	if len(preview) != 1 {
+		t.Fatalf("got %d preview lines, want 1", len(preview))
+	}
+	got := strings.TrimPrefix(preview[0], "+ ")
+	if !utf8.ValidString(got) {
+		t.Errorf("truncated line isn't valid UTF-8: %q", got)
+	}
+	if w := ansi.StringWidth(got); w > 60 {
+		t.Errorf("truncated line is %d cells wide, want at most 60", w)
+	}
+	if !strings.HasSuffix(got, "...") {
+		t.Errorf("truncated line %q has no ellipsis", got)
+	}
+}
+
+func TestContentCacheStaysBounded(t *testing.T) {
+	w := &Watcher{contents: make(map[string]string)}
+	chunk := strings.Repeat("x", maxCachedSize)
+	for i := range maxCacheTotal/maxCachedSize + 10 {
+		w.swapContent(fmt.Sprintf("file%d.txt", i), chunk, false)
+	}
+	if w.cachedBytes > maxCacheTotal {
+		t.Errorf("cache holds %d bytes, want at most %d", w.cachedBytes, maxCacheTotal)
+	}
+
+	// Replacing and forgetting contents gives their space back
+	for path := range w.contents {
+		w.swapContent(path, "", true)
+	}
+	if w.cachedBytes != 0 || len(w.contents) != 0 {
+		t.Errorf("cache holds %d bytes in %d files after forgetting all", w.cachedBytes, len(w.contents))
+	}
+}
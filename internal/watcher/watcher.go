@@ -1,11 +1,18 @@
 package watcher
 
 import (
+	"errors"
+	"fmt"
+	"io/fs"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"slices"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/barisercan/arcsii/internal/parser"
 	"github.com/fsnotify/fsnotify"
 )
 
@@ -17,34 +24,96 @@ type FileEvent struct {
 	Time      time.Time
 	Size      int64
 	IsGitOp   bool
-	GitOp     string // "commit", "push", "pull", "merge", etc.
-	Preview   []string // Preview lines of the change
+	GitOp     string   // "commit", "push", "pull", "merge", "tag", etc.
+	Preview   []string // Changed lines, prefixed "+ " or "- "
+
+	// Destructive is set for history-rewriting ref updates (reset, amend,
+	// rebase, force push); GitDetail summarizes what moved. For a checkout
+	// it holds the new branch, or the short SHA of a detached HEAD, and
+	// for a tag the tag's name.
+	Destructive bool
+	GitDetail   string
+
+	// OldPath is the previous path of a "renamed" event when the rename
+	// was paired with its new name
+	OldPath string
 }
 
 // Watcher watches for file changes
 type Watcher struct {
-	watcher    *fsnotify.Watcher
-	root       string
-	Events     chan FileEvent
-	Errors     chan error
-	done       chan bool
-	WatchCount int // Number of directories being watched
+	watcher  *fsnotify.Watcher
+	root     string
+	Events   chan FileEvent
+	Errors   chan error
+	done     chan struct{}
+	stopOnce sync.Once
+	debounce time.Duration  // Window for coalescing events on one path
+	ignore   *parser.Ignore // The root's .arcsiignore, or nil
+
+	// Directories being watched. The event goroutine adds created ones
+	// and drops removed ones while WatchCount reads them.
+	watchedMu sync.Mutex
+	watched   map[string]bool
+
+	// Last known file sizes, used to pair the two halves of a rename.
+	// AddRoot can run while the event goroutine reads them.
+	sizesMu sync.Mutex
+	sizes   map[string]int64
+
+	// Last seen contents of text files, diffed against on each change,
+	// and their total size, kept under maxCacheTotal
+	contentsMu  sync.Mutex
+	contents    map[string]string
+	cachedBytes int
 }
 
-// New creates a new file watcher
+// DefaultDebounce is how long events for one path are coalesced, long
+// enough to cover the write/chmod/rename burst of an editor save
+const DefaultDebounce = 200 * time.Millisecond
+
+// RenameWindow is how long a removed file waits for a create of the
+// same size, which turns the pair into a single rename
+const RenameWindow = 300 * time.Millisecond
+
+// pendingEvent is an event held back until its debounce window closes
+type pendingEvent struct {
+	event    FileEvent
+	due      time.Time
+	vanished bool // A remove or rename-away that may pair with a create
+}
+
+// New creates a new file watcher. Errors match ErrRootNotFound or
+// ErrNotADirectory for a bad root, and are a *WatchError when fsnotify
+// can't be initialized. If the OS watch limit is reached part way, New
+// returns the watcher together with an error matching
+// ErrWatchLimitExceeded; it covers the directories added before that.
 func New(root string) (*Watcher, error) {
-	fsWatcher, err := fsnotify.NewWatcher()
+	info, err := os.Stat(root)
 	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("%w: %s", ErrRootNotFound, root)
+		}
 		return nil, err
 	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("%w: %s", ErrNotADirectory, root)
+	}
+
+	fsWatcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, &WatchError{Op: "init", Err: err}
+	}
 
 	w := &Watcher{
-		watcher:    fsWatcher,
-		root:       root,
-		Events:     make(chan FileEvent, 100),
-		Errors:     make(chan error, 10),
-		done:       make(chan bool),
-		WatchCount: 0,
+		watcher:  fsWatcher,
+		root:     root,
+		Events:   make(chan FileEvent, 100),
+		Errors:   make(chan error, 10),
+		done:     make(chan struct{}),
+		debounce: DefaultDebounce,
+		sizes:    make(map[string]int64),
+		contents: make(map[string]string),
+		watched:  make(map[string]bool),
 	}
 
 	// Get absolute path
@@ -53,12 +122,55 @@ func New(root string) (*Watcher, error) {
 		absRoot = root
 	}
 	w.root = absRoot
+	w.ignore = parser.LoadArcsiIgnore(absRoot)
 
 	// Add all directories recursively
-	err = filepath.Walk(absRoot, func(path string, info os.FileInfo, err error) error {
+	limitErr := w.addTree(absRoot)
+
+	// Explicitly watch key .git directories for git operations
+	gitDirs := []string{
+		filepath.Join(absRoot, ".git"),
+		filepath.Join(absRoot, ".git", "refs"),
+		filepath.Join(absRoot, ".git", "refs", "heads"),
+		filepath.Join(absRoot, ".git", "refs", "remotes"),
+		filepath.Join(absRoot, ".git", "refs", "tags"),
+		filepath.Join(absRoot, ".git", "logs"),
+		filepath.Join(absRoot, ".git", "logs", "refs"),
+		filepath.Join(absRoot, ".git", "logs", "refs", "heads"),
+	}
+	for _, dir := range gitDirs {
+		if info, err := os.Stat(dir); err == nil && info.IsDir() {
+			fsWatcher.Add(dir)
+		}
+	}
+
+	return w, limitErr
+}
+
+// AddRoot watches another directory tree, such as a workspace member
+// outside the root. Errors match ErrNotADirectory, or
+// ErrWatchLimitExceeded if only part of the tree could be watched.
+func (w *Watcher) AddRoot(dir string) error {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("%w: %s", ErrNotADirectory, dir)
+	}
+	return w.addTree(dir)
+}
+
+// addTree adds every directory under dir, stopping at the OS watch limit
+func (w *Watcher) addTree(dir string) error {
+	var limitErr error
+	filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			return nil
 		}
+		if skip, result := w.ignore.Skip(path, info); skip {
+			return result
+		}
 
 		name := info.Name()
 		// Skip common ignore patterns but NOT .git (we want to watch it for git ops)
@@ -81,42 +193,109 @@ func New(root string) (*Watcher, error) {
 		}
 
 		if info.IsDir() {
-			if err := fsWatcher.Add(path); err == nil {
-				w.WatchCount++
+			if err := w.watcher.Add(path); err == nil {
+				w.watchedMu.Lock()
+				w.watched[path] = true
+				w.watchedMu.Unlock()
+			} else if isLimitError(err) {
+				limitErr = &WatchError{Op: "add", Path: path, Err: fmt.Errorf("%w: %w", ErrWatchLimitExceeded, err)}
+				return filepath.SkipAll
 			}
+		} else if !inGitDir {
+			w.setSize(path, info.Size())
+			w.cacheContent(path, info.Size())
 		}
 		return nil
 	})
+	return limitErr
+}
 
-	if err != nil {
-		fsWatcher.Close()
-		return nil, err
+// unwatchTree stops watching dir and every watched directory below it,
+// after dir was removed or renamed away
+func (w *Watcher) unwatchTree(dir string) {
+	w.watchedMu.Lock()
+	defer w.watchedMu.Unlock()
+	prefix := dir + string(filepath.Separator)
+	for path := range w.watched {
+		if path == dir || strings.HasPrefix(path, prefix) {
+			delete(w.watched, path)
+			w.watcher.Remove(path) // Already gone if the OS dropped it
+		}
 	}
+}
 
-	// Explicitly watch key .git directories for git operations
-	gitDirs := []string{
-		filepath.Join(absRoot, ".git"),
-		filepath.Join(absRoot, ".git", "refs"),
-		filepath.Join(absRoot, ".git", "refs", "heads"),
-		filepath.Join(absRoot, ".git", "refs", "remotes"),
-		filepath.Join(absRoot, ".git", "logs"),
-		filepath.Join(absRoot, ".git", "logs", "refs"),
-		filepath.Join(absRoot, ".git", "logs", "refs", "heads"),
-	}
-	for _, dir := range gitDirs {
-		if info, err := os.Stat(dir); err == nil && info.IsDir() {
-			fsWatcher.Add(dir)
-		}
+// WatchCount returns the number of directories being watched, which
+// follows directories created and removed since New
+func (w *Watcher) WatchCount() int {
+	w.watchedMu.Lock()
+	defer w.watchedMu.Unlock()
+	return len(w.watched)
+}
+
+// ignored reports whether .arcsiignore excludes path
+func (w *Watcher) ignored(path string) bool {
+	if w.ignore == nil {
+		return false
 	}
+	info, err := os.Stat(path)
+	return w.ignore.Match(path, err == nil && info.IsDir())
+}
 
-	return w, nil
+// SetDebounce sets the window in which events for the same path are
+// coalesced into one, reporting the last operation. Zero disables it.
+// Call it before Start.
+func (w *Watcher) SetDebounce(d time.Duration) {
+	w.debounce = max(d, 0)
 }
 
-// Start begins watching for file changes
+// Start begins watching for file changes. Events is closed once the
+// watcher stops.
 func (w *Watcher) Start() {
 	go func() {
+		// Closing Events lets listeners know the watcher is gone
+		defer close(w.Events)
+
+		// Events waiting out their debounce window, in arrival order
+		pending := make(map[string]*pendingEvent)
+		var order []string
+		var flush <-chan time.Time
+
+		// emit delivers an event, giving up once Stop has been called.
+		// The preview is taken here so a coalesced burst is diffed once.
+		emit := func(event FileEvent) bool {
+			w.attachPreview(&event)
+			select {
+			case w.Events <- event:
+				return true
+			case <-w.done:
+				return false
+			}
+		}
+
 		for {
 			select {
+			case <-flush:
+				now := time.Now()
+				var waiting []string
+				for _, path := range order {
+					p := pending[path]
+					if p.due.After(now) {
+						waiting = append(waiting, path)
+						continue
+					}
+					delete(pending, path)
+					// No create took the other half of the rename, so as
+					// far as the tree goes the file is gone
+					if p.vanished && p.event.Operation == "renamed" {
+						p.event.Operation = "deleted"
+					}
+					if !emit(p.event) {
+						return
+					}
+				}
+				order = waiting
+				flush = nextFlush(pending)
+
 			case event, ok := <-w.watcher.Events:
 				if !ok {
 					return
@@ -140,22 +319,32 @@ func (w *Watcher) Start() {
 					}
 				} else if strings.HasPrefix(name, ".") {
 					continue // Skip other hidden files
+				} else if w.ignored(event.Name) {
+					continue
 				}
 
 				var op string
 				switch {
 				case event.Op&fsnotify.Create == fsnotify.Create:
 					op = "created"
-					// If it's a new directory, watch it
+					// Watch a new directory along with anything already in
+					// it, as after an unzip or a move into the tree
 					if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
-						w.watcher.Add(event.Name)
+						if err := w.addTree(event.Name); err != nil {
+							select {
+							case w.Errors <- err:
+							default: // Nobody is reading errors
+							}
+						}
 					}
 				case event.Op&fsnotify.Write == fsnotify.Write:
 					op = "modified"
 				case event.Op&fsnotify.Remove == fsnotify.Remove:
 					op = "deleted"
+					w.unwatchTree(event.Name)
 				case event.Op&fsnotify.Rename == fsnotify.Rename:
 					op = "renamed"
+					w.unwatchTree(event.Name)
 				case event.Op&fsnotify.Chmod == fsnotify.Chmod:
 					continue // Skip chmod events
 				default:
@@ -163,8 +352,19 @@ func (w *Watcher) Start() {
 				}
 
 				var size int64
+				isDir, sizeKnown := false, true
 				if info, err := os.Stat(event.Name); err == nil {
-					size = info.Size()
+					size, isDir = info.Size(), info.IsDir()
+					if !isDir && !isGitOp {
+						w.setSize(event.Name, size)
+					}
+				} else if op == "deleted" || op == "renamed" {
+					size, sizeKnown = w.takeSize(event.Name)
+				}
+
+				// A new directory under refs/tags is a namespace, not a tag
+				if gitOp == "tag" && isDir {
+					continue
 				}
 
 				rel, _ := filepath.Rel(w.root, event.Name)
@@ -172,13 +372,20 @@ func (w *Watcher) Start() {
 					rel = event.Name
 				}
 
-				// Get preview for non-git file changes
-				var preview []string
-				if !isGitOp && (op == "modified" || op == "created") {
-					preview = getFilePreview(event.Name, 3)
+				// Check branch reflogs for history rewrites
+				var destructive bool
+				var gitDetail string
+				if isGitOp && op != "deleted" {
+					gitDetail, destructive = analyzeReflog(w.root, event.Name)
+				}
+				switch gitOp {
+				case "checkout":
+					gitDetail = readHead(event.Name)
+				case "tag":
+					gitDetail = tagName(event.Name)
 				}
 
-				w.Events <- FileEvent{
+				fileEvent := FileEvent{
 					Path:      rel,
 					Name:      name,
 					Operation: op,
@@ -186,14 +393,78 @@ func (w *Watcher) Start() {
 					Size:      size,
 					IsGitOp:   isGitOp,
 					GitOp:     gitOp,
-					Preview:   preview,
+
+					Destructive: destructive,
+					GitDetail:   gitDetail,
+				}
+
+				// Hold removes back briefly: a create of a same-sized file
+				// right after is the other half of a rename. One that can't
+				// be paired, without a known size, is a delete.
+				if !isGitOp && !sizeKnown && op == "renamed" {
+					fileEvent.Operation = "deleted"
+				}
+				if !isGitOp && sizeKnown && (op == "deleted" || op == "renamed") {
+					if p, ok := pending[event.Name]; ok {
+						p.event, p.vanished = fileEvent, true
+						p.due = later(p.due, time.Now().Add(RenameWindow))
+					} else {
+						pending[event.Name] = &pendingEvent{event: fileEvent, due: time.Now().Add(RenameWindow), vanished: true}
+						order = append(order, event.Name)
+					}
+					flush = nextFlush(pending)
+					continue
 				}
 
+				if !isGitOp && op == "created" && !isDir {
+					if p, ok := pending[event.Name]; ok && p.vanished {
+						// Replaced in place, as editors do when saving
+						fileEvent.Operation = "modified"
+						p.vanished = false
+					} else if old := pairRename(pending, order, size); old != "" {
+						fileEvent.Operation = "renamed"
+						fileEvent.OldPath = pending[old].event.Path
+						delete(pending, old)
+						order = slices.DeleteFunc(order, func(path string) bool { return path == old })
+					}
+				}
+
+				if w.debounce == 0 {
+					if _, ok := pending[event.Name]; ok {
+						delete(pending, event.Name)
+						order = slices.DeleteFunc(order, func(path string) bool { return path == event.Name })
+						flush = nextFlush(pending)
+					}
+					if !emit(fileEvent) {
+						return
+					}
+					continue
+				}
+
+				// Coalesce with an event already waiting for this path; the
+				// window runs from the first event so a steady stream of
+				// writes still shows up. A rename stays a rename when the
+				// new file is then written.
+				if p, ok := pending[event.Name]; ok {
+					if p.event.Operation == "renamed" && fileEvent.Operation == "modified" {
+						fileEvent.Operation, fileEvent.OldPath = "renamed", p.event.OldPath
+					}
+					p.event = fileEvent
+					continue
+				}
+				pending[event.Name] = &pendingEvent{event: fileEvent, due: time.Now().Add(w.debounce)}
+				order = append(order, event.Name)
+				flush = nextFlush(pending)
+
 			case err, ok := <-w.watcher.Errors:
 				if !ok {
 					return
 				}
-				w.Errors <- err
+				select {
+				case w.Errors <- err:
+				case <-w.done:
+					return
+				}
 
 			case <-w.done:
 				return
@@ -202,40 +473,62 @@ func (w *Watcher) Start() {
 	}()
 }
 
-// Stop stops the watcher
-func (w *Watcher) Stop() {
-	w.done <- true
-	w.watcher.Close()
+// pairRename finds the oldest held remove of a file with the given size
+// and returns its key in pending, or ""
+func pairRename(pending map[string]*pendingEvent, order []string, size int64) string {
+	for _, path := range order {
+		if p := pending[path]; p.vanished && p.event.Size == size {
+			return path
+		}
+	}
+	return ""
 }
 
-// getFilePreview reads the last few lines of a file for preview
-func getFilePreview(path string, numLines int) []string {
-	data, err := os.ReadFile(path)
-	if err != nil {
+// nextFlush returns a timer channel for the earliest pending event, or
+// nil when nothing is waiting
+func nextFlush(pending map[string]*pendingEvent) <-chan time.Time {
+	var next time.Time
+	for _, p := range pending {
+		if next.IsZero() || p.due.Before(next) {
+			next = p.due
+		}
+	}
+	if next.IsZero() {
 		return nil
 	}
+	return time.After(time.Until(next))
+}
 
-	// Skip binary files
-	if isBinary(data) {
-		return []string{"[binary file]"}
+func later(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
 	}
+	return b
+}
 
-	lines := strings.Split(string(data), "\n")
+func (w *Watcher) setSize(path string, size int64) {
+	w.sizesMu.Lock()
+	w.sizes[path] = size
+	w.sizesMu.Unlock()
+}
 
-	// Get last N non-empty lines
-	var preview []string
-	for i := len(lines) - 1; i >= 0 && len(preview) < numLines; i-- {
-		line := strings.TrimSpace(lines[i])
-		if line != "" {
-			// Truncate long lines
-			if len(line) > 60 {
-				line = line[:57] + "..."
-			}
-			preview = append([]string{line}, preview...)
-		}
-	}
+// takeSize returns and forgets the last known size of a removed file
+func (w *Watcher) takeSize(path string) (int64, bool) {
+	w.sizesMu.Lock()
+	defer w.sizesMu.Unlock()
+	size, ok := w.sizes[path]
+	delete(w.sizes, path)
+	return size, ok
+}
 
-	return preview
+// Stop stops the watcher. It never blocks, even if the event goroutine
+// has already exited or was never started, and is safe to call more than
+// once.
+func (w *Watcher) Stop() {
+	w.stopOnce.Do(func() {
+		close(w.done)
+		w.watcher.Close()
+	})
 }
 
 // isBinary checks if data appears to be binary
@@ -263,6 +556,11 @@ func detectGitOperation(path, name string) string {
 		return "commit"
 	}
 
+	// Tags created or deleted under refs/tags
+	if strings.Contains(path, "refs/tags") {
+		return "tag"
+	}
+
 	// refs/heads changes indicate a commit was made
 	if strings.Contains(path, "refs/heads") || strings.Contains(path, "logs/refs/heads") {
 		return "commit"
@@ -301,3 +599,82 @@ func detectGitOperation(path, name string) string {
 
 	return "" // Not an interesting git operation
 }
+
+// readHead returns the branch a HEAD file points at, or the short SHA of
+// a detached HEAD
+func readHead(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	head := strings.TrimSpace(string(data))
+	if ref, ok := strings.CutPrefix(head, "ref: "); ok {
+		return strings.TrimPrefix(ref, "refs/heads/")
+	}
+	return shortSHA(head)
+}
+
+// tagName returns the name of the tag a refs/tags path belongs to, e.g.
+// "release/v1.2" for .git/refs/tags/release/v1.2
+func tagName(path string) string {
+	slashed := filepath.ToSlash(path)
+	if _, name, ok := strings.Cut(slashed, ".git/refs/tags/"); ok {
+		return name
+	}
+	return ""
+}
+
+// analyzeReflog inspects the newest entry of a branch or remote-tracking
+// reflog and reports whether it rewrote history, as a rebase, amend or
+// forced update does: the old tip is no longer an ancestor of the new one.
+// A reset that only moves the branch forward is not a rewrite.
+func analyzeReflog(root, path string) (string, bool) {
+	slashed := filepath.ToSlash(path)
+	idx := strings.Index(slashed, ".git/logs/refs/")
+	if idx < 0 {
+		return "", false
+	}
+	ref := strings.TrimPrefix(slashed[idx:], ".git/logs/refs/")
+	ref = strings.TrimPrefix(strings.TrimPrefix(ref, "heads/"), "remotes/")
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", false
+	}
+	lines := strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+	last := lines[len(lines)-1]
+
+	header, message, _ := strings.Cut(last, "\t")
+	fields := strings.Fields(header)
+	if len(fields) < 2 {
+		return "", false
+	}
+	oldSHA, newSHA := fields[0], fields[1]
+	if strings.Trim(oldSHA, "0") == "" || strings.Trim(newSHA, "0") == "" {
+		return "", false // Branch created or deleted
+	}
+	if oldSHA == newSHA {
+		return "", false
+	}
+
+	if message == "" {
+		message = "ref updated"
+	}
+	detail := fmt.Sprintf("%s: %s → %s (%s)", ref, shortSHA(oldSHA), shortSHA(newSHA), message)
+
+	// Non-fast-forward: the old tip is no longer reachable from the new one
+	cmd := exec.Command("git", "-C", root, "merge-base", "--is-ancestor", oldSHA, newSHA)
+	if err := cmd.Run(); err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == 1 {
+			return detail, true
+		}
+	}
+	return detail, false
+}
+
+func shortSHA(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
+}
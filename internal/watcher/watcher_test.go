@@ -0,0 +1,133 @@
+package watcher
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStopTwice(t *testing.T) {
+	w, err := New(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.Start()
+	w.Stop()
+
+	done := make(chan struct{})
+	go func() {
+		w.Stop()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("second Stop blocked")
+	}
+}
+
+// nextEvent waits for the next event on path
+func nextEvent(t *testing.T, w *Watcher, path string) FileEvent {
+	t.Helper()
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case event := <-w.Events:
+			if event.Path == path {
+				return event
+			}
+		case <-timeout:
+			t.Fatalf("no event for %s", path)
+		}
+	}
+}
+
+func TestRenameAwayIsDeleted(t *testing.T) {
+	dir, outside := t.TempDir(), t.TempDir()
+	writeFile(t, filepath.Join(dir, "old.txt"), "contents")
+	writeFile(t, filepath.Join(dir, "moved.txt"), "moved")
+
+	w, err := New(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.Start()
+	defer w.Stop()
+
+	// Moved out of the tree: nothing is created to pair with
+	if err := os.Rename(filepath.Join(dir, "old.txt"), filepath.Join(outside, "old.txt")); err != nil {
+		t.Fatal(err)
+	}
+	if event := nextEvent(t, w, "old.txt"); event.Operation != "deleted" || event.OldPath != "" {
+		t.Errorf("moving old.txt away gave %q (from %q), want deleted", event.Operation, event.OldPath)
+	}
+
+	// Moved within the tree: still a rename
+	if err := os.Rename(filepath.Join(dir, "moved.txt"), filepath.Join(dir, "new.txt")); err != nil {
+		t.Fatal(err)
+	}
+	if event := nextEvent(t, w, "new.txt"); event.Operation != "renamed" || event.OldPath != "moved.txt" {
+		t.Errorf("renaming moved.txt gave %q (from %q), want renamed from moved.txt", event.Operation, event.OldPath)
+	}
+}
+
+func TestAnalyzeReflogFlagsOnlyRewrites(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	dir := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-C", dir, "-c", "user.name=t", "-c", "user.email=t@t"}, args...)...)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	git("init", "-q", "-b", "main")
+	for _, msg := range []string{"one", "two"} {
+		writeFile(t, filepath.Join(dir, "f.txt"), msg)
+		git("add", "f.txt")
+		git("commit", "-q", "-m", msg)
+	}
+	reflog := filepath.Join(dir, ".git", "logs", "refs", "heads", "main")
+
+	steps := []struct {
+		args        []string
+		destructive bool
+	}{
+		{[]string{"reset", "-q", "--hard", "HEAD~1"}, true},
+		{[]string{"reset", "-q", "--hard", "HEAD@{1}"}, false}, // Back forward again
+		{[]string{"commit", "-q", "--amend", "-m", "reworded"}, true},
+	}
+	for _, step := range steps {
+		git(step.args...)
+		if _, destructive := analyzeReflog(dir, reflog); destructive != step.destructive {
+			t.Errorf("git %v: destructive = %v, want %v", step.args, destructive, step.destructive)
+		}
+	}
+
+	// A reset to the current tip logs an entry that moves nothing
+	data, err := os.ReadFile(reflog)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+	tip := strings.Fields(lines[len(lines)-1])[1]
+	entry := tip + " " + tip + " t <t@t> 0 +0000\treset: moving to HEAD\n"
+	if err := os.WriteFile(reflog, append(data, entry...), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if detail, destructive := analyzeReflog(dir, reflog); destructive || detail != "" {
+		t.Errorf("unchanged tip = %q, %v, want no report", detail, destructive)
+	}
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -1,18 +1,75 @@
 package main
 
 import (
+	"bufio"
+	"encoding/json"
+	"flag"
 	"fmt"
 	"os"
+	"strings"
 
+	"github.com/barisercan/arcsii/internal/commands"
+	"github.com/barisercan/arcsii/internal/renderer"
 	"github.com/barisercan/arcsii/internal/ui"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/muesli/termenv"
 )
 
 func main() {
+	once := flag.Bool("once", false, "Run a single command, print its output and exit")
+	format := flag.String("format", "text", "Output format for --once: text, json or prom")
+	dir := flag.String("dir", ".", "Project directory for --once")
+	noColor := flag.Bool("no-color", os.Getenv("NO_COLOR") != "", "Disable colors, as a set NO_COLOR does; --once output is plain text")
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage:\n  arcsii [dir]\n  arcsii --once [--dir path] [--format text|json|prom] <command> [args]\n  arcsii [--format text|json|prom] --<command> [dir] [args]\n\nFlags:\n")
+		flag.PrintDefaults()
+	}
+
+	// A --<command> flag, such as --tree, is --once shorthand; what
+	// follows it is the command's, not arcsii's
+	command, commandArgs := commandFlag(os.Args[1:])
+	if command != "" {
+		flag.CommandLine.Parse(os.Args[1 : len(os.Args)-len(commandArgs)-1])
+	} else {
+		flag.Parse()
+	}
+
+	if name := os.Getenv("ARCSII_THEME"); name != "" {
+		if theme, ok := renderer.LookupTheme(name); ok {
+			renderer.SetTheme(theme)
+		} else {
+			fmt.Fprintf(os.Stderr, "arcsii: unknown ARCSII_THEME %q (%s)\n", name, strings.Join(renderer.ThemeNames(), ", "))
+		}
+	}
+
+	// Before anything renders: lipgloss drops colors under the ASCII
+	// profile, and the mono theme keeps views legible without them
+	if *noColor {
+		lipgloss.SetColorProfile(termenv.Ascii)
+		mono, _ := renderer.LookupTheme("mono")
+		renderer.SetTheme(mono)
+	}
+
+	if command != "" {
+		// An existing directory right after the command is the project
+		projectDir := "."
+		if len(commandArgs) > 0 {
+			if info, err := os.Stat(commandArgs[0]); err == nil && info.IsDir() {
+				projectDir, commandArgs = commandArgs[0], commandArgs[1:]
+			}
+		}
+		os.Exit(runOnce(projectDir, *format, *noColor, append([]string{command}, commandArgs...)))
+	}
+	if *once {
+		os.Exit(runOnce(*dir, *format, *noColor, flag.Args()))
+	}
+
 	// Get the target directory (current dir or specified)
 	targetDir := "."
-	if len(os.Args) > 1 {
-		targetDir = os.Args[1]
+	if flag.NArg() > 0 {
+		targetDir = flag.Arg(0)
 	}
 
 	p := tea.NewProgram(
@@ -26,3 +83,167 @@ func main() {
 		os.Exit(1)
 	}
 }
+
+// commandFlag finds the first --<command> argument, such as --tree or
+// --stats, naming a registry command rather than a flag. It returns the
+// command and the arguments after it, or "" when there is none. --help
+// stays the flag usage.
+func commandFlag(args []string) (string, []string) {
+	takesValue := false // The previous argument was a flag wanting a value
+
+	// Built only once a --flag isn't one of arcsii's own
+	var registry *commands.Registry
+	for i, arg := range args {
+		if takesValue {
+			takesValue = false
+			continue
+		}
+		if arg == "--" || !strings.HasPrefix(arg, "-") {
+			return "", nil // Flags end at the first argument
+		}
+
+		name, _, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		if f := flag.Lookup(name); f != nil {
+			boolFlag, ok := f.Value.(interface{ IsBoolFlag() bool })
+			takesValue = !hasValue && !(ok && boolFlag.IsBoolFlag())
+			continue
+		}
+		if !strings.HasPrefix(arg, "--") || name == "help" || hasValue {
+			continue
+		}
+		if registry == nil {
+			registry = commands.NewRegistry(".")
+		}
+		if registry.Has(name) {
+			return name, args[i+1:]
+		}
+	}
+	return "", nil
+}
+
+// runOnce executes one command without the TUI and prints its output,
+// returning the process exit code: 1 when the command fails, 2 when it
+// can't be run or, under --format json, has no JSON form. With noColor
+// the output is plain text.
+func runOnce(dir, format string, noColor bool, args []string) int {
+	if len(args) == 0 {
+		flag.Usage()
+		return 2
+	}
+
+	registry := commands.NewRegistry(dir)
+	if !registry.Has(args[0]) {
+		fmt.Fprintf(os.Stderr, "arcsii: unknown command %q\n", args[0])
+		return 2
+	}
+
+	command := strings.Join(args, " ")
+	switch format {
+	case "text":
+	case "prom", "prometheus":
+		name := strings.TrimPrefix(args[0], "/")
+		if name != "stats" && name != "metrics" && name != "prom" {
+			fmt.Fprintf(os.Stderr, "arcsii: --format %s only applies to stats\n", format)
+			return 2
+		}
+		command = "prom"
+	case "json":
+		if !registry.JSON(args[0]) {
+			fmt.Fprintf(os.Stderr, "arcsii: %s has no JSON output\n", args[0])
+			return 2
+		}
+		// Views take json after their own arguments, as in "file main.go json"
+		command += " json"
+	default:
+		fmt.Fprintf(os.Stderr, "arcsii: unknown format %q (text, json or prom)\n", format)
+		return 2
+	}
+
+	// Text streams as the view renders; JSON and metrics are checked whole
+	if format == "text" {
+		w := &onceWriter{noColor: noColor}
+		registry.ExecuteTo(w, command)
+		if w.finish() {
+			return 1
+		}
+		return 0
+	}
+
+	out, _ := registry.Execute(command)
+	if noColor {
+		out = ansi.Strip(out)
+	}
+	if !strings.HasSuffix(out, "\n") {
+		out += "\n"
+	}
+	// Errors and usage go to stderr, so scripts see the failure
+	if commands.Failed(out) {
+		fmt.Fprint(os.Stderr, out)
+		return 1
+	}
+	// Some modes, such as "deps dot", have no JSON form of their own
+	if format == "json" && !json.Valid([]byte(out)) {
+		fmt.Fprintf(os.Stderr, "arcsii: %s has no JSON output\n", strings.Join(args, " "))
+		return 2
+	}
+	fmt.Print(out)
+	return 0
+}
+
+// onceWriter streams --once output to stdout, or to stderr when it starts
+// as an error or usage message does, optionally without ANSI styling
+type onceWriter struct {
+	noColor bool
+	head    []byte        // Output held back until it shows whether it failed
+	out     *bufio.Writer // Where output goes once head has decided it
+	failed  bool          // Output went to stderr
+	last    byte          // Last byte written, to end the output with a newline
+}
+
+func (o *onceWriter) Write(p []byte) (int, error) {
+	n := len(p)
+	if o.noColor {
+		p = []byte(ansi.Strip(string(p)))
+	}
+	if len(p) == 0 {
+		return n, nil
+	}
+	o.last = p[len(p)-1]
+
+	if o.out == nil {
+		o.head = append(o.head, p...)
+		if len(o.head) < len("Error:") {
+			return n, nil
+		}
+		o.choose()
+		p, o.head = o.head, nil
+	}
+	if _, err := o.out.Write(p); err != nil {
+		return 0, err
+	}
+	return n, nil
+}
+
+// choose sends the output to stderr if head is an error or usage message
+func (o *onceWriter) choose() {
+	o.failed = commands.Failed(string(o.head))
+	if o.failed {
+		o.out = bufio.NewWriter(os.Stderr)
+	} else {
+		o.out = bufio.NewWriter(os.Stdout)
+	}
+}
+
+// finish writes any held-back output, ends it with a newline and reports
+// whether the command failed
+func (o *onceWriter) finish() bool {
+	if o.out == nil {
+		o.choose()
+		o.out.Write(o.head)
+	}
+	if o.last != '\n' {
+		o.out.WriteByte('\n')
+	}
+	o.out.Flush()
+	return o.failed
+}